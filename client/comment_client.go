@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/common/commentpb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// idempotentMethods are retried on UNAVAILABLE. Every CommentService method is
+// a read, add new methods here only if they are safe to call twice.
+var idempotentMethods = []string{
+	"GetProductRatingSummary",
+	"BatchGetProductRatings",
+	"ListReviewsByProduct",
+	"GetReview",
+}
+
+// CommentClient is a typed client of CommentService bound to one target.
+// It is safe for concurrent use.
+type CommentClient struct {
+	config *GRpcClientConfig
+	conn   *grpc.ClientConn
+	stub   commentpb.CommentServiceClient
+}
+
+// NewCommentClient creates a client of the target in config. The connection is
+// established lazily on the first call. Callers own the client and must Close it.
+// Calls carry the trace of their context in W3C trace context headers, and
+// are traced by the caller's global tracer provider.
+func NewCommentClient(config *GRpcClientConfig) (*CommentClient, error) {
+	if config == nil || config.Host == "" || config.Port <= 0 {
+		return nil, fmt.Errorf("invalid comment client config: %+v", config)
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(1024*1024),
+			grpc.MaxCallSendMsgSize(1024*1024),
+		),
+		grpc.WithDefaultServiceConfig(retryServiceConfig(config.maxAttempts())),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                config.keepaliveTime(),
+			Timeout:             config.keepaliveTimeout(),
+			PermitWithoutStream: true,
+		}),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithPropagators(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})),
+		)),
+	}
+	conn, err := grpc.NewClient(config.Target(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create comment client for %s: %w", config.Target(), err)
+	}
+	return &CommentClient{
+		config: config,
+		conn:   conn,
+		stub:   commentpb.NewCommentServiceClient(conn),
+	}, nil
+}
+
+// retryServiceConfig builds the gRPC service config that retries the
+// idempotent methods with exponential backoff.
+func retryServiceConfig(maxAttempts int) string {
+	if maxAttempts < 2 {
+		return `{}`
+	}
+	names := make([]string, len(idempotentMethods))
+	for idx, method := range idempotentMethods {
+		names[idx] = fmt.Sprintf(`{"service":"commentpb.CommentService","method":%q}`, method)
+	}
+	return fmt.Sprintf(`{"methodConfig":[{"name":[%s],"retryPolicy":{"maxAttempts":%d,"initialBackoff":"0.1s","maxBackoff":"1s","backoffMultiplier":2,"retryableStatusCodes":["UNAVAILABLE"]}}]}`,
+		strings.Join(names, ","), maxAttempts)
+}
+
+// withTimeout applies the configured deadline of method unless ctx already has one.
+func (c *CommentClient) withTimeout(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.config.timeout(method))
+}
+
+func (c *CommentClient) GetProductRatingSummary(ctx context.Context, productID int32) (*commentpb.RatingSummary, error) {
+	ctx, cancel := c.withTimeout(ctx, "GetProductRatingSummary")
+	defer cancel()
+	resp, err := c.stub.GetProductRatingSummary(ctx, &commentpb.GetProductRatingSummaryRequest{ProductId: productID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetSummary(), nil
+}
+
+// BatchGetProductRatings returns the rating summaries keyed by product id.
+func (c *CommentClient) BatchGetProductRatings(ctx context.Context, productIDs []int32) (map[int32]*commentpb.RatingSummary, error) {
+	ctx, cancel := c.withTimeout(ctx, "BatchGetProductRatings")
+	defer cancel()
+	resp, err := c.stub.BatchGetProductRatings(ctx, &commentpb.BatchGetProductRatingsRequest{ProductIds: productIDs})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetSummaries(), nil
+}
+
+func (c *CommentClient) ListReviewsByProduct(ctx context.Context, req *commentpb.ListReviewsByProductRequest) (*commentpb.ListReviewsByProductResponse, error) {
+	ctx, cancel := c.withTimeout(ctx, "ListReviewsByProduct")
+	defer cancel()
+	return c.stub.ListReviewsByProduct(ctx, req)
+}
+
+func (c *CommentClient) GetReview(ctx context.Context, reviewID string) (*commentpb.Review, error) {
+	ctx, cancel := c.withTimeout(ctx, "GetReview")
+	defer cancel()
+	resp, err := c.stub.GetReview(ctx, &commentpb.GetReviewRequest{ReviewId: reviewID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetReview(), nil
+}
+
+// Close closes the underlying connection.
+func (c *CommentClient) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/common/commentpb"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeCommentServer struct {
+	commentpb.UnimplementedCommentServiceServer
+	calls       atomic.Int32
+	failFirst   int32
+	handleDelay time.Duration
+	traceparent atomic.Value // of the last call
+}
+
+func (s *fakeCommentServer) GetReview(ctx context.Context, in *commentpb.GetReviewRequest) (*commentpb.GetReviewResponse, error) {
+	n := s.calls.Add(1)
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.traceparent.Store(strings.Join(md.Get("traceparent"), ","))
+	if n <= s.failFirst {
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+	select {
+	case <-time.After(s.handleDelay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &commentpb.GetReviewResponse{Review: &commentpb.Review{Id: in.GetReviewId()}}, nil
+}
+
+func startServer(t *testing.T, srv *fakeCommentServer) *GRpcClientConfig {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := grpc.NewServer()
+	commentpb.RegisterCommentServiceServer(s, srv)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+	return &GRpcClientConfig{Host: "127.0.0.1", Port: lis.Addr().(*net.TCPAddr).Port}
+}
+
+func TestCommentClient_RetriesUnavailable(t *testing.T) {
+	srv := &fakeCommentServer{failFirst: 2}
+	config := startServer(t, srv)
+
+	c, err := NewCommentClient(config)
+	if err != nil {
+		t.Fatalf("NewCommentClient: %v", err)
+	}
+	defer c.Close()
+
+	review, err := c.GetReview(context.Background(), "r1")
+	if err != nil {
+		t.Fatalf("GetReview: %v", err)
+	}
+	if review.GetId() != "r1" {
+		t.Errorf("got review %q, want r1", review.GetId())
+	}
+	if got := srv.calls.Load(); got != 3 {
+		t.Errorf("got %d attempts, want 3", got)
+	}
+}
+
+func TestCommentClient_NoRetryWhenDisabled(t *testing.T) {
+	srv := &fakeCommentServer{failFirst: 1}
+	config := startServer(t, srv)
+	config.MaxAttempts = 1
+
+	c, err := NewCommentClient(config)
+	if err != nil {
+		t.Fatalf("NewCommentClient: %v", err)
+	}
+	defer c.Close()
+
+	_, err = c.GetReview(context.Background(), "r1")
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("got %v, want Unavailable", err)
+	}
+}
+
+func TestCommentClient_MethodTimeout(t *testing.T) {
+	srv := &fakeCommentServer{handleDelay: time.Second}
+	config := startServer(t, srv)
+	config.MethodTimeouts = map[string]time.Duration{"GetReview": 50 * time.Millisecond}
+
+	c, err := NewCommentClient(config)
+	if err != nil {
+		t.Fatalf("NewCommentClient: %v", err)
+	}
+	defer c.Close()
+
+	start := time.Now()
+	_, err = c.GetReview(context.Background(), "r1")
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("got %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("call took %v, deadline not applied", elapsed)
+	}
+}
+
+func TestNewCommentClient_InvalidConfig(t *testing.T) {
+	if _, err := NewCommentClient(&GRpcClientConfig{Host: "localhost"}); err == nil {
+		t.Error("expected an error for a config without port")
+	}
+}
+
+func TestGetCommentClient_PerTarget(t *testing.T) {
+	defer Destroy()
+
+	a1, err := GetCommentClient(&GRpcClientConfig{Host: "comment-a", Port: 5001})
+	if err != nil {
+		t.Fatalf("GetCommentClient: %v", err)
+	}
+	a2, _ := GetCommentClient(&GRpcClientConfig{Host: "comment-a", Port: 5001})
+	b, _ := GetCommentClient(&GRpcClientConfig{Host: "comment-b", Port: 5001})
+	if a1 != a2 {
+		t.Error("same target should share one client")
+	}
+	if a1 == b {
+		t.Error("different targets should not share a client")
+	}
+
+	Destroy()
+	if len(clients) != 0 {
+		t.Errorf("Destroy left %d clients", len(clients))
+	}
+}
+
+func TestGetCommentClient_DifferentConfig(t *testing.T) {
+	defer Destroy()
+
+	config := &GRpcClientConfig{Host: "comment-a", Port: 5001, MethodTimeouts: map[string]time.Duration{"GetReview": time.Second}}
+	if _, err := GetCommentClient(config); err != nil {
+		t.Fatalf("GetCommentClient: %v", err)
+	}
+	if _, err := GetCommentClient(&GRpcClientConfig{Host: "comment-a", Port: 5001, Timeout: time.Second}); err == nil {
+		t.Error("expected an error for the same target with a different timeout")
+	}
+	config.MethodTimeouts["GetReview"] = 2 * time.Second
+	if _, err := GetCommentClient(config); err == nil {
+		t.Error("expected an error once the method timeouts of the config changed")
+	}
+	same := &GRpcClientConfig{Host: "comment-a", Port: 5001, MethodTimeouts: map[string]time.Duration{"GetReview": time.Second}}
+	if _, err := GetCommentClient(same); err != nil {
+		t.Errorf("an equal config should get the shared client: %v", err)
+	}
+}
+
+func TestCommentClient_PropagatesTraceContext(t *testing.T) {
+	srv := &fakeCommentServer{}
+	config := startServer(t, srv)
+
+	c, err := NewCommentClient(config)
+	if err != nil {
+		t.Fatalf("NewCommentClient: %v", err)
+	}
+	defer c.Close()
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+	if _, err := c.GetReview(ctx, "r1"); err != nil {
+		t.Fatalf("GetReview: %v", err)
+	}
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if got := srv.traceparent.Load(); got != want {
+		t.Fatalf("traceparent = %v, want %s", got, want)
+	}
+}
@@ -1,6 +1,65 @@
 package client
 
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultTimeout          = 3 * time.Second
+	defaultMaxAttempts      = 3
+	defaultKeepaliveTime    = 30 * time.Second
+	defaultKeepaliveTimeout = 10 * time.Second
+)
+
 type GRpcClientConfig struct {
 	Host string `yaml:"host"`
 	Port int    `yaml:"port"`
+	// Timeout is the deadline applied to a call whose context has none, 0 means 3s.
+	Timeout time.Duration `yaml:"timeout"`
+	// MethodTimeouts overrides Timeout per method, keyed by method name, e.g. "GetReview".
+	MethodTimeouts map[string]time.Duration `yaml:"method_timeouts"`
+	// MaxAttempts bounds the attempts of idempotent methods, including the first one.
+	// 0 means 3, 1 disables retries.
+	MaxAttempts int `yaml:"max_attempts"`
+	// KeepaliveTime is the idle time after which the client pings the server, 0 means 30s.
+	KeepaliveTime time.Duration `yaml:"keepalive_time"`
+	// KeepaliveTimeout is how long the client waits for a ping ack, 0 means 10s.
+	KeepaliveTimeout time.Duration `yaml:"keepalive_timeout"`
+}
+
+// Target returns the host:port address the client dials.
+func (c *GRpcClientConfig) Target() string {
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
+
+func (c *GRpcClientConfig) timeout(method string) time.Duration {
+	if t, ok := c.MethodTimeouts[method]; ok && t > 0 {
+		return t
+	}
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return defaultTimeout
+}
+
+func (c *GRpcClientConfig) maxAttempts() int {
+	if c.MaxAttempts > 0 {
+		return c.MaxAttempts
+	}
+	return defaultMaxAttempts
+}
+
+func (c *GRpcClientConfig) keepaliveTime() time.Duration {
+	if c.KeepaliveTime > 0 {
+		return c.KeepaliveTime
+	}
+	return defaultKeepaliveTime
+}
+
+func (c *GRpcClientConfig) keepaliveTimeout() time.Duration {
+	if c.KeepaliveTimeout > 0 {
+		return c.KeepaliveTimeout
+	}
+	return defaultKeepaliveTimeout
 }
@@ -2,40 +2,57 @@ package client
 
 import (
 	"fmt"
+	"maps"
+	"reflect"
 	"sync"
-
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/common/demopb"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
+// cachedClient is a shared client with the config it was created with.
+type cachedClient struct {
+	config GRpcClientConfig
+	client *CommentClient
+}
+
 var (
-	conn           *grpc.ClientConn
-	client         demopb.DemoServiceClient
-	clientSyncOnce sync.Once
+	clients   = map[string]cachedClient{}
+	clientsMu sync.Mutex
 )
 
-func GetDemoClient(config *GRpcClientConfig) (demopb.DemoServiceClient, error) {
-	clientSyncOnce.Do(func() {
-		opts := []grpc.DialOption{
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-			grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(1024 * 1024)),
-			grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(1024 * 1024)),
-		}
-		conn, err := grpc.NewClient(fmt.Sprintf("%s:%d", config.Host, config.Port), opts...)
-		if err != nil {
-			panic(err)
+// GetCommentClient returns the shared client of the target in config, creating
+// it on first use. Clients of different targets are independent. Asking for a
+// target again with a different config is an error rather than handing out a
+// client with the first config.
+func GetCommentClient(config *GRpcClientConfig) (*CommentClient, error) {
+	if config == nil {
+		return nil, fmt.Errorf("nil comment client config")
+	}
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if cached, ok := clients[config.Target()]; ok {
+		if !reflect.DeepEqual(cached.config, *config) {
+			return nil, fmt.Errorf("comment client for %s already created with a different config: %+v", config.Target(), cached.config)
 		}
-		client = demopb.NewDemoServiceClient(conn)
-	})
-	return client, nil
+		return cached.client, nil
+	}
+	c, err := NewCommentClient(config)
+	if err != nil {
+		return nil, err
+	}
+	// a copy, so changes of the caller to its config are seen as a different config
+	saved := *config
+	saved.MethodTimeouts = maps.Clone(config.MethodTimeouts)
+	clients[config.Target()] = cachedClient{config: saved, client: c}
+	return c, nil
 }
 
+// Destroy closes every client created by GetCommentClient.
 func Destroy() {
-	if conn != nil {
-		err := conn.Close()
-		if err != nil {
-			fmt.Printf("Failed to close gRPC connection: %v\n", err)
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	for target, cached := range clients {
+		if err := cached.client.Close(); err != nil {
+			fmt.Printf("Failed to close gRPC connection to %s: %v\n", target, err)
 		}
+		delete(clients, target)
 	}
 }
@@ -0,0 +1,825 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v4.25.3
+// source: proto/comment.proto
+
+package commentpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RatingSummary struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ProductId    int32                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	TotalCount   int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	AverageStars float64                `protobuf:"fixed64,3,opt,name=average_stars,json=averageStars,proto3" json:"average_stars,omitempty"`
+	// stars (1-5) -> number of reviews
+	StarHistogram    map[int32]int32 `protobuf:"bytes,4,rep,name=star_histogram,json=starHistogram,proto3" json:"star_histogram,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	PhotoReviewCount int32           `protobuf:"varint,5,opt,name=photo_review_count,json=photoReviewCount,proto3" json:"photo_review_count,omitempty"`
+	ReplyCount       int32           `protobuf:"varint,6,opt,name=reply_count,json=replyCount,proto3" json:"reply_count,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RatingSummary) Reset() {
+	*x = RatingSummary{}
+	mi := &file_proto_comment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatingSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatingSummary) ProtoMessage() {}
+
+func (x *RatingSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_comment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatingSummary.ProtoReflect.Descriptor instead.
+func (*RatingSummary) Descriptor() ([]byte, []int) {
+	return file_proto_comment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RatingSummary) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *RatingSummary) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *RatingSummary) GetAverageStars() float64 {
+	if x != nil {
+		return x.AverageStars
+	}
+	return 0
+}
+
+func (x *RatingSummary) GetStarHistogram() map[int32]int32 {
+	if x != nil {
+		return x.StarHistogram
+	}
+	return nil
+}
+
+func (x *RatingSummary) GetPhotoReviewCount() int32 {
+	if x != nil {
+		return x.PhotoReviewCount
+	}
+	return 0
+}
+
+func (x *RatingSummary) GetReplyCount() int32 {
+	if x != nil {
+		return x.ReplyCount
+	}
+	return 0
+}
+
+type Review struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Content     string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	UserId      int32                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId   int32                  `protobuf:"varint,4,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ParentId    string                 `protobuf:"bytes,5,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Stars       int32                  `protobuf:"varint,6,opt,name=stars,proto3" json:"stars,omitempty"`
+	IsAnonymous bool                   `protobuf:"varint,7,opt,name=is_anonymous,json=isAnonymous,proto3" json:"is_anonymous,omitempty"`
+	PicInfo     []string               `protobuf:"bytes,8,rep,name=pic_info,json=picInfo,proto3" json:"pic_info,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Likes       int32                  `protobuf:"varint,10,opt,name=likes,proto3" json:"likes,omitempty"`
+	IsPinned    bool                   `protobuf:"varint,11,opt,name=is_pinned,json=isPinned,proto3" json:"is_pinned,omitempty"`
+	ReplyCount  int32                  `protobuf:"varint,12,opt,name=reply_count,json=replyCount,proto3" json:"reply_count,omitempty"`
+	// direct replies, oldest first, up to the depth configured on the server
+	Replies []*Review `protobuf:"bytes,13,rep,name=replies,proto3" json:"replies,omitempty"`
+	// unset unless the author edited the review
+	EditedAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`
+	// 1 based order among the product's pinned reviews, 0 when not pinned
+	PinPosition   int32 `protobuf:"varint,15,opt,name=pin_position,json=pinPosition,proto3" json:"pin_position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Review) Reset() {
+	*x = Review{}
+	mi := &file_proto_comment_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Review) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Review) ProtoMessage() {}
+
+func (x *Review) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_comment_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Review.ProtoReflect.Descriptor instead.
+func (*Review) Descriptor() ([]byte, []int) {
+	return file_proto_comment_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Review) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Review) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Review) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Review) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *Review) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Review) GetStars() int32 {
+	if x != nil {
+		return x.Stars
+	}
+	return 0
+}
+
+func (x *Review) GetIsAnonymous() bool {
+	if x != nil {
+		return x.IsAnonymous
+	}
+	return false
+}
+
+func (x *Review) GetPicInfo() []string {
+	if x != nil {
+		return x.PicInfo
+	}
+	return nil
+}
+
+func (x *Review) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Review) GetLikes() int32 {
+	if x != nil {
+		return x.Likes
+	}
+	return 0
+}
+
+func (x *Review) GetIsPinned() bool {
+	if x != nil {
+		return x.IsPinned
+	}
+	return false
+}
+
+func (x *Review) GetReplyCount() int32 {
+	if x != nil {
+		return x.ReplyCount
+	}
+	return 0
+}
+
+func (x *Review) GetReplies() []*Review {
+	if x != nil {
+		return x.Replies
+	}
+	return nil
+}
+
+func (x *Review) GetEditedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EditedAt
+	}
+	return nil
+}
+
+func (x *Review) GetPinPosition() int32 {
+	if x != nil {
+		return x.PinPosition
+	}
+	return 0
+}
+
+type GetProductRatingSummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int32                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductRatingSummaryRequest) Reset() {
+	*x = GetProductRatingSummaryRequest{}
+	mi := &file_proto_comment_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductRatingSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductRatingSummaryRequest) ProtoMessage() {}
+
+func (x *GetProductRatingSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_comment_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductRatingSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetProductRatingSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_comment_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetProductRatingSummaryRequest) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+type GetProductRatingSummaryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Summary       *RatingSummary         `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductRatingSummaryResponse) Reset() {
+	*x = GetProductRatingSummaryResponse{}
+	mi := &file_proto_comment_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductRatingSummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductRatingSummaryResponse) ProtoMessage() {}
+
+func (x *GetProductRatingSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_comment_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductRatingSummaryResponse.ProtoReflect.Descriptor instead.
+func (*GetProductRatingSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_comment_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetProductRatingSummaryResponse) GetSummary() *RatingSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+type BatchGetProductRatingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductIds    []int32                `protobuf:"varint,1,rep,packed,name=product_ids,json=productIds,proto3" json:"product_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetProductRatingsRequest) Reset() {
+	*x = BatchGetProductRatingsRequest{}
+	mi := &file_proto_comment_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetProductRatingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetProductRatingsRequest) ProtoMessage() {}
+
+func (x *BatchGetProductRatingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_comment_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetProductRatingsRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetProductRatingsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_comment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchGetProductRatingsRequest) GetProductIds() []int32 {
+	if x != nil {
+		return x.ProductIds
+	}
+	return nil
+}
+
+type BatchGetProductRatingsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// product_id -> rating summary
+	Summaries     map[int32]*RatingSummary `protobuf:"bytes,1,rep,name=summaries,proto3" json:"summaries,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetProductRatingsResponse) Reset() {
+	*x = BatchGetProductRatingsResponse{}
+	mi := &file_proto_comment_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetProductRatingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetProductRatingsResponse) ProtoMessage() {}
+
+func (x *BatchGetProductRatingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_comment_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetProductRatingsResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetProductRatingsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_comment_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchGetProductRatingsResponse) GetSummaries() map[int32]*RatingSummary {
+	if x != nil {
+		return x.Summaries
+	}
+	return nil
+}
+
+type ListReviewsByProductRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId int32                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	// page size, 0 means default
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// next_cursor of the previous page, empty for the first page
+	Cursor string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// newest (default), oldest, stars_desc, stars_asc, likes or helpful
+	Sort          string `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsByProductRequest) Reset() {
+	*x = ListReviewsByProductRequest{}
+	mi := &file_proto_comment_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsByProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsByProductRequest) ProtoMessage() {}
+
+func (x *ListReviewsByProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_comment_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsByProductRequest.ProtoReflect.Descriptor instead.
+func (*ListReviewsByProductRequest) Descriptor() ([]byte, []int) {
+	return file_proto_comment_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListReviewsByProductRequest) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ListReviewsByProductRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListReviewsByProductRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListReviewsByProductRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type ListReviewsByProductResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Reviews []*Review              `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	// first of pinned_reviews, kept for older clients
+	PinnedReview *Review `protobuf:"bytes,2,opt,name=pinned_review,json=pinnedReview,proto3" json:"pinned_review,omitempty"`
+	NextCursor   string  `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// in pin position order
+	PinnedReviews []*Review `protobuf:"bytes,4,rep,name=pinned_reviews,json=pinnedReviews,proto3" json:"pinned_reviews,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsByProductResponse) Reset() {
+	*x = ListReviewsByProductResponse{}
+	mi := &file_proto_comment_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsByProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsByProductResponse) ProtoMessage() {}
+
+func (x *ListReviewsByProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_comment_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsByProductResponse.ProtoReflect.Descriptor instead.
+func (*ListReviewsByProductResponse) Descriptor() ([]byte, []int) {
+	return file_proto_comment_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListReviewsByProductResponse) GetReviews() []*Review {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+func (x *ListReviewsByProductResponse) GetPinnedReview() *Review {
+	if x != nil {
+		return x.PinnedReview
+	}
+	return nil
+}
+
+func (x *ListReviewsByProductResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *ListReviewsByProductResponse) GetPinnedReviews() []*Review {
+	if x != nil {
+		return x.PinnedReviews
+	}
+	return nil
+}
+
+type GetReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReviewRequest) Reset() {
+	*x = GetReviewRequest{}
+	mi := &file_proto_comment_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReviewRequest) ProtoMessage() {}
+
+func (x *GetReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_comment_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReviewRequest.ProtoReflect.Descriptor instead.
+func (*GetReviewRequest) Descriptor() ([]byte, []int) {
+	return file_proto_comment_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetReviewRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+type GetReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReviewResponse) Reset() {
+	*x = GetReviewResponse{}
+	mi := &file_proto_comment_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReviewResponse) ProtoMessage() {}
+
+func (x *GetReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_comment_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReviewResponse.ProtoReflect.Descriptor instead.
+func (*GetReviewResponse) Descriptor() ([]byte, []int) {
+	return file_proto_comment_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetReviewResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+var File_proto_comment_proto protoreflect.FileDescriptor
+
+const file_proto_comment_proto_rawDesc = "" +
+	"\n" +
+	"\x13proto/comment.proto\x12\tcommentpb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd9\x02\n" +
+	"\rRatingSummary\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x05R\tproductId\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\x12#\n" +
+	"\raverage_stars\x18\x03 \x01(\x01R\faverageStars\x12R\n" +
+	"\x0estar_histogram\x18\x04 \x03(\v2+.commentpb.RatingSummary.StarHistogramEntryR\rstarHistogram\x12,\n" +
+	"\x12photo_review_count\x18\x05 \x01(\x05R\x10photoReviewCount\x12\x1f\n" +
+	"\vreply_count\x18\x06 \x01(\x05R\n" +
+	"replyCount\x1a@\n" +
+	"\x12StarHistogramEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xf3\x03\n" +
+	"\x06Review\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x05R\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x04 \x01(\x05R\tproductId\x12\x1b\n" +
+	"\tparent_id\x18\x05 \x01(\tR\bparentId\x12\x14\n" +
+	"\x05stars\x18\x06 \x01(\x05R\x05stars\x12!\n" +
+	"\fis_anonymous\x18\a \x01(\bR\visAnonymous\x12\x19\n" +
+	"\bpic_info\x18\b \x03(\tR\apicInfo\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x14\n" +
+	"\x05likes\x18\n" +
+	" \x01(\x05R\x05likes\x12\x1b\n" +
+	"\tis_pinned\x18\v \x01(\bR\bisPinned\x12\x1f\n" +
+	"\vreply_count\x18\f \x01(\x05R\n" +
+	"replyCount\x12+\n" +
+	"\areplies\x18\r \x03(\v2\x11.commentpb.ReviewR\areplies\x127\n" +
+	"\tedited_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\beditedAt\x12!\n" +
+	"\fpin_position\x18\x0f \x01(\x05R\vpinPosition\"?\n" +
+	"\x1eGetProductRatingSummaryRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x05R\tproductId\"U\n" +
+	"\x1fGetProductRatingSummaryResponse\x122\n" +
+	"\asummary\x18\x01 \x01(\v2\x18.commentpb.RatingSummaryR\asummary\"@\n" +
+	"\x1dBatchGetProductRatingsRequest\x12\x1f\n" +
+	"\vproduct_ids\x18\x01 \x03(\x05R\n" +
+	"productIds\"\xd0\x01\n" +
+	"\x1eBatchGetProductRatingsResponse\x12V\n" +
+	"\tsummaries\x18\x01 \x03(\v28.commentpb.BatchGetProductRatingsResponse.SummariesEntryR\tsummaries\x1aV\n" +
+	"\x0eSummariesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x05R\x03key\x12.\n" +
+	"\x05value\x18\x02 \x01(\v2\x18.commentpb.RatingSummaryR\x05value:\x028\x01\"~\n" +
+	"\x1bListReviewsByProductRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x05R\tproductId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x12\x12\n" +
+	"\x04sort\x18\x04 \x01(\tR\x04sort\"\xde\x01\n" +
+	"\x1cListReviewsByProductResponse\x12+\n" +
+	"\areviews\x18\x01 \x03(\v2\x11.commentpb.ReviewR\areviews\x126\n" +
+	"\rpinned_review\x18\x02 \x01(\v2\x11.commentpb.ReviewR\fpinnedReview\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\x128\n" +
+	"\x0epinned_reviews\x18\x04 \x03(\v2\x11.commentpb.ReviewR\rpinnedReviews\"/\n" +
+	"\x10GetReviewRequest\x12\x1b\n" +
+	"\treview_id\x18\x01 \x01(\tR\breviewId\">\n" +
+	"\x11GetReviewResponse\x12)\n" +
+	"\x06review\x18\x01 \x01(\v2\x11.commentpb.ReviewR\x06review2\xa2\x03\n" +
+	"\x0eCommentService\x12p\n" +
+	"\x17GetProductRatingSummary\x12).commentpb.GetProductRatingSummaryRequest\x1a*.commentpb.GetProductRatingSummaryResponse\x12m\n" +
+	"\x16BatchGetProductRatings\x12(.commentpb.BatchGetProductRatingsRequest\x1a).commentpb.BatchGetProductRatingsResponse\x12g\n" +
+	"\x14ListReviewsByProduct\x12&.commentpb.ListReviewsByProductRequest\x1a'.commentpb.ListReviewsByProductResponse\x12F\n" +
+	"\tGetReview\x12\x1b.commentpb.GetReviewRequest\x1a\x1c.commentpb.GetReviewResponseB\x16Z\x14/commentpb;commentpbb\x06proto3"
+
+var (
+	file_proto_comment_proto_rawDescOnce sync.Once
+	file_proto_comment_proto_rawDescData []byte
+)
+
+func file_proto_comment_proto_rawDescGZIP() []byte {
+	file_proto_comment_proto_rawDescOnce.Do(func() {
+		file_proto_comment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_comment_proto_rawDesc), len(file_proto_comment_proto_rawDesc)))
+	})
+	return file_proto_comment_proto_rawDescData
+}
+
+var file_proto_comment_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_proto_comment_proto_goTypes = []any{
+	(*RatingSummary)(nil),                   // 0: commentpb.RatingSummary
+	(*Review)(nil),                          // 1: commentpb.Review
+	(*GetProductRatingSummaryRequest)(nil),  // 2: commentpb.GetProductRatingSummaryRequest
+	(*GetProductRatingSummaryResponse)(nil), // 3: commentpb.GetProductRatingSummaryResponse
+	(*BatchGetProductRatingsRequest)(nil),   // 4: commentpb.BatchGetProductRatingsRequest
+	(*BatchGetProductRatingsResponse)(nil),  // 5: commentpb.BatchGetProductRatingsResponse
+	(*ListReviewsByProductRequest)(nil),     // 6: commentpb.ListReviewsByProductRequest
+	(*ListReviewsByProductResponse)(nil),    // 7: commentpb.ListReviewsByProductResponse
+	(*GetReviewRequest)(nil),                // 8: commentpb.GetReviewRequest
+	(*GetReviewResponse)(nil),               // 9: commentpb.GetReviewResponse
+	nil,                                     // 10: commentpb.RatingSummary.StarHistogramEntry
+	nil,                                     // 11: commentpb.BatchGetProductRatingsResponse.SummariesEntry
+	(*timestamppb.Timestamp)(nil),           // 12: google.protobuf.Timestamp
+}
+var file_proto_comment_proto_depIdxs = []int32{
+	10, // 0: commentpb.RatingSummary.star_histogram:type_name -> commentpb.RatingSummary.StarHistogramEntry
+	12, // 1: commentpb.Review.created_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: commentpb.Review.replies:type_name -> commentpb.Review
+	12, // 3: commentpb.Review.edited_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: commentpb.GetProductRatingSummaryResponse.summary:type_name -> commentpb.RatingSummary
+	11, // 5: commentpb.BatchGetProductRatingsResponse.summaries:type_name -> commentpb.BatchGetProductRatingsResponse.SummariesEntry
+	1,  // 6: commentpb.ListReviewsByProductResponse.reviews:type_name -> commentpb.Review
+	1,  // 7: commentpb.ListReviewsByProductResponse.pinned_review:type_name -> commentpb.Review
+	1,  // 8: commentpb.ListReviewsByProductResponse.pinned_reviews:type_name -> commentpb.Review
+	1,  // 9: commentpb.GetReviewResponse.review:type_name -> commentpb.Review
+	0,  // 10: commentpb.BatchGetProductRatingsResponse.SummariesEntry.value:type_name -> commentpb.RatingSummary
+	2,  // 11: commentpb.CommentService.GetProductRatingSummary:input_type -> commentpb.GetProductRatingSummaryRequest
+	4,  // 12: commentpb.CommentService.BatchGetProductRatings:input_type -> commentpb.BatchGetProductRatingsRequest
+	6,  // 13: commentpb.CommentService.ListReviewsByProduct:input_type -> commentpb.ListReviewsByProductRequest
+	8,  // 14: commentpb.CommentService.GetReview:input_type -> commentpb.GetReviewRequest
+	3,  // 15: commentpb.CommentService.GetProductRatingSummary:output_type -> commentpb.GetProductRatingSummaryResponse
+	5,  // 16: commentpb.CommentService.BatchGetProductRatings:output_type -> commentpb.BatchGetProductRatingsResponse
+	7,  // 17: commentpb.CommentService.ListReviewsByProduct:output_type -> commentpb.ListReviewsByProductResponse
+	9,  // 18: commentpb.CommentService.GetReview:output_type -> commentpb.GetReviewResponse
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_proto_comment_proto_init() }
+func file_proto_comment_proto_init() {
+	if File_proto_comment_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_comment_proto_rawDesc), len(file_proto_comment_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_comment_proto_goTypes,
+		DependencyIndexes: file_proto_comment_proto_depIdxs,
+		MessageInfos:      file_proto_comment_proto_msgTypes,
+	}.Build()
+	File_proto_comment_proto = out.File
+	file_proto_comment_proto_goTypes = nil
+	file_proto_comment_proto_depIdxs = nil
+}
@@ -0,0 +1,239 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v4.25.3
+// source: proto/comment.proto
+
+package commentpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CommentService_GetProductRatingSummary_FullMethodName = "/commentpb.CommentService/GetProductRatingSummary"
+	CommentService_BatchGetProductRatings_FullMethodName  = "/commentpb.CommentService/BatchGetProductRatings"
+	CommentService_ListReviewsByProduct_FullMethodName    = "/commentpb.CommentService/ListReviewsByProduct"
+	CommentService_GetReview_FullMethodName               = "/commentpb.CommentService/GetReview"
+)
+
+// CommentServiceClient is the client API for CommentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CommentService exposes review data to the other ceramicraft services.
+type CommentServiceClient interface {
+	GetProductRatingSummary(ctx context.Context, in *GetProductRatingSummaryRequest, opts ...grpc.CallOption) (*GetProductRatingSummaryResponse, error)
+	BatchGetProductRatings(ctx context.Context, in *BatchGetProductRatingsRequest, opts ...grpc.CallOption) (*BatchGetProductRatingsResponse, error)
+	ListReviewsByProduct(ctx context.Context, in *ListReviewsByProductRequest, opts ...grpc.CallOption) (*ListReviewsByProductResponse, error)
+	GetReview(ctx context.Context, in *GetReviewRequest, opts ...grpc.CallOption) (*GetReviewResponse, error)
+}
+
+type commentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCommentServiceClient(cc grpc.ClientConnInterface) CommentServiceClient {
+	return &commentServiceClient{cc}
+}
+
+func (c *commentServiceClient) GetProductRatingSummary(ctx context.Context, in *GetProductRatingSummaryRequest, opts ...grpc.CallOption) (*GetProductRatingSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProductRatingSummaryResponse)
+	err := c.cc.Invoke(ctx, CommentService_GetProductRatingSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) BatchGetProductRatings(ctx context.Context, in *BatchGetProductRatingsRequest, opts ...grpc.CallOption) (*BatchGetProductRatingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetProductRatingsResponse)
+	err := c.cc.Invoke(ctx, CommentService_BatchGetProductRatings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) ListReviewsByProduct(ctx context.Context, in *ListReviewsByProductRequest, opts ...grpc.CallOption) (*ListReviewsByProductResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReviewsByProductResponse)
+	err := c.cc.Invoke(ctx, CommentService_ListReviewsByProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) GetReview(ctx context.Context, in *GetReviewRequest, opts ...grpc.CallOption) (*GetReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetReviewResponse)
+	err := c.cc.Invoke(ctx, CommentService_GetReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CommentServiceServer is the server API for CommentService service.
+// All implementations must embed UnimplementedCommentServiceServer
+// for forward compatibility.
+//
+// CommentService exposes review data to the other ceramicraft services.
+type CommentServiceServer interface {
+	GetProductRatingSummary(context.Context, *GetProductRatingSummaryRequest) (*GetProductRatingSummaryResponse, error)
+	BatchGetProductRatings(context.Context, *BatchGetProductRatingsRequest) (*BatchGetProductRatingsResponse, error)
+	ListReviewsByProduct(context.Context, *ListReviewsByProductRequest) (*ListReviewsByProductResponse, error)
+	GetReview(context.Context, *GetReviewRequest) (*GetReviewResponse, error)
+	mustEmbedUnimplementedCommentServiceServer()
+}
+
+// UnimplementedCommentServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCommentServiceServer struct{}
+
+func (UnimplementedCommentServiceServer) GetProductRatingSummary(context.Context, *GetProductRatingSummaryRequest) (*GetProductRatingSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductRatingSummary not implemented")
+}
+func (UnimplementedCommentServiceServer) BatchGetProductRatings(context.Context, *BatchGetProductRatingsRequest) (*BatchGetProductRatingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetProductRatings not implemented")
+}
+func (UnimplementedCommentServiceServer) ListReviewsByProduct(context.Context, *ListReviewsByProductRequest) (*ListReviewsByProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReviewsByProduct not implemented")
+}
+func (UnimplementedCommentServiceServer) GetReview(context.Context, *GetReviewRequest) (*GetReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReview not implemented")
+}
+func (UnimplementedCommentServiceServer) mustEmbedUnimplementedCommentServiceServer() {}
+func (UnimplementedCommentServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeCommentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CommentServiceServer will
+// result in compilation errors.
+type UnsafeCommentServiceServer interface {
+	mustEmbedUnimplementedCommentServiceServer()
+}
+
+func RegisterCommentServiceServer(s grpc.ServiceRegistrar, srv CommentServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCommentServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CommentService_ServiceDesc, srv)
+}
+
+func _CommentService_GetProductRatingSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductRatingSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).GetProductRatingSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommentService_GetProductRatingSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).GetProductRatingSummary(ctx, req.(*GetProductRatingSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_BatchGetProductRatings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetProductRatingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).BatchGetProductRatings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommentService_BatchGetProductRatings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).BatchGetProductRatings(ctx, req.(*BatchGetProductRatingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_ListReviewsByProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReviewsByProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).ListReviewsByProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommentService_ListReviewsByProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).ListReviewsByProduct(ctx, req.(*ListReviewsByProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_GetReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).GetReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommentService_GetReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).GetReview(ctx, req.(*GetReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CommentService_ServiceDesc is the grpc.ServiceDesc for CommentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CommentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "commentpb.CommentService",
+	HandlerType: (*CommentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetProductRatingSummary",
+			Handler:    _CommentService_GetProductRatingSummary_Handler,
+		},
+		{
+			MethodName: "BatchGetProductRatings",
+			Handler:    _CommentService_BatchGetProductRatings_Handler,
+		},
+		{
+			MethodName: "ListReviewsByProduct",
+			Handler:    _CommentService_ListReviewsByProduct_Handler,
+		},
+		{
+			MethodName: "GetReview",
+			Handler:    _CommentService_GetReview_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/comment.proto",
+}
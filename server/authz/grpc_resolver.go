@@ -0,0 +1,69 @@
+package authz
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/common/productpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const defaultOwnerLookupTimeout = 2 * time.Second
+
+// GrpcOwnershipResolver asks the product service who owns a product.
+type GrpcOwnershipResolver struct {
+	client  productpb.ProductServiceClient
+	timeout time.Duration
+}
+
+// NewGrpcOwnershipResolver creates a resolver for the product service at
+// target. The connection is established on the first lookup. Lookups carry
+// the trace of the request that needs them.
+func NewGrpcOwnershipResolver(target string, timeout time.Duration) (*GrpcOwnershipResolver, error) {
+	conn, err := grpc.NewClient(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return NewGrpcOwnershipResolverWithClient(productpb.NewProductServiceClient(conn), timeout), nil
+}
+
+// NewGrpcOwnershipResolverWithClient creates a resolver on an existing client.
+// A timeout of 0 means 2s.
+func NewGrpcOwnershipResolverWithClient(client productpb.ProductServiceClient, timeout time.Duration) *GrpcOwnershipResolver {
+	if timeout <= 0 {
+		timeout = defaultOwnerLookupTimeout
+	}
+	return &GrpcOwnershipResolver{client: client, timeout: timeout}
+}
+
+// IsProductOwner implements ProductOwnershipResolver. Unknown products have no owner.
+func (g *GrpcOwnershipResolver) IsProductOwner(ctx context.Context, merchantID int, productID int) (bool, error) {
+	owner, err := g.ProductOwner(ctx, productID)
+	if err != nil {
+		return false, err
+	}
+	return owner != 0 && owner == merchantID, nil
+}
+
+// ProductOwner implements ProductOwnershipResolver.
+func (g *GrpcOwnershipResolver) ProductOwner(ctx context.Context, productID int) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+	resp, err := g.client.GetProductOwner(ctx, &productpb.GetProductOwnerRequest{ProductId: int32(productID)})
+	if status.Code(err) == codes.NotFound {
+		return 0, nil
+	}
+	if err != nil {
+		log.Ctx(ctx).Errorf("GetProductOwner failed\tproduct_id=%d\terr=%v", productID, err)
+		return 0, err
+	}
+	return int(resp.MerchantId), nil
+}
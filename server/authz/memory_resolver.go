@@ -0,0 +1,42 @@
+package authz
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryOwnershipResolver keeps product owners in memory, for tests and
+// local runs without a product service.
+type MemoryOwnershipResolver struct {
+	mu     sync.RWMutex
+	owners map[int]int // product id -> merchant id
+}
+
+func NewMemoryOwnershipResolver() *MemoryOwnershipResolver {
+	return &MemoryOwnershipResolver{owners: make(map[int]int)}
+}
+
+// SetOwner records merchantID as the owner of the products.
+func (m *MemoryOwnershipResolver) SetOwner(merchantID int, productIDs ...int) *MemoryOwnershipResolver {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, productID := range productIDs {
+		m.owners[productID] = merchantID
+	}
+	return m
+}
+
+// IsProductOwner implements ProductOwnershipResolver.
+func (m *MemoryOwnershipResolver) IsProductOwner(_ context.Context, merchantID int, productID int) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	owner, ok := m.owners[productID]
+	return ok && owner == merchantID, nil
+}
+
+// ProductOwner implements ProductOwnershipResolver.
+func (m *MemoryOwnershipResolver) ProductOwner(_ context.Context, productID int) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.owners[productID], nil
+}
@@ -0,0 +1,46 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+)
+
+// ProductOwnershipResolver tells whether a merchant owns a product. Merchant
+// actions on reviews are only allowed on the merchant's own products.
+type ProductOwnershipResolver interface {
+	IsProductOwner(ctx context.Context, merchantID int, productID int) (bool, error)
+	// ProductOwner returns the merchant owning the product, 0 for unknown
+	// products.
+	ProductOwner(ctx context.Context, productID int) (merchantID int, err error)
+}
+
+var (
+	resolverInstance ProductOwnershipResolver
+	resolverSyncOnce sync.Once
+)
+
+// GetOwnershipResolver returns the resolver backed by the product service. The
+// config requires the product service, without one no merchant owns any
+// product and every merchant action is forbidden.
+func GetOwnershipResolver() ProductOwnershipResolver {
+	resolverSyncOnce.Do(func() {
+		cfg := config.Config.ProductConfig
+		if cfg == nil {
+			log.Logger.Errorf("product service is not configured, merchant actions will be forbidden")
+			resolverInstance = NewMemoryOwnershipResolver()
+			return
+		}
+		resolver, err := NewGrpcOwnershipResolver(fmt.Sprintf("%s:%d", cfg.Host, cfg.Port), cfg.Timeout)
+		if err != nil {
+			log.Logger.Errorf("failed to create product ownership resolver, merchant actions will be forbidden: %v", err)
+			resolverInstance = NewMemoryOwnershipResolver()
+			return
+		}
+		resolverInstance = resolver
+	})
+	return resolverInstance
+}
@@ -0,0 +1,95 @@
+package authz
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/common/productpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+)
+
+func init() {
+	logger, _ := zap.NewDevelopment()
+	log.Logger = logger.Sugar()
+}
+
+type fakeProductService struct {
+	productpb.UnimplementedProductServiceServer
+	owners map[int32]int32
+}
+
+func (f *fakeProductService) GetProductOwner(_ context.Context, req *productpb.GetProductOwnerRequest) (*productpb.GetProductOwnerResponse, error) {
+	if req.ProductId < 0 {
+		return nil, status.Error(codes.Unavailable, "down")
+	}
+	owner, ok := f.owners[req.ProductId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "no such product")
+	}
+	return &productpb.GetProductOwnerResponse{MerchantId: owner}, nil
+}
+
+func startProductService(t *testing.T, owners map[int32]int32) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	productpb.RegisterProductServiceServer(server, &fakeProductService{owners: owners})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestGrpcOwnershipResolver(t *testing.T) {
+	target := startProductService(t, map[int32]int32{10: 1})
+	resolver, err := NewGrpcOwnershipResolver(target, 0)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	isOwner, err := resolver.IsProductOwner(ctx, 1, 10)
+	assert.NoError(t, err)
+	assert.True(t, isOwner)
+
+	isOwner, err = resolver.IsProductOwner(ctx, 2, 10)
+	assert.NoError(t, err)
+	assert.False(t, isOwner)
+
+	// unknown products have no owner
+	isOwner, err = resolver.IsProductOwner(ctx, 1, 11)
+	assert.NoError(t, err)
+	assert.False(t, isOwner)
+
+	_, err = resolver.IsProductOwner(ctx, 1, -1)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	owner, err := resolver.ProductOwner(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, owner)
+	owner, err = resolver.ProductOwner(ctx, 11)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, owner)
+}
+
+func TestMemoryOwnershipResolver(t *testing.T) {
+	resolver := NewMemoryOwnershipResolver().SetOwner(1, 10, 11)
+	ctx := context.Background()
+
+	isOwner, _ := resolver.IsProductOwner(ctx, 1, 11)
+	assert.True(t, isOwner)
+	isOwner, _ = resolver.IsProductOwner(ctx, 2, 11)
+	assert.False(t, isOwner)
+	isOwner, _ = resolver.IsProductOwner(ctx, 1, 12)
+	assert.False(t, isOwner)
+
+	owner, _ := resolver.ProductOwner(ctx, 10)
+	assert.Equal(t, 1, owner)
+	owner, _ = resolver.ProductOwner(ctx, 12)
+	assert.Equal(t, 0, owner)
+}
@@ -1,31 +1,97 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
-var Config = &Conf{}
+const (
+	// EnvPrefix prefixes the environment variables overriding config keys,
+	// mongo.host is overridden by CERAMICRAFT_MONGO_HOST.
+	EnvPrefix = "CERAMICRAFT"
+	// ProfileEnv selects the profile when the -profile flag is not given.
+	ProfileEnv = EnvPrefix + "_PROFILE"
+	// DefaultProfile reads resources/config.yml, any other profile p reads
+	// resources/config-p.yml.
+	DefaultProfile = "default"
+)
+
+var (
+	Config = &Conf{}
+	// Profile is the profile the config was read for, File the file read.
+	Profile string
+	File    string
+)
 
 type Conf struct {
-	GrpcConfig  *GrpcConfig    `mapstructure:"grpc"`
-	LogConfig   *LogConfig     `mapstructure:"log"`
-	HttpConfig  *HttpConfig    `mapstructure:"http"`
-	MySQLConfig *MySQL         `mapstructure:"mysql"`
-	MongoConfig *MongoDBConfig `mapstructure:"mongo"`
-	RedisConfig *RedisConfig   `mapstructure:"redis"`
+	GrpcConfig    *GrpcConfig    `mapstructure:"grpc"`
+	LogConfig     *LogConfig     `mapstructure:"log"`
+	HttpConfig    *HttpConfig    `mapstructure:"http"`
+	MySQLConfig   *MySQL         `mapstructure:"mysql"`
+	MongoConfig   *MongoDBConfig `mapstructure:"mongo"`
+	RedisConfig   *RedisConfig   `mapstructure:"redis"`
+	ReviewConfig  *ReviewConfig  `mapstructure:"review"`
+	ProductConfig *ProductConfig `mapstructure:"product"`
+	TracingConfig *TracingConfig `mapstructure:"tracing"`
+	HealthConfig  *HealthConfig  `mapstructure:"health"`
+	// ShutdownDelay is how long the servers keep serving at shutdown once they
+	// report not ready, for the probes to see it and stop routing to them
+	ShutdownDelay time.Duration `mapstructure:"shutdown_delay"`
+	// ShutdownTimeout bounds how long the servers drain at shutdown
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 }
 
-type RedisConfig struct {
-	Host string `mapstructure:"host"`
-	Port int    `mapstructure:"port"`
+// ProductConfig locates the product service, which knows the owner of each product.
+type ProductConfig struct {
+	Host    string        `mapstructure:"host"`
+	Port    int           `mapstructure:"port"`
+	Timeout time.Duration `mapstructure:"timeout"`
 }
 
-type MongoDBConfig struct {
+// TracingConfig selects where the spans of the service are exported.
+type TracingConfig struct {
+	Exporter    string  `mapstructure:"exporter"`     // otlp, stdout or none
+	Endpoint    string  `mapstructure:"endpoint"`     // host:port of the otlp grpc collector
+	Insecure    bool    `mapstructure:"insecure"`     // talk to the collector without tls
+	SampleRatio float64 `mapstructure:"sample_ratio"` // of the traces started here, callers decide for theirs
+}
+
+// HealthConfig tunes the readiness checks of the dependencies.
+type HealthConfig struct {
+	Timeout       time.Duration `mapstructure:"timeout"`        // of each dependency ping
+	CheckInterval time.Duration `mapstructure:"check_interval"` // how often the grpc health status is refreshed
+}
+
+type ReviewConfig struct {
+	ReplyDepth       int           `mapstructure:"reply_depth"`        // levels of replies nested under a review in list responses
+	ModerationPolicy string        `mapstructure:"moderation_policy"`  // auto_approve or hold
+	TrashRetention   time.Duration `mapstructure:"trash_retention"`    // how long deleted reviews stay restorable
+	PurgeInterval    time.Duration `mapstructure:"purge_interval"`     // how often the trash is purged
+	EditWindow       time.Duration `mapstructure:"edit_window"`        // how long after posting authors may edit a review
+	MaxContentLength int           `mapstructure:"max_content_length"` // in characters
+	MaxPictures      int           `mapstructure:"max_pictures"`
+	PictureHosts     []string      `mapstructure:"picture_hosts"` // hosts pictures may be served from, empty allows any
+	MaxPinned        int           `mapstructure:"max_pinned"`    // pinned reviews per product
+}
+
+type RedisConfig struct {
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
-	Database string `mapstructure:"database"`
+	Password string `mapstructure:"password" secret:"true"`
+}
+
+type MongoDBConfig struct {
+	Host           string `mapstructure:"host"`
+	Port           int    `mapstructure:"port"`
+	Database       string `mapstructure:"database"`
+	Username       string `mapstructure:"username"` // empty connects without authentication
+	Password       string `mapstructure:"password" secret:"true"`
+	MigrateOnStart bool   `mapstructure:"migrate_on_start"` // apply pending schema migrations at startup
 }
 
 type HttpConfig struct {
@@ -36,6 +102,7 @@ type HttpConfig struct {
 type LogConfig struct {
 	Level    string `mapstructure:"level"`
 	FilePath string `mapstructure:"file_path"`
+	Format   string `mapstructure:"format"` // console or json, empty means console
 }
 
 type GrpcConfig struct {
@@ -49,29 +116,80 @@ type MySQL struct {
 	Host     string `mapstructure:"host"`
 	Port     string `mapstructure:"port"`
 	UserName string `mapstructure:"userName"`
-	Password string `mapstructure:"password"`
+	Password string `mapstructure:"password" secret:"true"`
 	DBName   string `mapstructure:"dbName"`
 }
 
-var useLocalConfig = false
-
-func Init() {
+// Init reads the config of the profile, or of the profile in CERAMICRAFT_PROFILE
+// when it is empty, applies the environment overrides and validates it. A
+// config that cannot be read or is invalid is reported on stderr and exits.
+func Init(profile string) {
+	if profile == "" {
+		profile = os.Getenv(ProfileEnv)
+	}
+	if profile == "" {
+		profile = DefaultProfile
+	}
 	workDir, _ := os.Getwd()
-	if useLocalConfig {
-		viper.SetConfigName("config-local")
-	} else {
-		viper.SetConfigName("config")
+	conf, file, err := load(profile, workDir+"/resources", workDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config profile %s: %v\n", profile, err)
+		os.Exit(1)
 	}
-	viper.SetConfigType("yml")
-	viper.AddConfigPath(workDir + "/resources")
-	viper.AddConfigPath(workDir)
+	if err := conf.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "config profile %s, %s: %v\n", profile, file, err)
+		os.Exit(1)
+	}
+	Config, Profile, File = conf, profile, file
+}
 
-	err := viper.ReadInConfig()
-	if err != nil {
-		panic(err)
+// load reads the config file of the profile from the first of the dirs that
+// has it, overridden by the environment, and returns the file read.
+func load(profile string, dirs ...string) (*Conf, string, error) {
+	v := viper.New()
+	v.SetConfigName(configName(profile))
+	v.SetConfigType("yml")
+	for _, dir := range dirs {
+		v.AddConfigPath(dir)
 	}
-	err = viper.Unmarshal(&Config)
-	if err != nil {
-		panic(err)
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	// keys missing from the file are only looked up in the environment once
+	// bound
+	bindEnvs(v, reflect.TypeOf(Conf{}), "")
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, "", err
+	}
+	conf := &Conf{}
+	if err := v.Unmarshal(conf); err != nil {
+		return nil, "", err
+	}
+	return conf, v.ConfigFileUsed(), nil
+}
+
+func configName(profile string) string {
+	if profile == DefaultProfile {
+		return "config"
+	}
+	return "config-" + profile
+}
+
+// bindEnvs binds every key of the config struct t, nested under prefix, to
+// its environment variable.
+func bindEnvs(v *viper.Viper, t reflect.Type, prefix string) {
+	for idx := 0; idx < t.NumField(); idx++ {
+		field := t.Field(idx)
+		key := prefix + field.Tag.Get("mapstructure")
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct {
+			bindEnvs(v, fieldType, key+".")
+			continue
+		}
+		_ = v.BindEnv(key) // fails only without a key
 	}
 }
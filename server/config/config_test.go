@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfig = `
+grpc:
+  port: 5001
+http:
+  port: 8080
+log:
+  level: info
+mongo:
+  host: "mongo"
+  port: 27017
+  database: "comment_db"
+redis:
+  host: "redis"
+  port: 6379
+review:
+  trash_retention: "720h"
+`
+
+func writeConfig(t *testing.T, name string, content string) string {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	return dir
+}
+
+func TestLoad_Profile(t *testing.T) {
+	dir := writeConfig(t, "config-staging.yml", testConfig)
+
+	conf, file, err := load("staging", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "config-staging.yml"), file)
+	assert.Equal(t, "mongo", conf.MongoConfig.Host)
+	assert.Equal(t, 720*time.Hour, conf.ReviewConfig.TrashRetention)
+
+	_, _, err = load(DefaultProfile, dir)
+	assert.Error(t, err)
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	dir := writeConfig(t, "config.yml", testConfig)
+	t.Setenv("CERAMICRAFT_MONGO_HOST", "mongo.internal")
+	t.Setenv("CERAMICRAFT_MONGO_PORT", "27018")
+	t.Setenv("CERAMICRAFT_REVIEW_TRASH_RETENTION", "24h")
+	t.Setenv("CERAMICRAFT_REVIEW_PICTURE_HOSTS", "a.example.com,b.example.com")
+	// keys missing from the file and a section missing from it
+	t.Setenv("CERAMICRAFT_REDIS_PASSWORD", "secret")
+	t.Setenv("CERAMICRAFT_PRODUCT_HOST", "product")
+
+	conf, _, err := load(DefaultProfile, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "mongo.internal", conf.MongoConfig.Host)
+	assert.Equal(t, 27018, conf.MongoConfig.Port)
+	assert.Equal(t, 24*time.Hour, conf.ReviewConfig.TrashRetention)
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, conf.ReviewConfig.PictureHosts)
+	assert.Equal(t, "secret", conf.RedisConfig.Password)
+	assert.Equal(t, "product", conf.ProductConfig.Host)
+	assert.Equal(t, "redis", conf.RedisConfig.Host)
+}
+
+func TestLoad_ShippedProfiles(t *testing.T) {
+	for _, profile := range []string{DefaultProfile, "local"} {
+		conf, _, err := load(profile, "../resources")
+		assert.NoError(t, err, profile)
+		assert.NoError(t, conf.Validate(), profile)
+	}
+}
+
+func validConfig() *Conf {
+	return &Conf{
+		GrpcConfig:    &GrpcConfig{Port: 5001},
+		HttpConfig:    &HttpConfig{Port: 8080},
+		LogConfig:     &LogConfig{Level: "info", Format: "json"},
+		MongoConfig:   &MongoDBConfig{Host: "mongo", Port: 27017, Database: "comment_db"},
+		RedisConfig:   &RedisConfig{Host: "redis", Port: 6379},
+		ProductConfig: &ProductConfig{Host: "product", Port: 5001},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, validConfig().Validate())
+
+	conf := validConfig()
+	conf.HttpConfig = nil
+	conf.LogConfig.Level = "verbose"
+	conf.MongoConfig.Port = 0
+	conf.MongoConfig.Password = "secret"
+	conf.ReviewConfig = &ReviewConfig{ModerationPolicy: "manual", EditWindow: -time.Hour}
+	conf.TracingConfig = &TracingConfig{Exporter: " OTLP ", SampleRatio: 2}
+	conf.ProductConfig = nil
+	conf.ShutdownDelay = -time.Second
+	err := conf.Validate()
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{
+		"http: is required",
+		`log.level: must be one of debug, info, warn, error, dpanic, panic, fatal, got "verbose"`,
+		"mongo.port: must be between 1 and 65535, got 0",
+		"mongo.username: is required with mongo.password",
+		`review.moderation_policy: must be one of auto_approve, hold, got "manual"`,
+		"review.edit_window: must not be negative, got -1h0m0s",
+		"product: is required",
+		"tracing.sample_ratio: must be between 0 and 1, got 2",
+		"shutdown_delay: must not be negative, got -1s",
+	}, validationErr.Problems)
+}
+
+func TestMasked(t *testing.T) {
+	conf := validConfig()
+	conf.MongoConfig.Username = "app"
+	conf.MongoConfig.Password = "secret"
+	conf.ShutdownTimeout = 20 * time.Second
+
+	out := conf.Masked()
+	mongo := out["mongo"].(map[string]interface{})
+	assert.Equal(t, "app", mongo["username"])
+	assert.Equal(t, masked, mongo["password"])
+	assert.Equal(t, "", out["redis"].(map[string]interface{})["password"])
+	assert.Equal(t, "20s", out["shutdown_timeout"])
+	assert.Nil(t, out["tracing"])
+	// the config itself is left as is
+	assert.Equal(t, "secret", conf.MongoConfig.Password)
+}
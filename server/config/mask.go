@@ -0,0 +1,43 @@
+package config
+
+import (
+	"reflect"
+	"time"
+)
+
+// masked replaces the value of a secret key that is set.
+const masked = "******"
+
+// Masked returns the config as nested maps keyed like the config file, for
+// printing. Secret keys that are set read masked, durations read like 20s.
+func (c *Conf) Masked() map[string]interface{} {
+	return maskStruct(reflect.ValueOf(c).Elem())
+}
+
+func maskStruct(value reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{}, value.NumField())
+	for idx := 0; idx < value.NumField(); idx++ {
+		field := value.Type().Field(idx)
+		key := field.Tag.Get("mapstructure")
+		fieldValue := value.Field(idx)
+		switch {
+		case field.Tag.Get("secret") == "true":
+			if fieldValue.IsZero() {
+				out[key] = ""
+			} else {
+				out[key] = masked
+			}
+		case field.Type == reflect.TypeOf(time.Duration(0)):
+			out[key] = time.Duration(fieldValue.Int()).String()
+		case field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct:
+			if fieldValue.IsNil() {
+				out[key] = nil
+			} else {
+				out[key] = maskStruct(fieldValue.Elem())
+			}
+		default:
+			out[key] = fieldValue.Interface()
+		}
+	}
+	return out
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Values of the keys that take one of a few, the packages reading them switch
+// on the same strings. Empty picks the default of each.
+var (
+	logFormats         = []string{"console", "json"}
+	moderationPolicies = []string{"auto_approve", "hold"}
+	traceExporters     = []string{"none", "otlp", "stdout"}
+)
+
+// ValidationError lists every problem of an invalid config, each prefixed
+// with its key.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// validator collects the problems of the config.
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(key string, format string, args ...interface{}) {
+	v.problems = append(v.problems, key+": "+fmt.Sprintf(format, args...))
+}
+
+// section reports whether a required section is there, it is a problem
+// when missing.
+func (v *validator) section(key string, missing bool) bool {
+	if missing {
+		v.addf(key, "is required")
+	}
+	return !missing
+}
+
+func (v *validator) host(key string, host string) {
+	if host == "" {
+		v.addf(key, "is required")
+	}
+}
+
+func (v *validator) port(key string, port int) {
+	if port < 1 || port > 65535 {
+		v.addf(key, "must be between 1 and 65535, got %d", port)
+	}
+}
+
+func (v *validator) notNegative(key string, value int) {
+	if value < 0 {
+		v.addf(key, "must not be negative, got %d", value)
+	}
+}
+
+func (v *validator) duration(key string, value time.Duration) {
+	if value < 0 {
+		v.addf(key, "must not be negative, got %s", value)
+	}
+}
+
+func (v *validator) oneOf(key string, value string, allowed []string) {
+	if value == "" {
+		return
+	}
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+	v.addf(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+// Validate checks the config before anything is started. It reports every
+// problem found rather than the first, in a *ValidationError.
+func (c *Conf) Validate() error {
+	v := &validator{}
+	if v.section("grpc", c.GrpcConfig == nil) {
+		v.port("grpc.port", c.GrpcConfig.Port)
+		v.notNegative("grpc.connect_timeout", c.GrpcConfig.ConnectTimeout)
+		v.notNegative("grpc.max_pool_size", c.GrpcConfig.MaxPoolSize)
+	}
+	if v.section("http", c.HttpConfig == nil) {
+		v.port("http.port", c.HttpConfig.Port)
+	}
+	if v.section("log", c.LogConfig == nil) {
+		if c.LogConfig.Level != "" {
+			if _, err := zapcore.ParseLevel(c.LogConfig.Level); err != nil {
+				v.addf("log.level", "must be one of debug, info, warn, error, dpanic, panic, fatal, got %q", c.LogConfig.Level)
+			}
+		}
+		v.oneOf("log.format", c.LogConfig.Format, logFormats)
+	}
+	if v.section("mongo", c.MongoConfig == nil) {
+		v.host("mongo.host", c.MongoConfig.Host)
+		v.port("mongo.port", c.MongoConfig.Port)
+		if c.MongoConfig.Database == "" {
+			v.addf("mongo.database", "is required")
+		}
+		if c.MongoConfig.Password != "" && c.MongoConfig.Username == "" {
+			v.addf("mongo.username", "is required with mongo.password")
+		}
+	}
+	if v.section("redis", c.RedisConfig == nil) {
+		v.host("redis.host", c.RedisConfig.Host)
+		v.port("redis.port", c.RedisConfig.Port)
+	}
+	if c.ReviewConfig != nil {
+		v.notNegative("review.reply_depth", c.ReviewConfig.ReplyDepth)
+		v.oneOf("review.moderation_policy", c.ReviewConfig.ModerationPolicy, moderationPolicies)
+		v.duration("review.trash_retention", c.ReviewConfig.TrashRetention)
+		v.duration("review.purge_interval", c.ReviewConfig.PurgeInterval)
+		v.duration("review.edit_window", c.ReviewConfig.EditWindow)
+		v.notNegative("review.max_content_length", c.ReviewConfig.MaxContentLength)
+		v.notNegative("review.max_pictures", c.ReviewConfig.MaxPictures)
+		v.notNegative("review.max_pinned", c.ReviewConfig.MaxPinned)
+	}
+	// without a product service no merchant owns any product, so every
+	// merchant action would be forbidden
+	if v.section("product", c.ProductConfig == nil) {
+		v.host("product.host", c.ProductConfig.Host)
+		v.port("product.port", c.ProductConfig.Port)
+		v.duration("product.timeout", c.ProductConfig.Timeout)
+	}
+	if c.TracingConfig != nil {
+		// the tracing package ignores the case and spaces of the exporter
+		exporter := strings.ToLower(strings.TrimSpace(c.TracingConfig.Exporter))
+		v.oneOf("tracing.exporter", exporter, traceExporters)
+		if c.TracingConfig.SampleRatio < 0 || c.TracingConfig.SampleRatio > 1 {
+			v.addf("tracing.sample_ratio", "must be between 0 and 1, got %v", c.TracingConfig.SampleRatio)
+		}
+	}
+	if c.HealthConfig != nil {
+		v.duration("health.timeout", c.HealthConfig.Timeout)
+		v.duration("health.check_interval", c.HealthConfig.CheckInterval)
+	}
+	v.duration("shutdown_delay", c.ShutdownDelay)
+	v.duration("shutdown_timeout", c.ShutdownTimeout)
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
@@ -70,7 +70,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "code 40001 with field errors",
                         "schema": {
                             "allOf": [
                                 {
@@ -80,7 +80,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/validation.FieldError"
+                                            }
                                         }
                                     }
                                 }
@@ -110,7 +113,7 @@ const docTemplate = `{
         },
         "/comment-ms/v1/customer/reviews/product/{product_id}": {
             "get": {
-                "description": "Get list of reviews for a product",
+                "description": "Get a page of reviews for a product, newest first unless sorted otherwise",
                 "consumes": [
                     "application/json"
                 ],
@@ -139,6 +142,33 @@ const docTemplate = `{
                         "name": "client",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, default 20, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "newest",
+                            "oldest",
+                            "stars_desc",
+                            "stars_asc",
+                            "likes",
+                            "helpful"
+                        ],
+                        "type": "string",
+                        "default": "newest",
+                        "description": "Order of the reviews",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -199,9 +229,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment-ms/v1/customer/reviews/user": {
+        "/comment-ms/v1/customer/reviews/product/{product_id}/search": {
             "get": {
-                "description": "Get list of reviews created by current authenticated user",
+                "description": "Full-text search over the content of a product's reviews, the most relevant first. q supports \"quoted phrases\" and -negated terms, results carry a highlighted snippet",
                 "consumes": [
                     "application/json"
                 ],
@@ -211,18 +241,49 @@ const docTemplate = `{
                 "tags": [
                     "Review"
                 ],
-                "summary": "Get reviews by user",
+                "summary": "Search the reviews of a product",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, default 20, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "enum": [
-                            "customer",
-                            "merchant"
+                            "relevance",
+                            "newest",
+                            "oldest",
+                            "stars_desc",
+                            "stars_asc",
+                            "likes",
+                            "helpful"
                         ],
                         "type": "string",
-                        "description": "Client identifier",
-                        "name": "client",
-                        "in": "path",
-                        "required": true
+                        "default": "relevance",
+                        "description": "Order of the results",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -237,10 +298,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/types.ReviewInfo"
-                                            }
+                                            "$ref": "#/definitions/types.ListReviewResponse"
                                         }
                                     }
                                 }
@@ -248,7 +306,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "code 40001 with field errors",
                         "schema": {
                             "allOf": [
                                 {
@@ -258,7 +316,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/validation.FieldError"
+                                            }
                                         }
                                     }
                                 }
@@ -286,9 +347,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment-ms/v1/customer/reviews/{review_id}/like": {
-            "post": {
-                "description": "Like a review by id",
+        "/comment-ms/v1/customer/reviews/product/{product_id}/summary": {
+            "get": {
+                "description": "Get review count, average stars, star histogram, photo review count and reply count of a product",
                 "consumes": [
                     "application/json"
                 ],
@@ -298,25 +359,12 @@ const docTemplate = `{
                 "tags": [
                     "Review"
                 ],
-                "summary": "Like a review",
+                "summary": "Get product rating summary",
                 "parameters": [
                     {
-                        "description": "LikeRequest",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.LikeRequest"
-                        }
-                    },
-                    {
-                        "enum": [
-                            "customer",
-                            "merchant"
-                        ],
-                        "type": "string",
-                        "description": "Client identifier",
-                        "name": "client",
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
                         "in": "path",
                         "required": true
                     }
@@ -333,7 +381,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/types.RatingSummary"
                                         }
                                     }
                                 }
@@ -379,9 +427,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment-ms/v1/merchant/review/{review_id}": {
-            "delete": {
-                "description": "Delete a review by id",
+        "/comment-ms/v1/customer/reviews/user": {
+            "get": {
+                "description": "Get a page of reviews created by current authenticated user, newest first unless sorted otherwise",
                 "consumes": [
                     "application/json"
                 ],
@@ -391,14 +439,45 @@ const docTemplate = `{
                 "tags": [
                     "Review"
                 ],
-                "summary": "Delete a review",
+                "summary": "Get reviews by user",
                 "parameters": [
                     {
+                        "enum": [
+                            "customer",
+                            "merchant"
+                        ],
                         "type": "string",
-                        "description": "Review ID",
-                        "name": "review_id",
+                        "description": "Client identifier",
+                        "name": "client",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, default 20, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "newest",
+                            "oldest",
+                            "stars_desc",
+                            "stars_asc",
+                            "likes",
+                            "helpful"
+                        ],
+                        "type": "string",
+                        "default": "newest",
+                        "description": "Order of the reviews",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -413,7 +492,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/types.ListReviewResponse"
                                         }
                                     }
                                 }
@@ -459,9 +538,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment-ms/v1/merchant/reviews/list": {
-            "post": {
-                "description": "Filter reviews by product_id and stars (0 means any), ordered by created_at desc",
+        "/comment-ms/v1/customer/reviews/{review_id}": {
+            "delete": {
+                "description": "Move a review or reply written by the current user to the trash",
                 "consumes": [
                     "application/json"
                 ],
@@ -471,16 +550,14 @@ const docTemplate = `{
                 "tags": [
                     "Review"
                 ],
-                "summary": "List reviews by product and stars",
+                "summary": "Delete own review",
                 "parameters": [
                     {
-                        "description": "ListReviewRequest",
-                        "name": "filter",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.ListReviewRequest"
-                        }
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -495,10 +572,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/types.ReviewInfo"
-                                            }
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -523,6 +597,42 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -542,11 +652,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/comment-ms/v1/merchant/reviews/{review_id}": {
+            },
             "patch": {
-                "description": "Pin a review by id",
+                "description": "Change the content, stars or pictures of a review written by the current user, within the edit window. Fields left out keep their value",
                 "consumes": [
                     "application/json"
                 ],
@@ -556,15 +664,22 @@ const docTemplate = `{
                 "tags": [
                     "Review"
                 ],
-                "summary": "Pin a review",
+                "summary": "Edit own review",
                 "parameters": [
                     {
-                        "description": "PinReviewRequest",
-                        "name": "user",
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "EditReviewRequest",
+                        "name": "req",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PinReviewRequest"
+                            "$ref": "#/definitions/types.EditReviewRequest"
                         }
                     }
                 ],
@@ -580,7 +695,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/types.ReviewInfo"
                                         }
                                     }
                                 }
@@ -588,7 +703,64 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "code 40001 with field errors",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/validation.FieldError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "allOf": [
                                 {
@@ -626,9 +798,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment-ms/v1/merchant/reviews/{review_id}/reply": {
+        "/comment-ms/v1/customer/reviews/{review_id}/like": {
             "post": {
-                "description": "Reply an review record.",
+                "description": "Like a review by id. Only approved reviews that are not deleted can be liked",
                 "consumes": [
                     "application/json"
                 ],
@@ -638,16 +810,27 @@ const docTemplate = `{
                 "tags": [
                     "Review"
                 ],
-                "summary": "Review Reply",
+                "summary": "Like a review",
                 "parameters": [
                     {
-                        "description": "CreateReviewRequest",
+                        "description": "LikeRequest",
                         "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.CreateReviewRequest"
+                            "$ref": "#/definitions/types.LikeRequest"
                         }
+                    },
+                    {
+                        "enum": [
+                            "customer",
+                            "merchant"
+                        ],
+                        "type": "string",
+                        "description": "Client identifier",
+                        "name": "client",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -662,7 +845,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/types.CreateReviewRequest"
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -687,6 +870,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -706,7 +907,1424 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
+            },
+            "delete": {
+                "description": "Take back the current user's like on a review by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Unlike a review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comment-ms/v1/customer/reviews/{review_id}/unhelpful": {
+            "post": {
+                "description": "Record that the current user found a review unhelpful, it lowers the review's helpful score. Only approved reviews that are not deleted can be voted on",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Mark a review as unhelpful",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Take back the current user's unhelpful vote on a review by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Take back an unhelpful vote",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comment-ms/v1/merchant/review/{review_id}": {
+            "delete": {
+                "description": "Move a review of one of the merchant's products to the trash. It is purged after the retention period",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Delete a review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comment-ms/v1/merchant/reviews/list": {
+            "post": {
+                "description": "Filter the reviews of one of the merchant's products by stars or a star range, created_at range, photos, anonymity, merchant reply, moderation status, authors and a keyword in the content. Newest first unless sorted otherwise, one page at a time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Search reviews",
+                "parameters": [
+                    {
+                        "description": "ListReviewRequest",
+                        "name": "filter",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ListReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.ListReviewResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "code 40001 with field errors",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/validation.FieldError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comment-ms/v1/merchant/reviews/moderation": {
+            "get": {
+                "description": "Get a page of reviews and replies of one of the merchant's products in a moderation status, pending by default, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "List moderation queue",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID, one of the merchant's products",
+                        "name": "product_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected",
+                            "hidden"
+                        ],
+                        "type": "string",
+                        "description": "Moderation status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, default 20, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.ListReviewResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "code 40001 with field errors",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/validation.FieldError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comment-ms/v1/merchant/reviews/search": {
+            "post": {
+                "description": "Full-text search over the content of the reviews of the given products of the merchant, in every moderation status, the most relevant first. q supports \"quoted phrases\" and -negated terms, results carry a highlighted snippet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Search the reviews of the merchant's products",
+                "parameters": [
+                    {
+                        "description": "SearchReviewRequest",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.SearchReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.ListReviewResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "code 40001 with field errors",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/validation.FieldError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comment-ms/v1/merchant/reviews/trash": {
+            "get": {
+                "description": "Get a page of deleted reviews of one of the merchant's products that can still be restored, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "List deleted reviews",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID, one of the merchant's products",
+                        "name": "product_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, default 20, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.ListReviewResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "code 40001 with field errors",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/validation.FieldError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comment-ms/v1/merchant/reviews/{review_id}": {
+            "patch": {
+                "description": "Pin a review at a position among its product's pinned reviews, optionally until expires_at. is_pinned false unpins it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Pin or unpin a review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "PinReviewRequest",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PinReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comment-ms/v1/merchant/reviews/{review_id}/moderation": {
+            "post": {
+                "description": "Approve, reject or hide a review or reply. A reason is required unless the review is approved",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Moderate a review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ModerateReviewRequest",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ModerateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comment-ms/v1/merchant/reviews/{review_id}/reply": {
+            "post": {
+                "description": "Reply an review record.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Review Reply",
+                "parameters": [
+                    {
+                        "description": "CreateReviewRequest",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.CreateReviewRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "code 40001 with field errors",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/validation.FieldError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comment-ms/v1/merchant/reviews/{review_id}/restore": {
+            "post": {
+                "description": "Take a review out of the trash. It keeps its likes but is no longer pinned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Restore a deleted review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comment-ms/v1/merchant/reviews/{review_id}/revisions": {
+            "get": {
+                "description": "Get the versions of a review replaced by edits of its author, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Get review revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.ReviewRevisionsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
@@ -748,6 +2366,23 @@ const docTemplate = `{
                 }
             }
         },
+        "types.EditReviewRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "pic_info": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "stars": {
+                    "type": "integer"
+                }
+            }
+        },
         "types.LikeRequest": {
             "type": "object",
             "properties": {
@@ -759,20 +2394,88 @@ const docTemplate = `{
         "types.ListReviewRequest": {
             "type": "object",
             "properties": {
+                "created_from": {
+                    "description": "inclusive",
+                    "type": "string"
+                },
+                "created_to": {
+                    "description": "exclusive",
+                    "type": "string"
+                },
+                "cursor": {
+                    "description": "next_cursor of the previous page",
+                    "type": "string"
+                },
+                "has_photos": {
+                    "type": "boolean"
+                },
+                "has_reply": {
+                    "description": "has a reply of the merchant",
+                    "type": "boolean"
+                },
+                "is_anonymous": {
+                    "type": "boolean"
+                },
+                "keyword": {
+                    "description": "case insensitive, matched in the content",
+                    "type": "string"
+                },
+                "limit": {
+                    "description": "page size, 0 means default",
+                    "type": "integer"
+                },
+                "max_stars": {
+                    "type": "integer"
+                },
+                "min_stars": {
+                    "description": "filters of the merchant review list, the other lists ignore them",
+                    "type": "integer"
+                },
                 "product_id": {
+                    "description": "required by the merchant review list",
                     "type": "integer"
                 },
+                "sort": {
+                    "description": "newest (default), oldest, stars_desc, stars_asc, likes or helpful",
+                    "type": "string"
+                },
                 "stars": {
                     "description": "0 means any stars",
                     "type": "integer"
+                },
+                "status": {
+                    "description": "moderation status, empty means approved",
+                    "type": "string"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
         "types.ListReviewResponse": {
             "type": "object",
             "properties": {
+                "next_cursor": {
+                    "description": "empty when there are no more pages",
+                    "type": "string"
+                },
                 "pinned_review": {
-                    "$ref": "#/definitions/types.ReviewInfo"
+                    "description": "first of pinned_reviews, kept for older clients",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.ReviewInfo"
+                        }
+                    ]
+                },
+                "pinned_reviews": {
+                    "description": "in pin position order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ReviewInfo"
+                    }
                 },
                 "review_list": {
                     "type": "array",
@@ -782,11 +2485,60 @@ const docTemplate = `{
                 }
             }
         },
+        "types.ModerateReviewRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "required unless the review is approved",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "approved, rejected or hidden",
+                    "type": "string"
+                }
+            }
+        },
         "types.PinReviewRequest": {
             "type": "object",
             "properties": {
+                "expires_at": {
+                    "description": "optional, the pin is dropped after this time",
+                    "type": "string"
+                },
                 "is_pinned": {
+                    "description": "false unpins the review",
                     "type": "boolean"
+                },
+                "position": {
+                    "description": "1 based, 0 or past the end appends the review",
+                    "type": "integer"
+                }
+            }
+        },
+        "types.RatingSummary": {
+            "type": "object",
+            "properties": {
+                "average_stars": {
+                    "type": "number"
+                },
+                "photo_review_count": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "reply_count": {
+                    "type": "integer"
+                },
+                "star_histogram": {
+                    "description": "stars (1-5) -\u003e number of reviews",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total_count": {
+                    "type": "integer"
                 }
             }
         },
@@ -802,18 +2554,39 @@ const docTemplate = `{
                 "current_user_liked": {
                     "type": "boolean"
                 },
+                "deleted_at": {
+                    "description": "only set for reviews in the trash",
+                    "type": "string"
+                },
+                "deleted_by": {
+                    "type": "integer"
+                },
+                "edited_at": {
+                    "description": "set once the author edited the review",
+                    "type": "string"
+                },
+                "helpful_score": {
+                    "description": "ranks the most helpful sort",
+                    "type": "number"
+                },
                 "id": {
                     "type": "string"
                 },
                 "is_anonymous": {
                     "type": "boolean"
                 },
+                "is_merchant_reply": {
+                    "type": "boolean"
+                },
                 "is_pinned": {
                     "type": "boolean"
                 },
                 "likes": {
                     "type": "integer"
                 },
+                "moderation_reason": {
+                    "type": "string"
+                },
                 "parent_id": {
                     "type": "string"
                 },
@@ -823,16 +2596,123 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "pin_position": {
+                    "description": "1 based order among the product's pinned reviews",
+                    "type": "integer"
+                },
+                "pinned_until": {
+                    "description": "the pin expires after this time",
+                    "type": "string"
+                },
                 "product_id": {
                     "type": "integer"
                 },
+                "replies": {
+                    "description": "direct replies, oldest first, up to the configured depth",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ReviewInfo"
+                    }
+                },
+                "reply_count": {
+                    "description": "number of direct replies",
+                    "type": "integer"
+                },
+                "snippet": {
+                    "description": "search results only: HTML escaped excerpt, matches wrapped in \u003cem\u003e",
+                    "type": "string"
+                },
                 "stars": {
                     "type": "integer"
                 },
+                "status": {
+                    "description": "pending, approved, rejected or hidden",
+                    "type": "string"
+                },
+                "unhelpful_count": {
+                    "type": "integer"
+                },
                 "user_id": {
                     "type": "integer"
                 }
             }
+        },
+        "types.ReviewRevision": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "pic_info": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "revised_at": {
+                    "description": "when this version was replaced",
+                    "type": "string"
+                },
+                "stars": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.ReviewRevisionsResponse": {
+            "type": "object",
+            "properties": {
+                "review_id": {
+                    "type": "string"
+                },
+                "revisions": {
+                    "description": "oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ReviewRevision"
+                    }
+                }
+            }
+        },
+        "types.SearchReviewRequest": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "description": "next_cursor of the previous page",
+                    "type": "string"
+                },
+                "limit": {
+                    "description": "page size, 0 means default",
+                    "type": "integer"
+                },
+                "product_ids": {
+                    "description": "merchant search only, the merchant's products to search",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "q": {
+                    "type": "string"
+                },
+                "sort": {
+                    "description": "relevance (default) or a sort of the review lists",
+                    "type": "string"
+                }
+            }
+        },
+        "validation.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
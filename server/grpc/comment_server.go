@@ -0,0 +1,161 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/common/commentpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/service"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxBatchProductIDs bounds BatchGetProductRatings so one call cannot fan out
+// to an unbounded number of aggregations.
+const maxBatchProductIDs = 100
+
+// CommentService serves the internal comment RPCs on top of service.ReviewService,
+// so the gRPC and HTTP paths share the same logic.
+type CommentService struct {
+	commentpb.UnimplementedCommentServiceServer
+	reviewService service.ReviewService
+}
+
+func NewCommentService(reviewService service.ReviewService) *CommentService {
+	return &CommentService{reviewService: reviewService}
+}
+
+func (s *CommentService) GetProductRatingSummary(ctx context.Context, in *commentpb.GetProductRatingSummaryRequest) (*commentpb.GetProductRatingSummaryResponse, error) {
+	if in.GetProductId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid product_id")
+	}
+	summary, err := s.reviewService.GetRatingSummary(ctx, int(in.GetProductId()))
+	if err != nil {
+		return nil, toStatusErr(ctx, "GetProductRatingSummary", err)
+	}
+	return &commentpb.GetProductRatingSummaryResponse{Summary: toPbRatingSummary(summary)}, nil
+}
+
+func (s *CommentService) BatchGetProductRatings(ctx context.Context, in *commentpb.BatchGetProductRatingsRequest) (*commentpb.BatchGetProductRatingsResponse, error) {
+	if len(in.GetProductIds()) > maxBatchProductIDs {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d product_ids per call", maxBatchProductIDs)
+	}
+	productIds := make([]int, 0, len(in.GetProductIds()))
+	for _, id := range in.GetProductIds() {
+		if id <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid product_id %d", id)
+		}
+		productIds = append(productIds, int(id))
+	}
+	summaries, err := s.reviewService.BatchGetRatingSummary(ctx, productIds)
+	if err != nil {
+		return nil, toStatusErr(ctx, "BatchGetProductRatings", err)
+	}
+	resp := &commentpb.BatchGetProductRatingsResponse{
+		Summaries: make(map[int32]*commentpb.RatingSummary, len(summaries)),
+	}
+	for productId, summary := range summaries {
+		resp.Summaries[int32(productId)] = toPbRatingSummary(summary)
+	}
+	return resp, nil
+}
+
+func (s *CommentService) ListReviewsByProduct(ctx context.Context, in *commentpb.ListReviewsByProductRequest) (*commentpb.ListReviewsByProductResponse, error) {
+	if in.GetProductId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid product_id")
+	}
+	list, err := s.reviewService.GetListByProductID(ctx, types.ListReviewRequest{
+		ProductID: int(in.GetProductId()),
+		Limit:     int(in.GetLimit()),
+		Cursor:    in.GetCursor(),
+		Sort:      in.GetSort(),
+	}, 0)
+	if err != nil {
+		return nil, toStatusErr(ctx, "ListReviewsByProduct", err)
+	}
+	resp := &commentpb.ListReviewsByProductResponse{
+		Reviews:    make([]*commentpb.Review, len(list.ReviewList)),
+		NextCursor: list.NextCursor,
+	}
+	for idx, review := range list.ReviewList {
+		resp.Reviews[idx] = toPbReview(review)
+	}
+	if list.PinnedReview != nil {
+		resp.PinnedReview = toPbReview(*list.PinnedReview)
+	}
+	resp.PinnedReviews = make([]*commentpb.Review, len(list.PinnedReviews))
+	for idx, review := range list.PinnedReviews {
+		resp.PinnedReviews[idx] = toPbReview(review)
+	}
+	return resp, nil
+}
+
+func (s *CommentService) GetReview(ctx context.Context, in *commentpb.GetReviewRequest) (*commentpb.GetReviewResponse, error) {
+	if in.GetReviewId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty review_id")
+	}
+	review, err := s.reviewService.GetReview(ctx, in.GetReviewId(), 0)
+	if err != nil {
+		return nil, toStatusErr(ctx, "GetReview", err)
+	}
+	return &commentpb.GetReviewResponse{Review: toPbReview(review)}, nil
+}
+
+// toStatusErr maps service errors to gRPC status codes.
+func toStatusErr(ctx context.Context, method string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidCursor), errors.Is(err, service.ErrInvalidSort):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrReviewNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		log.Ctx(ctx).Errorf("%s: failed, err %s", method, err.Error())
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func toPbRatingSummary(summary types.RatingSummary) *commentpb.RatingSummary {
+	histogram := make(map[int32]int32, len(summary.StarHistogram))
+	for stars, cnt := range summary.StarHistogram {
+		histogram[int32(stars)] = int32(cnt)
+	}
+	return &commentpb.RatingSummary{
+		ProductId:        int32(summary.ProductID),
+		TotalCount:       int32(summary.TotalCount),
+		AverageStars:     summary.AverageStars,
+		StarHistogram:    histogram,
+		PhotoReviewCount: int32(summary.PhotoReviewCount),
+		ReplyCount:       int32(summary.ReplyCount),
+	}
+}
+
+func toPbReview(review types.ReviewInfo) *commentpb.Review {
+	var replies []*commentpb.Review
+	for _, reply := range review.Replies {
+		replies = append(replies, toPbReview(reply))
+	}
+	var editedAt *timestamppb.Timestamp
+	if review.EditedAt != nil {
+		editedAt = timestamppb.New(*review.EditedAt)
+	}
+	return &commentpb.Review{
+		Id:          review.ID,
+		Content:     review.Content,
+		UserId:      int32(review.UserID),
+		ProductId:   int32(review.ProductID),
+		ParentId:    review.ParentID,
+		Stars:       int32(review.Stars),
+		IsAnonymous: review.IsAnonymous,
+		PicInfo:     review.PicInfo,
+		CreatedAt:   timestamppb.New(review.CreatedAt),
+		Likes:       int32(review.Likes),
+		IsPinned:    review.IsPinned,
+		PinPosition: int32(review.PinPosition),
+		ReplyCount:  int32(review.ReplyCount),
+		Replies:     replies,
+		EditedAt:    editedAt,
+	}
+}
@@ -0,0 +1,150 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/common/commentpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/service"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/service/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+)
+
+func init() {
+	logger, _ := zap.NewDevelopment()
+	log.Logger = logger.Sugar()
+}
+
+func TestGetProductRatingSummary_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockReviewService(ctrl)
+	srv := NewCommentService(mockSvc)
+
+	mockSvc.EXPECT().GetRatingSummary(gomock.Any(), 5).Return(types.RatingSummary{
+		ProductID:     5,
+		TotalCount:    2,
+		AverageStars:  4.5,
+		StarHistogram: map[int]int{4: 1, 5: 1},
+	}, nil)
+
+	resp, err := srv.GetProductRatingSummary(context.Background(), &commentpb.GetProductRatingSummaryRequest{ProductId: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), resp.GetSummary().GetTotalCount())
+	assert.Equal(t, 4.5, resp.GetSummary().GetAverageStars())
+	assert.Equal(t, int32(1), resp.GetSummary().GetStarHistogram()[5])
+}
+
+func TestGetProductRatingSummary_InvalidProductID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := NewCommentService(mocks.NewMockReviewService(ctrl))
+
+	_, err := srv.GetProductRatingSummary(context.Background(), &commentpb.GetProductRatingSummaryRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestBatchGetProductRatings_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockReviewService(ctrl)
+	srv := NewCommentService(mockSvc)
+
+	mockSvc.EXPECT().BatchGetRatingSummary(gomock.Any(), []int{1, 2}).Return(map[int]types.RatingSummary{
+		1: {ProductID: 1, TotalCount: 3},
+		2: {ProductID: 2},
+	}, nil)
+
+	resp, err := srv.BatchGetProductRatings(context.Background(), &commentpb.BatchGetProductRatingsRequest{ProductIds: []int32{1, 2}})
+	assert.NoError(t, err)
+	assert.Len(t, resp.GetSummaries(), 2)
+	assert.Equal(t, int32(3), resp.GetSummaries()[1].GetTotalCount())
+}
+
+func TestBatchGetProductRatings_TooMany(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := NewCommentService(mocks.NewMockReviewService(ctrl))
+
+	ids := make([]int32, maxBatchProductIDs+1)
+	for i := range ids {
+		ids[i] = int32(i + 1)
+	}
+	_, err := srv.BatchGetProductRatings(context.Background(), &commentpb.BatchGetProductRatingsRequest{ProductIds: ids})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListReviewsByProduct_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockReviewService(ctrl)
+	srv := NewCommentService(mockSvc)
+
+	now := time.Now()
+	mockSvc.EXPECT().GetListByProductID(gomock.Any(), types.ListReviewRequest{ProductID: 9, Limit: 10, Cursor: "c1", Sort: "likes"}, 0).
+		Return(types.ListReviewResponse{
+			ReviewList:   []types.ReviewInfo{{ID: "r1", Stars: 5, CreatedAt: now}},
+			PinnedReview: &types.ReviewInfo{ID: "p1", IsPinned: true},
+			NextCursor:   "c2",
+		}, nil)
+
+	resp, err := srv.ListReviewsByProduct(context.Background(), &commentpb.ListReviewsByProductRequest{ProductId: 9, Limit: 10, Cursor: "c1", Sort: "likes"})
+	assert.NoError(t, err)
+	assert.Len(t, resp.GetReviews(), 1)
+	assert.Equal(t, "r1", resp.GetReviews()[0].GetId())
+	assert.True(t, resp.GetReviews()[0].GetCreatedAt().AsTime().Equal(now))
+	assert.Equal(t, "p1", resp.GetPinnedReview().GetId())
+	assert.Equal(t, "c2", resp.GetNextCursor())
+}
+
+func TestListReviewsByProduct_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockReviewService(ctrl)
+	srv := NewCommentService(mockSvc)
+
+	mockSvc.EXPECT().GetListByProductID(gomock.Any(), gomock.Any(), 0).Return(types.ListReviewResponse{}, service.ErrInvalidCursor)
+
+	_, err := srv.ListReviewsByProduct(context.Background(), &commentpb.ListReviewsByProductRequest{ProductId: 9, Cursor: "bad"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetReview_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockReviewService(ctrl)
+	srv := NewCommentService(mockSvc)
+
+	mockSvc.EXPECT().GetReview(gomock.Any(), "missing", 0).Return(types.ReviewInfo{}, service.ErrReviewNotFound)
+
+	_, err := srv.GetReview(context.Background(), &commentpb.GetReviewRequest{ReviewId: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGetReview_InternalError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockReviewService(ctrl)
+	srv := NewCommentService(mockSvc)
+
+	mockSvc.EXPECT().GetReview(gomock.Any(), "r1", 0).Return(types.ReviewInfo{}, assert.AnError)
+
+	_, err := srv.GetReview(context.Background(), &commentpb.GetReviewRequest{ReviewId: "r1"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
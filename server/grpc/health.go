@@ -0,0 +1,69 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/common/commentpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/health"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+)
+
+const defaultCheckInterval = 5 * time.Second
+
+// healthServices are reported by the grpc health service: the server as a
+// whole and the comment service.
+var healthServices = []string{"", commentpb.CommentService_ServiceDesc.ServiceName}
+
+// newHealthServer returns a grpc.health.v1 server reporting NOT_SERVING
+// until watchHealth finds the service ready.
+func newHealthServer() *grpchealth.Server {
+	server := grpchealth.NewServer()
+	for _, service := range healthServices {
+		server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return server
+}
+
+// watchHealth runs the readiness checks every check interval, and whenever
+// the readiness of the service changes, and sets the serving status of
+// server to match /readyz until ctx is done.
+func watchHealth(ctx context.Context, server *grpchealth.Server) {
+	interval := defaultCheckInterval
+	if config.Config.HealthConfig != nil && config.Config.HealthConfig.CheckInterval > 0 {
+		interval = config.Config.HealthConfig.CheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_NOT_SERVING
+	for {
+		report := health.Check(ctx)
+		status := servingStatus(report)
+		for _, service := range healthServices {
+			server.SetServingStatus(service, status)
+		}
+		if status != last {
+			log.Logger.Infow("serving status changed", "status", status.String(), "dependencies", report.Dependencies)
+			last = status
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-health.Changed():
+		}
+	}
+}
+
+func servingStatus(report *health.Report) healthpb.HealthCheckResponse_ServingStatus {
+	if report.Up() {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
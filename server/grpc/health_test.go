@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/common/commentpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/health"
+)
+
+func TestWatchHealth(t *testing.T) {
+	saved := config.Config.HealthConfig
+	config.Config.HealthConfig = &config.HealthConfig{CheckInterval: 10 * time.Millisecond}
+	defer func() { config.Config.HealthConfig = saved }()
+	var down atomic.Bool
+	health.Register("store", func(ctx context.Context) error {
+		if down.Load() {
+			return errors.New("unreachable")
+		}
+		return nil
+	})
+
+	server := newHealthServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watchHealth(ctx, server)
+
+	statusOf := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		assert.NoError(t, err)
+		return resp.GetStatus()
+	}
+	eventually := func(want healthpb.HealthCheckResponse_ServingStatus) {
+		assert.Eventually(t, func() bool {
+			return statusOf("") == want && statusOf(commentpb.CommentService_ServiceDesc.ServiceName) == want
+		}, time.Second, 5*time.Millisecond, "want %v", want)
+	}
+
+	// not serving until startup completes
+	eventually(healthpb.HealthCheckResponse_NOT_SERVING)
+	health.SetReady(true)
+	eventually(healthpb.HealthCheckResponse_SERVING)
+	down.Store(true)
+	eventually(healthpb.HealthCheckResponse_NOT_SERVING)
+	down.Store(false)
+	eventually(healthpb.HealthCheckResponse_SERVING)
+	health.SetReady(false)
+	eventually(healthpb.HealthCheckResponse_NOT_SERVING)
+}
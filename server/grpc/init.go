@@ -1,24 +1,40 @@
 package grpc
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
 	"time"
 
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/common/demopb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/common/commentpb"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/service"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 )
 
-func Init(exitSig chan os.Signal) {
+var (
+	grpcServer   *grpc.Server
+	healthServer *grpchealth.Server
+	listener     net.Listener
+	stopWatch    context.CancelFunc
+)
+
+// Init builds the grpc server, with the grpc.health.v1 service, and starts
+// listening, calls are served from Serve.
+func Init() {
 	ipPort := fmt.Sprintf("%s:%d", config.Config.GrpcConfig.Host, config.Config.GrpcConfig.Port)
-	listener, err := net.Listen("tcp", ipPort)
+	var err error
+	listener, err = net.Listen("tcp", ipPort)
 	if err != nil {
 		log.Logger.Fatalf("Failed to listen: %v", err)
-		exitSig <- os.Interrupt
-		return
 	}
 	// Set up gRPC options for timeout and connection pooling
 	opts := []grpc.ServerOption{
@@ -26,13 +42,51 @@ func Init(exitSig chan os.Signal) {
 		grpc.MaxConcurrentStreams(uint32(config.Config.GrpcConfig.MaxPoolSize)),                      // Set maximum concurrent streams
 		grpc.MaxRecvMsgSize(1024 * 1024), // Set maximum receive message size (1MB here)
 		grpc.MaxSendMsgSize(1024 * 1024), // Set maximum send message size (1MB here)
+		// Accept the keepalive pings of the comment client (every 30s by default)
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             10 * time.Second,
+			PermitWithoutStream: true,
+		}),
+		grpc.ChainUnaryInterceptor(loggingInterceptor(), metrics.UnaryServerInterceptor()),
+		// a span per call, continuing the trace of the caller, health checks
+		// are probed all the time and left out
+		grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))),
 	}
-	grpcServer := grpc.NewServer(opts...)
-	demopb.RegisterDemoServiceServer(grpcServer, &DemoService{})
+	grpcServer = grpc.NewServer(opts...)
+	commentpb.RegisterCommentServiceServer(grpcServer, NewCommentService(service.GetReviewServiceInstance()))
+	healthServer = newHealthServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	var watchCtx context.Context
+	watchCtx, stopWatch = context.WithCancel(context.Background())
+	go watchHealth(watchCtx, healthServer)
+}
 
-	log.Logger.Infof("Server is running on %s", ipPort)
+// Serve serves grpc calls until Shutdown.
+func Serve(exitSig chan os.Signal) {
+	log.Logger.Infof("Server is running on %s", listener.Addr())
 	if err := grpcServer.Serve(listener); err != nil {
 		log.Logger.Fatal("Failed to serve: %v", err)
 		exitSig <- os.Interrupt
 	}
 }
+
+// Shutdown stops accepting connections and waits for the calls in flight
+// until ctx is done, then closes the connections left.
+func Shutdown(ctx context.Context) error {
+	// health checks answer NOT_SERVING from now on
+	stopWatch()
+	healthServer.Shutdown()
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		grpcServer.Stop()
+		log.Logger.Errorf("grpc shutdown failed: %v", ctx.Err())
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+)
+
+// quietMethods are probed all the time and get no access log.
+var quietMethods = map[string]bool{
+	healthpb.Health_Check_FullMethodName: true,
+}
+
+// loggingInterceptor gives every call a logger with the request id of the
+// caller, or a new one, the method and the trace id, and logs the call once
+// it returns.
+func loggingInterceptor() grpc.UnaryServerInterceptor {
+	requestIDKey := strings.ToLower(log.RequestIDHeader)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if ids := md.Get(requestIDKey); len(ids) > 0 {
+				id = ids[0]
+			}
+		}
+		if !log.ValidRequestID(id) {
+			id = log.NewRequestID()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+
+		fields := []interface{}{"request_id", id, "method", info.FullMethod}
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+			fields = append(fields, "trace_id", spanContext.TraceID().String())
+		}
+		ctx = log.WithFields(ctx, fields...)
+
+		resp, err := handler(ctx, req)
+		if quietMethods[info.FullMethod] {
+			return resp, err
+		}
+		log.Ctx(ctx).Infow("access",
+			"code", status.Code(err).String(),
+			"latency_ms", time.Since(start).Milliseconds(),
+		)
+		return resp, err
+	}
+}
@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+)
+
+// Statuses of the service and of its dependencies.
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+const defaultTimeout = 2 * time.Second
+
+type dependency struct {
+	name string
+	ping func(ctx context.Context) error
+}
+
+var (
+	mu           sync.Mutex
+	dependencies []dependency
+)
+
+// DependencyStatus is the outcome of pinging a dependency.
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the readiness of the service. It is up when the service accepts
+// traffic and every dependency answered its ping in time.
+type Report struct {
+	Status       string                      `json:"status"`
+	Serving      bool                        `json:"serving"` // false before startup completes and while shutting down
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+// Up reports whether the service is ready.
+func (r *Report) Up() bool {
+	return r.Status == StatusUp
+}
+
+// Register adds a dependency the service needs to serve, pinged by Check.
+func Register(name string, ping func(ctx context.Context) error) {
+	mu.Lock()
+	defer mu.Unlock()
+	dependencies = append(dependencies, dependency{name: name, ping: ping})
+}
+
+// Check pings the dependencies in parallel, each within the health timeout.
+func Check(ctx context.Context) *Report {
+	timeout := defaultTimeout
+	if config.Config.HealthConfig != nil && config.Config.HealthConfig.Timeout > 0 {
+		timeout = config.Config.HealthConfig.Timeout
+	}
+	mu.Lock()
+	deps := append([]dependency(nil), dependencies...)
+	mu.Unlock()
+
+	statuses := make([]DependencyStatus, len(deps))
+	var wg sync.WaitGroup
+	for idx := range deps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[idx] = ping(ctx, deps[idx], timeout)
+		}()
+	}
+	wg.Wait()
+
+	report := &Report{Status: StatusUp, Serving: Ready(), Dependencies: make(map[string]DependencyStatus, len(deps))}
+	if !report.Serving {
+		report.Status = StatusDown
+	}
+	for idx, dep := range deps {
+		report.Dependencies[dep.name] = statuses[idx]
+		if statuses[idx].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func ping(ctx context.Context, dep dependency, timeout time.Duration) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	err := dep.ping(ctx)
+	status := DependencyStatus{Status: StatusUp, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+	return status
+}
@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+)
+
+// withDependencies replaces the registered dependencies for the test.
+func withDependencies(t *testing.T, deps ...dependency) {
+	saved := dependencies
+	dependencies = deps
+	t.Cleanup(func() { dependencies = saved })
+}
+
+func up(ctx context.Context) error { return nil }
+
+func TestCheck_Up(t *testing.T) {
+	withDependencies(t, dependency{"mongo", up}, dependency{"redis", up})
+	SetReady(true)
+	defer SetReady(false)
+
+	report := Check(context.Background())
+	assert.True(t, report.Up())
+	assert.True(t, report.Serving)
+	assert.Equal(t, StatusUp, report.Dependencies["mongo"].Status)
+	assert.Equal(t, StatusUp, report.Dependencies["redis"].Status)
+}
+
+func TestCheck_DependencyDown(t *testing.T) {
+	withDependencies(t, dependency{"mongo", up}, dependency{"redis", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}})
+	SetReady(true)
+	defer SetReady(false)
+
+	report := Check(context.Background())
+	assert.False(t, report.Up())
+	assert.Equal(t, StatusUp, report.Dependencies["mongo"].Status)
+	assert.Equal(t, DependencyStatus{Status: StatusDown, Error: "connection refused"}, report.Dependencies["redis"])
+}
+
+func TestCheck_Timeout(t *testing.T) {
+	saved := config.Config.HealthConfig
+	config.Config.HealthConfig = &config.HealthConfig{Timeout: 20 * time.Millisecond}
+	defer func() { config.Config.HealthConfig = saved }()
+	withDependencies(t, dependency{"mongo", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+	SetReady(true)
+	defer SetReady(false)
+
+	start := time.Now()
+	report := Check(context.Background())
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, report.Up())
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Dependencies["mongo"].Error)
+}
+
+func TestCheck_NotServing(t *testing.T) {
+	withDependencies(t, dependency{"mongo", up})
+	SetReady(false)
+
+	report := Check(context.Background())
+	assert.False(t, report.Up())
+	assert.False(t, report.Serving)
+	assert.Equal(t, StatusUp, report.Dependencies["mongo"].Status)
+}
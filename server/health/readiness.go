@@ -0,0 +1,33 @@
+package health
+
+import (
+	"sync/atomic"
+)
+
+var (
+	ready atomic.Bool
+	// changed wakes up the watcher of the readiness, see Changed
+	changed = make(chan struct{}, 1)
+)
+
+// SetReady marks whether the service accepts traffic. It is set once the
+// servers listen and cleared when shutdown starts, so load balancers stop
+// routing to an instance that is draining.
+func SetReady(isReady bool) {
+	ready.Store(isReady)
+	select {
+	case changed <- struct{}{}:
+	default: // a change is pending already
+	}
+}
+
+// Ready reports whether the service accepts traffic.
+func Ready() bool {
+	return ready.Load()
+}
+
+// Changed is signalled when SetReady is called, so the grpc health status
+// follows without waiting for the next check. It has a single reader.
+func Changed() <-chan struct{} {
+	return changed
+}
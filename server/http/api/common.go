@@ -1,16 +1,24 @@
 package api
 
 import (
+	"errors"
+	"net/http"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/service"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/validation"
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	SUCCESS = 0
-	ERROR   = 500
+	SUCCESS           = 0
+	ERROR             = 500
+	VALIDATION_FAILED = 40001 // data holds the field errors
 )
 
 var MsgFlags = map[int]string{
-	SUCCESS: "ok",
+	SUCCESS:           "ok",
+	VALIDATION_FAILED: "validation failed",
 }
 
 // GetMsg 获取状态码对应信息
@@ -62,3 +70,54 @@ func RespError(ctx *gin.Context, err error, code ...int) *Response {
 
 	return r
 }
+
+// respondError writes the error returned by the service layer. Validation
+// errors are returned field by field under the VALIDATION_FAILED code.
+func respondError(c *gin.Context, err error) {
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		respondValidation(c, fieldErrs)
+		return
+	}
+	c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
+}
+
+// respondBindError writes an error of decoding the request body.
+func respondBindError(c *gin.Context, err error) {
+	respondValidation(c, validation.FromBindError(err))
+}
+
+func respondValidation(c *gin.Context, fieldErrs validation.Errors) {
+	c.JSON(http.StatusBadRequest, data.BaseResponse{
+		Code:   VALIDATION_FAILED,
+		ErrMsg: GetMsg(VALIDATION_FAILED),
+		Data:   fieldErrs,
+	})
+}
+
+// errStatus maps an error returned by the service layer to an HTTP status.
+func errStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidCursor),
+		errors.Is(err, service.ErrInvalidSort),
+		errors.Is(err, service.ErrParentNotFound),
+		errors.Is(err, service.ErrParentProductMismatch),
+		errors.Is(err, service.ErrInvalidStatus),
+		errors.Is(err, service.ErrReasonRequired),
+		errors.Is(err, service.ErrInvalidPinExpiry),
+		errors.Is(err, service.ErrCannotPinReply):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrReviewNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrReviewNotApproved),
+		errors.Is(err, service.ErrEditWindowClosed),
+		errors.Is(err, service.ErrTooManyPinned),
+		errors.Is(err, service.ErrPinBusy),
+		errors.Is(err, service.ErrPinConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/service"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+	"github.com/gin-gonic/gin"
+)
+
+// List moderation queue
+// @Summary List moderation queue
+// @Description Get a page of reviews and replies of one of the merchant's products in a moderation status, pending by default, newest first
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param product_id query int true "Product ID, one of the merchant's products"
+// @Param status query string false "Moderation status" Enums(pending, approved, rejected, hidden)
+// @Param limit query int false "Page size, default 20, max 100"
+// @Param cursor query string false "next_cursor returned by the previous page"
+// @Success 200 {object} data.BaseResponse{data=types.ListReviewResponse}
+// @Failure 400 {object} data.BaseResponse{data=[]validation.FieldError} "code 40001 with field errors"
+// @Failure 403 {object} data.BaseResponse{data=string}
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/merchant/reviews/moderation [get]
+func ListModerationQueue(c *gin.Context) {
+	var req types.ModerationQueueRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	userID := c.Value("userID").(int)
+	resp, err := service.GetReviewServiceInstance().ListModerationQueue(c, req, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, resp))
+}
+
+// Moderate review
+// @Summary Moderate a review
+// @Description Approve, reject or hide a review or reply. A reason is required unless the review is approved
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param review_id path string true "Review ID"
+// @Param req body types.ModerateReviewRequest true "ModerateReviewRequest"
+// @Success 200 {object} data.BaseResponse{data=string}
+// @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 403 {object} data.BaseResponse{data=string}
+// @Failure 404 {object} data.BaseResponse{data=string}
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/merchant/reviews/{review_id}/moderation [post]
+func ModerateReview(c *gin.Context) {
+	reviewID := c.Param("review_id")
+	if reviewID == "" {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: "empty review_id"})
+		return
+	}
+	var req types.ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	userID := c.Value("userID").(int)
+	err := service.GetReviewServiceInstance().ModerateReview(c, reviewID, req, userID)
+	if err != nil {
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, "moderate success"))
+}
@@ -20,19 +20,19 @@ import (
 // @Param user body types.CreateReviewRequest true "CreateReviewRequest"
 // @Param client path string true "Client identifier" Enums(customer, merchant)
 // @Success 200	{object} data.BaseResponse{data=types.CreateReviewRequest}
-// @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 400 {object} data.BaseResponse{data=[]validation.FieldError} "code 40001 with field errors"
 // @Failure 500 {object} data.BaseResponse{data=string}
 // @Router /comment-ms/v1/customer/reviews [post]
 func CreateReview(c *gin.Context) {
 	var req types.CreateReviewRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: err.Error()})
+		respondBindError(c, err)
 		return
 	}
 	userID := c.Value("userID").(int)
 	err := service.GetReviewServiceInstance().CreateReview(c, req, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, data.BaseResponse{ErrMsg: err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, RespSuccess(c, "create review success"))
@@ -40,7 +40,7 @@ func CreateReview(c *gin.Context) {
 
 // Like review
 // @Summary Like a review
-// @Description Like a review by id
+// @Description Like a review by id. Only approved reviews that are not deleted can be liked
 // @Tags Review
 // @Accept json
 // @Produce json
@@ -48,6 +48,7 @@ func CreateReview(c *gin.Context) {
 // @Param client path string true "Client identifier" Enums(customer, merchant)
 // @Success 200 {object} data.BaseResponse{data=string}
 // @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 404 {object} data.BaseResponse{data=string}
 // @Failure 500 {object} data.BaseResponse{data=string}
 // @Router /comment-ms/v1/customer/reviews/{review_id}/like [post]
 func Like(c *gin.Context) {
@@ -65,41 +66,133 @@ func Like(c *gin.Context) {
 	userID := c.Value("userID").(int)
 	err := service.GetReviewServiceInstance().Like(c, req, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, data.BaseResponse{ErrMsg: err.Error()})
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, RespSuccess(c, "like success"))
 }
 
+// Unlike review
+// @Summary Unlike a review
+// @Description Take back the current user's like on a review by id
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param review_id path string true "Review ID"
+// @Success 200 {object} data.BaseResponse{data=string}
+// @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 404 {object} data.BaseResponse{data=string}
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/customer/reviews/{review_id}/like [delete]
+func Unlike(c *gin.Context) {
+	reviewID := c.Param("review_id")
+	if reviewID == "" {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: "empty review_id"})
+		return
+	}
+	userID := c.Value("userID").(int)
+	err := service.GetReviewServiceInstance().Unlike(c, types.LikeRequest{ReviewID: reviewID}, userID)
+	if err != nil {
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, "unlike success"))
+}
+
+// MarkUnhelpful review
+// @Summary Mark a review as unhelpful
+// @Description Record that the current user found a review unhelpful, it lowers the review's helpful score. Only approved reviews that are not deleted can be voted on
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param review_id path string true "Review ID"
+// @Success 200 {object} data.BaseResponse{data=string}
+// @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 404 {object} data.BaseResponse{data=string}
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/customer/reviews/{review_id}/unhelpful [post]
+func MarkUnhelpful(c *gin.Context) {
+	reviewID := c.Param("review_id")
+	if reviewID == "" {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: "empty review_id"})
+		return
+	}
+	userID := c.Value("userID").(int)
+	err := service.GetReviewServiceInstance().MarkUnhelpful(c, reviewID, userID)
+	if err != nil {
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, "mark unhelpful success"))
+}
+
+// UnmarkUnhelpful review
+// @Summary Take back an unhelpful vote
+// @Description Take back the current user's unhelpful vote on a review by id
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param review_id path string true "Review ID"
+// @Success 200 {object} data.BaseResponse{data=string}
+// @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 404 {object} data.BaseResponse{data=string}
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/customer/reviews/{review_id}/unhelpful [delete]
+func UnmarkUnhelpful(c *gin.Context) {
+	reviewID := c.Param("review_id")
+	if reviewID == "" {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: "empty review_id"})
+		return
+	}
+	userID := c.Value("userID").(int)
+	err := service.GetReviewServiceInstance().UnmarkUnhelpful(c, reviewID, userID)
+	if err != nil {
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, "unmark unhelpful success"))
+}
+
 // Get reviews by current user
 // @Summary Get reviews by user
-// @Description Get list of reviews created by current authenticated user
+// @Description Get a page of reviews created by current authenticated user, newest first unless sorted otherwise
 // @Tags Review
 // @Accept json
 // @Produce json
 // @Param client path string true "Client identifier" Enums(customer, merchant)
-// @Success 200 {object} data.BaseResponse{data=[]types.ReviewInfo}
+// @Param limit query int false "Page size, default 20, max 100"
+// @Param cursor query string false "next_cursor returned by the previous page"
+// @Param sort query string false "Order of the reviews" Enums(newest, oldest, stars_desc, stars_asc, likes, helpful) default(newest)
+// @Success 200 {object} data.BaseResponse{data=types.ListReviewResponse}
 // @Failure 400 {object} data.BaseResponse{data=string}
 // @Failure 500 {object} data.BaseResponse{data=string}
 // @Router /comment-ms/v1/customer/reviews/user [get]
 func GetListByUserID(c *gin.Context) {
+	var req types.ListReviewRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
 	userID := c.Value("userID").(int)
-	list, err := service.GetReviewServiceInstance().GetListByUserID(c, userID)
+	resp, err := service.GetReviewServiceInstance().GetListByUserID(c, req, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, data.BaseResponse{ErrMsg: err.Error()})
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, RespSuccess(c, list))
+	c.JSON(http.StatusOK, RespSuccess(c, resp))
 }
 
 // Get reviews by product id
 // @Summary Get reviews by product
-// @Description Get list of reviews for a product
+// @Description Get a page of reviews for a product, newest first unless sorted otherwise
 // @Tags Review
 // @Accept json
 // @Produce json
 // @Param product_id path int true "Product ID"
 // @Param client path string true "Client identifier" Enums(customer, merchant)
+// @Param limit query int false "Page size, default 20, max 100"
+// @Param cursor query string false "next_cursor returned by the previous page"
+// @Param sort query string false "Order of the reviews" Enums(newest, oldest, stars_desc, stars_asc, likes, helpful) default(newest)
 // @Success 200 {object} data.BaseResponse{data=types.ListReviewResponse}
 // @Failure 400 {object} data.BaseResponse{data=string}
 // @Failure 500 {object} data.BaseResponse{data=string}
@@ -111,33 +204,65 @@ func GetListByProductID(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: "invalid product_id"})
 		return
 	}
+	var req types.ListReviewRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	req.ProductID = pid
 	userID := 0
 	if v := c.Value("userID"); v != nil {
 		userID = v.(int)
 	}
-	list, err := service.GetReviewServiceInstance().GetListByProductID(c, pid, userID)
+	list, err := service.GetReviewServiceInstance().GetListByProductID(c, req, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, data.BaseResponse{ErrMsg: err.Error()})
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, RespSuccess(c, list))
 }
 
+// GetRatingSummary
+// @Summary Get product rating summary
+// @Description Get review count, average stars, star histogram, photo review count and reply count of a product
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param product_id path int true "Product ID"
+// @Success 200 {object} data.BaseResponse{data=types.RatingSummary}
+// @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/customer/reviews/product/{product_id}/summary [get]
+func GetRatingSummary(c *gin.Context) {
+	pid, err := strconv.Atoi(c.Param("product_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: "invalid product_id"})
+		return
+	}
+	summary, err := service.GetReviewServiceInstance().GetRatingSummary(c, pid)
+	if err != nil {
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, summary))
+}
+
 // ListReviewsByFilter
-// @Summary List reviews by product and stars
-// @Description Filter reviews by product_id and stars (0 means any), ordered by created_at desc
+// @Summary Search reviews
+// @Description Filter the reviews of one of the merchant's products by stars or a star range, created_at range, photos, anonymity, merchant reply, moderation status, authors and a keyword in the content. Newest first unless sorted otherwise, one page at a time
 // @Tags Review
 // @Accept json
 // @Produce json
 // @Param filter body types.ListReviewRequest true "ListReviewRequest"
-// @Success 200 {object} data.BaseResponse{data=[]types.ReviewInfo}
-// @Failure 400 {object} data.BaseResponse{data=string}
+// @Success 200 {object} data.BaseResponse{data=types.ListReviewResponse}
+// @Failure 400 {object} data.BaseResponse{data=[]validation.FieldError} "code 40001 with field errors"
+// @Failure 403 {object} data.BaseResponse{data=string}
 // @Failure 500 {object} data.BaseResponse{data=string}
 // @Router /comment-ms/v1/merchant/reviews/list [post]
 func ListReviewsByFilter(c *gin.Context) {
 	var req types.ListReviewRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: err.Error()})
+		respondBindError(c, err)
 		return
 	}
 	userID := 0
@@ -146,21 +271,89 @@ func ListReviewsByFilter(c *gin.Context) {
 	}
 	resp, err := service.GetReviewServiceInstance().GetListByQuery(c, req, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, data.BaseResponse{ErrMsg: err.Error()})
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, resp))
+}
+
+// SearchProductReviews
+// @Summary Search the reviews of a product
+// @Description Full-text search over the content of a product's reviews, the most relevant first. q supports "quoted phrases" and -negated terms, results carry a highlighted snippet
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param product_id path int true "Product ID"
+// @Param q query string true "Search query"
+// @Param limit query int false "Page size, default 20, max 100"
+// @Param cursor query string false "next_cursor returned by the previous page"
+// @Param sort query string false "Order of the results" Enums(relevance, newest, oldest, stars_desc, stars_asc, likes, helpful) default(relevance)
+// @Success 200 {object} data.BaseResponse{data=types.ListReviewResponse}
+// @Failure 400 {object} data.BaseResponse{data=[]validation.FieldError} "code 40001 with field errors"
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/customer/reviews/product/{product_id}/search [get]
+func SearchProductReviews(c *gin.Context) {
+	pid, err := strconv.Atoi(c.Param("product_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: "invalid product_id"})
+		return
+	}
+	var req types.SearchReviewRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	userID := 0
+	if v := c.Value("userID"); v != nil {
+		userID = v.(int)
+	}
+	resp, err := service.GetReviewServiceInstance().SearchProductReviews(c, pid, req, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, resp))
+}
+
+// SearchMerchantReviews
+// @Summary Search the reviews of the merchant's products
+// @Description Full-text search over the content of the reviews of the given products of the merchant, in every moderation status, the most relevant first. q supports "quoted phrases" and -negated terms, results carry a highlighted snippet
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param req body types.SearchReviewRequest true "SearchReviewRequest"
+// @Success 200 {object} data.BaseResponse{data=types.ListReviewResponse}
+// @Failure 400 {object} data.BaseResponse{data=[]validation.FieldError} "code 40001 with field errors"
+// @Failure 403 {object} data.BaseResponse{data=string}
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/merchant/reviews/search [post]
+func SearchMerchantReviews(c *gin.Context) {
+	var req types.SearchReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	userID := c.Value("userID").(int)
+	resp, err := service.GetReviewServiceInstance().SearchMerchantReviews(c, req, userID)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, RespSuccess(c, resp))
 }
 
 // Pin review
-// @Summary Pin a review
-// @Description Pin a review by id
+// @Summary Pin or unpin a review
+// @Description Pin a review at a position among its product's pinned reviews, optionally until expires_at. is_pinned false unpins it
 // @Tags Review
 // @Accept json
 // @Produce json
+// @Param review_id path string true "Review ID"
 // @Param user body types.PinReviewRequest true "PinReviewRequest"
 // @Success 200 {object} data.BaseResponse{data=string}
 // @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 409 {object} data.BaseResponse{data=string}
+// @Failure 403 {object} data.BaseResponse{data=string}
 // @Failure 500 {object} data.BaseResponse{data=string}
 // @Router /comment-ms/v1/merchant/reviews/{review_id} [patch]
 func PinReview(c *gin.Context) {
@@ -174,28 +367,33 @@ func PinReview(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: err.Error()})
 		return
 	}
-	if req.IsPinned {
-		err := service.GetReviewServiceInstance().PinReview(c, reviewID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, data.BaseResponse{ErrMsg: err.Error()})
+	userID := c.Value("userID").(int)
+	if !req.IsPinned {
+		if err := service.GetReviewServiceInstance().UnpinReview(c, reviewID, userID); err != nil {
+			c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, RespSuccess(c, "pin success"))
+		c.JSON(http.StatusOK, RespSuccess(c, "unpin success"))
 		return
 	}
 
-	c.JSON(http.StatusOK, RespSuccess(c, nil))
+	if err := service.GetReviewServiceInstance().PinReview(c, reviewID, req, userID); err != nil {
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, "pin success"))
 }
 
 // Delete review
 // @Summary Delete a review
-// @Description Delete a review by id
+// @Description Move a review of one of the merchant's products to the trash. It is purged after the retention period
 // @Tags Review
 // @Accept json
 // @Produce json
 // @Param review_id path string true "Review ID"
 // @Success 200 {object} data.BaseResponse{data=string}
 // @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 403 {object} data.BaseResponse{data=string}
 // @Failure 500 {object} data.BaseResponse{data=string}
 // @Router /comment-ms/v1/merchant/review/{review_id} [delete]
 func DeleteReview(c *gin.Context) {
@@ -204,14 +402,100 @@ func DeleteReview(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: "empty review_id"})
 		return
 	}
-	err := service.GetReviewServiceInstance().DeleteReview(c, reviewID)
+	userID := c.Value("userID").(int)
+	err := service.GetReviewServiceInstance().DeleteReview(c, reviewID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, data.BaseResponse{ErrMsg: err.Error()})
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, RespSuccess(c, "delete success"))
 }
 
+// Delete own review
+// @Summary Delete own review
+// @Description Move a review or reply written by the current user to the trash
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param review_id path string true "Review ID"
+// @Success 200 {object} data.BaseResponse{data=string}
+// @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 403 {object} data.BaseResponse{data=string}
+// @Failure 404 {object} data.BaseResponse{data=string}
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/customer/reviews/{review_id} [delete]
+func DeleteOwnReview(c *gin.Context) {
+	reviewID := c.Param("review_id")
+	if reviewID == "" {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: "empty review_id"})
+		return
+	}
+	userID := c.Value("userID").(int)
+	err := service.GetReviewServiceInstance().DeleteOwnReview(c, reviewID, userID)
+	if err != nil {
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, "delete success"))
+}
+
+// List trash
+// @Summary List deleted reviews
+// @Description Get a page of deleted reviews of one of the merchant's products that can still be restored, newest first
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param product_id query int true "Product ID, one of the merchant's products"
+// @Param limit query int false "Page size, default 20, max 100"
+// @Param cursor query string false "next_cursor returned by the previous page"
+// @Success 200 {object} data.BaseResponse{data=types.ListReviewResponse}
+// @Failure 400 {object} data.BaseResponse{data=[]validation.FieldError} "code 40001 with field errors"
+// @Failure 403 {object} data.BaseResponse{data=string}
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/merchant/reviews/trash [get]
+func ListTrash(c *gin.Context) {
+	var req types.ListReviewRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	userID := c.Value("userID").(int)
+	resp, err := service.GetReviewServiceInstance().ListTrash(c, req, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, resp))
+}
+
+// Restore review
+// @Summary Restore a deleted review
+// @Description Take a review out of the trash. It keeps its likes but is no longer pinned
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param review_id path string true "Review ID"
+// @Success 200 {object} data.BaseResponse{data=string}
+// @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 404 {object} data.BaseResponse{data=string}
+// @Failure 403 {object} data.BaseResponse{data=string}
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/merchant/reviews/{review_id}/restore [post]
+func RestoreReview(c *gin.Context) {
+	reviewID := c.Param("review_id")
+	if reviewID == "" {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: "empty review_id"})
+		return
+	}
+	userID := c.Value("userID").(int)
+	err := service.GetReviewServiceInstance().RestoreReview(c, reviewID, userID)
+	if err != nil {
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, "restore success"))
+}
+
 // ReplyReview Reply.
 //
 // @Summary Review Reply
@@ -221,7 +505,8 @@ func DeleteReview(c *gin.Context) {
 // @Produce json
 // @Param user body types.CreateReviewRequest true "CreateReviewRequest"
 // @Success 200	{object} data.BaseResponse{data=types.CreateReviewRequest}
-// @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 400 {object} data.BaseResponse{data=[]validation.FieldError} "code 40001 with field errors"
+// @Failure 403 {object} data.BaseResponse{data=string}
 // @Failure 500 {object} data.BaseResponse{data=string}
 // @Router /comment-ms/v1/merchant/reviews/{review_id}/reply [post]
 func ReplyReview(c *gin.Context) {
@@ -232,15 +517,78 @@ func ReplyReview(c *gin.Context) {
 	}
 	var req types.CreateReviewRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: err.Error()})
+		respondBindError(c, err)
 		return
 	}
 	req.ParentID = parentID
 	userID := c.Value("userID").(int)
-	err := service.GetReviewServiceInstance().CreateReview(c, req, userID)
+	err := service.GetReviewServiceInstance().ReplyReview(c, req, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, data.BaseResponse{ErrMsg: err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, RespSuccess(c, "reply review success"))
 }
+
+// Edit review
+// @Summary Edit own review
+// @Description Change the content, stars or pictures of a review written by the current user, within the edit window. Fields left out keep their value
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param review_id path string true "Review ID"
+// @Param req body types.EditReviewRequest true "EditReviewRequest"
+// @Success 200 {object} data.BaseResponse{data=types.ReviewInfo}
+// @Failure 400 {object} data.BaseResponse{data=[]validation.FieldError} "code 40001 with field errors"
+// @Failure 403 {object} data.BaseResponse{data=string}
+// @Failure 404 {object} data.BaseResponse{data=string}
+// @Failure 409 {object} data.BaseResponse{data=string}
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/customer/reviews/{review_id} [patch]
+func EditReview(c *gin.Context) {
+	reviewID := c.Param("review_id")
+	if reviewID == "" {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: "empty review_id"})
+		return
+	}
+	var req types.EditReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	userID := c.Value("userID").(int)
+	detail, err := service.GetReviewServiceInstance().EditReview(c, reviewID, req, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, detail))
+}
+
+// Get review revisions
+// @Summary Get review revisions
+// @Description Get the versions of a review replaced by edits of its author, oldest first
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param review_id path string true "Review ID"
+// @Success 200 {object} data.BaseResponse{data=types.ReviewRevisionsResponse}
+// @Failure 400 {object} data.BaseResponse{data=string}
+// @Failure 404 {object} data.BaseResponse{data=string}
+// @Failure 403 {object} data.BaseResponse{data=string}
+// @Failure 500 {object} data.BaseResponse{data=string}
+// @Router /comment-ms/v1/merchant/reviews/{review_id}/revisions [get]
+func GetRevisions(c *gin.Context) {
+	reviewID := c.Param("review_id")
+	if reviewID == "" {
+		c.JSON(http.StatusBadRequest, data.BaseResponse{ErrMsg: "empty review_id"})
+		return
+	}
+	userID := c.Value("userID").(int)
+	resp, err := service.GetReviewServiceInstance().GetRevisions(c, reviewID, userID)
+	if err != nil {
+		c.JSON(errStatus(err), data.BaseResponse{ErrMsg: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RespSuccess(c, resp))
+}
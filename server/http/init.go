@@ -1,7 +1,11 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	gohttp "net/http"
 	"os"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
@@ -9,12 +13,39 @@ import (
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
 )
 
-func Init(exitSig chan os.Signal) {
-	r := router.NewRouter()
-	log.Logger.Infof("Cerami Craft ItemService start...")
-	err := r.Run(fmt.Sprintf("%s:%d", config.Config.HttpConfig.Host, config.Config.HttpConfig.Port))
+var (
+	server   *gohttp.Server
+	listener net.Listener
+)
+
+// Init builds the http server and starts listening, requests are served from
+// Serve.
+func Init() {
+	addr := fmt.Sprintf("%s:%d", config.Config.HttpConfig.Host, config.Config.HttpConfig.Port)
+	var err error
+	listener, err = net.Listen("tcp", addr)
 	if err != nil {
+		log.Logger.Fatalf("Failed to listen: %v", err)
+	}
+	server = &gohttp.Server{Handler: router.NewRouter()}
+}
+
+// Serve serves http requests until Shutdown.
+func Serve(exitSig chan os.Signal) {
+	log.Logger.Infof("Cerami Craft ItemService start...")
+	err := server.Serve(listener)
+	if err != nil && !errors.Is(err, gohttp.ErrServerClosed) {
 		log.Logger.Fatalf("Failed to run server: %v", err)
 		exitSig <- os.Interrupt
 	}
 }
+
+// Shutdown stops accepting connections and waits for the requests in flight
+// until ctx is done.
+func Shutdown(ctx context.Context) error {
+	if err := server.Shutdown(ctx); err != nil {
+		log.Logger.Errorf("http shutdown failed: %v", err)
+		return err
+	}
+	return nil
+}
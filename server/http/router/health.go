@@ -0,0 +1,27 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/health"
+)
+
+// healthz is the liveness probe, it answers as long as the process serves
+// http. The dependencies are left out, restarting the pod does not bring
+// them back.
+func healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusUp})
+}
+
+// readyz is the readiness probe. It pings the dependencies and answers 503
+// with the state of each while one is down or the service is not serving.
+func readyz(c *gin.Context) {
+	report := health.Check(c.Request.Context())
+	status := http.StatusOK
+	if !report.Up() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
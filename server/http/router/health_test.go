@@ -0,0 +1,54 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/health"
+)
+
+func TestProbes(t *testing.T) {
+	down := false
+	health.Register("store", func(ctx context.Context) error {
+		if down {
+			return errors.New("unreachable")
+		}
+		return nil
+	})
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/healthz", healthz)
+	r.GET("/readyz", readyz)
+	probe := func(path string) (int, health.Report) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var report health.Report
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return w.Code, report
+	}
+
+	health.SetReady(true)
+	defer health.SetReady(false)
+	code, report := probe("/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusUp, report.Status)
+	assert.Equal(t, health.StatusUp, report.Dependencies["store"].Status)
+
+	down = true
+	code, report = probe("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, "unreachable", report.Dependencies["store"].Error)
+
+	// liveness does not depend on the stores
+	code, report = probe("/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusUp, report.Status)
+}
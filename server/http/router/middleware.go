@@ -0,0 +1,91 @@
+package router
+
+import (
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+)
+
+// quietPaths are scraped or probed all the time and get no access log or
+// trace.
+var quietPaths = map[string]bool{
+	"/metrics": true,
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// requestID keeps the X-Request-ID of the caller, or makes one up, and echoes
+// it in the response. The request context carries a logger with the request
+// id, the route and the trace id, handlers pass it down to the service and
+// dao calls.
+func requestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(log.RequestIDHeader)
+		if !log.ValidRequestID(id) {
+			id = log.NewRequestID()
+		}
+		c.Header(log.RequestIDHeader, id)
+
+		fields := []interface{}{"request_id", id, "route", c.FullPath()}
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.HasTraceID() {
+			fields = append(fields, "trace_id", spanContext.TraceID().String())
+		}
+		c.Request = c.Request.WithContext(log.WithFields(c.Request.Context(), fields...))
+		c.Next()
+	}
+}
+
+// logUser adds the id of the authenticated user to the request logger. It
+// runs after the auth middleware.
+func logUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID, ok := c.Value("userID").(int); ok {
+			c.Request = c.Request.WithContext(log.WithFields(c.Request.Context(), "user_id", userID))
+		}
+		c.Next()
+	}
+}
+
+// accessLog logs every request once it is served, server errors at error
+// level.
+func accessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		if quietPaths[c.Request.URL.Path] {
+			return
+		}
+
+		status := c.Writer.Status()
+		fields := []interface{}{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+			"size", c.Writer.Size(),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, "errors", c.Errors.String())
+		}
+		logger := log.Ctx(c.Request.Context())
+		if status >= http.StatusInternalServerError {
+			logger.Errorw("access", fields...)
+			return
+		}
+		logger.Infow("access", fields...)
+	}
+}
+
+// recovery turns a panic of a handler into a 500 and logs it with its stack.
+func recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, err any) {
+		log.Ctx(c.Request.Context()).Errorw("panic recovered", "err", err, "stack", string(debug.Stack()))
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
@@ -0,0 +1,81 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+)
+
+// newTestRouter serves GET /reviews/:review_id as user 7, logging through
+// the request logger, and a handler that panics.
+func newTestRouter() (*gin.Engine, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	log.Logger = zap.New(core).Sugar()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.Use(requestID(), accessLog(), recovery())
+	authed := r.Group("/", func(c *gin.Context) { c.Set("userID", 7) }, logUser())
+	authed.GET("/reviews/:review_id", func(c *gin.Context) {
+		log.Ctx(c).Infof("handler")
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+	return r, logs
+}
+
+func TestRequestID_KeepsCallerID(t *testing.T) {
+	r, logs := newTestRouter()
+	req := httptest.NewRequest(http.MethodGet, "/reviews/r1", nil)
+	req.Header.Set(log.RequestIDHeader, "abc-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "abc-123", w.Header().Get(log.RequestIDHeader))
+	entries := logs.All()
+	assert.Len(t, entries, 2)
+	// the handler logs with the request fields
+	assert.Equal(t, "handler", entries[0].Message)
+	assert.Equal(t, "abc-123", entries[0].ContextMap()["request_id"])
+	assert.Equal(t, "/reviews/:review_id", entries[0].ContextMap()["route"])
+	assert.Equal(t, int64(7), entries[0].ContextMap()["user_id"])
+
+	access := entries[1]
+	assert.Equal(t, "access", access.Message)
+	assert.Equal(t, int64(http.StatusNoContent), access.ContextMap()["status"])
+	assert.Equal(t, "/reviews/r1", access.ContextMap()["path"])
+	assert.Equal(t, int64(7), access.ContextMap()["user_id"])
+}
+
+func TestRequestID_ReplacesInvalidID(t *testing.T) {
+	r, _ := newTestRouter()
+	req := httptest.NewRequest(http.MethodGet, "/reviews/r1", nil)
+	req.Header.Set(log.RequestIDHeader, "bad id\n")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	id := w.Header().Get(log.RequestIDHeader)
+	assert.NotEqual(t, "bad id\n", id)
+	assert.True(t, log.ValidRequestID(id))
+}
+
+func TestRecovery_LogsPanic(t *testing.T) {
+	r, logs := newTestRouter()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, 1, logs.FilterMessage("panic recovered").Len())
+	access := logs.FilterMessage("access").All()
+	assert.Len(t, access, 1)
+	assert.Equal(t, zapcore.ErrorLevel, access[0].Level)
+}
@@ -1,13 +1,18 @@
 package router
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 
 	_ "github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/docs"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/http/api"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/tracing"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-user-mservice/common/middleware"
 	swaggerFiles "github.com/swaggo/files"
 	gs "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 const (
@@ -15,7 +20,17 @@ const (
 )
 
 func NewRouter() *gin.Engine {
-	r := gin.Default()
+	r := gin.New()
+	// handlers pass the gin context on, it has to carry the request span and
+	// logger
+	r.ContextWithFallback = true
+	r.Use(otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(func(req *http.Request) bool {
+		return !quietPaths[req.URL.Path]
+	})))
+	r.Use(requestID(), accessLog(), metrics.GinMiddleware(), recovery())
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	r.GET("/healthz", healthz)
+	r.GET("/readyz", readyz)
 
 	basicGroup := r.Group(serviceURIPrefix)
 	{
@@ -32,20 +47,33 @@ func NewRouter() *gin.Engine {
 
 	merchantGroup := basicGroup.Group("/merchant")
 	{
-		merchantGroup.Use(middleware.AuthMiddleware())
+		merchantGroup.Use(middleware.AuthMiddleware(), logUser())
 		merchantGroup.PATCH("/reviews/:review_id", api.PinReview)
 		merchantGroup.DELETE("/review/:review_id", api.DeleteReview)
 		merchantGroup.POST("/reviews/list", api.ListReviewsByFilter)
+		merchantGroup.POST("/reviews/search", api.SearchMerchantReviews)
 		merchantGroup.POST("/reviews/:review_id/replies", api.ReplyReview)
+		merchantGroup.GET("/reviews/moderation", api.ListModerationQueue)
+		merchantGroup.POST("/reviews/:review_id/moderation", api.ModerateReview)
+		merchantGroup.GET("/reviews/trash", api.ListTrash)
+		merchantGroup.POST("/reviews/:review_id/restore", api.RestoreReview)
+		merchantGroup.GET("/reviews/:review_id/revisions", api.GetRevisions)
 	}
 
 	customerGroup := basicGroup.Group("/customer")
 	{
-		customerGroup.Use(middleware.AuthMiddleware())
+		customerGroup.Use(middleware.AuthMiddleware(), logUser())
 		customerGroup.POST("/reviews", api.CreateReview)
+		customerGroup.PATCH("/reviews/:review_id", api.EditReview)
+		customerGroup.DELETE("/reviews/:review_id", api.DeleteOwnReview)
 		customerGroup.POST("/reviews/:review_id/like", api.Like)
+		customerGroup.DELETE("/reviews/:review_id/like", api.Unlike)
+		customerGroup.POST("/reviews/:review_id/unhelpful", api.MarkUnhelpful)
+		customerGroup.DELETE("/reviews/:review_id/unhelpful", api.UnmarkUnhelpful)
 		customerGroup.GET("/reviews/user", api.GetListByUserID)
 		customerGroup.GET("/reviews/product/:product_id", api.GetListByProductID)
+		customerGroup.GET("/reviews/product/:product_id/summary", api.GetRatingSummary)
+		customerGroup.GET("/reviews/product/:product_id/search", api.SearchProductReviews)
 	}
 	return r
 }
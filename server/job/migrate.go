@@ -0,0 +1,59 @@
+package job
+
+import (
+	"context"
+	"flag"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/migration"
+)
+
+// Migrate runs the migrate command. It applies the pending schema migrations
+// or, with -dry-run, only lists them. Opt-in migrations are only applied with
+// -opt-in. It returns the exit code of the process.
+func Migrate(args []string) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "list the pending migrations without applying them")
+	optIn := flags.Bool("opt-in", false, "also apply the opt-in migrations")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	opts := migration.RunOptions{DryRun: *dryRun, OptIn: *optIn}
+	report, err := migration.GetRunner().Run(context.Background(), opts)
+	if *dryRun {
+		for _, m := range report.Pending {
+			log.Logger.Infof("pending migration\tversion=%d\tname=%s", m.Version, m.Name)
+		}
+	}
+	logSkipped(report)
+	if err != nil {
+		log.Logger.Errorf("migrate failed: %v", err)
+		return 1
+	}
+	log.Logger.Infof("migrate: %d pending, %d applied, dry_run=%t", len(report.Pending), len(report.Applied), *dryRun)
+	return 0
+}
+
+// logSkipped lists the opt-in migrations a run left pending.
+func logSkipped(report migration.Report) {
+	for _, m := range report.Skipped {
+		log.Logger.Infof("opt-in migration pending, apply it with migrate -opt-in\tversion=%d\tname=%s", m.Version, m.Name)
+	}
+}
+
+// MigrateOnStart applies the pending schema migrations while the service
+// starts. A failure is logged rather than fatal, the service keeps running on
+// the schema it has and the migration is retried at the next start.
+func MigrateOnStart() {
+	report, err := migration.GetRunner().Run(context.Background(), migration.RunOptions{})
+	logSkipped(report)
+	if err != nil {
+		log.Logger.Errorf("migrate on start failed, %d of %d pending migrations applied: %v",
+			len(report.Applied), len(report.Pending), err)
+		return
+	}
+	if len(report.Applied) > 0 {
+		log.Logger.Infof("migrate on start: %d migrations applied", len(report.Applied))
+	}
+}
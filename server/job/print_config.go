@@ -0,0 +1,23 @@
+package job
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+)
+
+// PrintConfig runs the config command. It prints the effective config, the
+// file of the profile with the environment overrides applied, with secrets
+// masked. It returns the exit code of the process.
+func PrintConfig() int {
+	out, err := yaml.Marshal(config.Config.Masked())
+	if err != nil {
+		log.Logger.Errorf("print config failed: %v", err)
+		return 1
+	}
+	fmt.Printf("# profile %s, %s\n%s", config.Profile, config.File, out)
+	return 0
+}
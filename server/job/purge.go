@@ -0,0 +1,78 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/service"
+)
+
+const (
+	defaultTrashRetention = 30 * 24 * time.Hour
+	defaultPurgeInterval  = time.Hour
+)
+
+var (
+	jobCtx, stopJob = context.WithCancel(context.Background())
+	jobDone         = make(chan struct{})
+)
+
+// Init runs the trash purge job until Stop. Every purge interval it
+// hard-deletes the reviews that stayed in the trash longer than the
+// retention period.
+func Init() {
+	defer close(jobDone)
+	retention, interval := defaultTrashRetention, defaultPurgeInterval
+	if cfg := config.Config.ReviewConfig; cfg != nil {
+		if cfg.TrashRetention > 0 {
+			retention = cfg.TrashRetention
+		}
+		if cfg.PurgeInterval > 0 {
+			interval = cfg.PurgeInterval
+		}
+	}
+	log.Logger.Infof("Trash purge job started, retention %s, interval %s", retention, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-jobCtx.Done():
+			log.Logger.Infof("Trash purge job stopped")
+			return
+		case <-ticker.C:
+			purge(jobCtx, retention)
+		}
+	}
+}
+
+// Stop stops the purge job and waits for it to return until ctx is done. A
+// purge in progress is cancelled, the reviews it did not remove stay in the
+// trash for the next run.
+func Stop(ctx context.Context) error {
+	stopJob()
+	select {
+	case <-jobDone:
+		return nil
+	case <-ctx.Done():
+		log.Logger.Errorf("Trash purge job stop failed: %v", ctx.Err())
+		return ctx.Err()
+	}
+}
+
+func purge(ctx context.Context, retention time.Duration) {
+	purged, err := service.GetReviewServiceInstance().PurgeDeleted(ctx, time.Now().Add(-retention))
+	if ctx.Err() != nil {
+		log.Logger.Infof("Trash purge cancelled after %d reviews", purged)
+		return
+	}
+	if err != nil {
+		log.Logger.Errorf("Trash purge failed after %d reviews: %v", purged, err)
+		return
+	}
+	if purged > 0 {
+		log.Logger.Infof("Trash purge removed %d reviews", purged)
+	}
+}
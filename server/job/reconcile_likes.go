@@ -0,0 +1,50 @@
+package job
+
+import (
+	"context"
+	"flag"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/migration"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/service"
+)
+
+// ReconcileLikes runs the reconcile-likes command. It logs every review whose
+// like data in redis drifted from the likes recorded in mongo and, with -fix,
+// rebuilds it from mongo. -fix is refused while the likes cached in redis
+// before they were recorded in mongo are not imported. It returns the exit
+// code of the process.
+func ReconcileLikes(args []string) int {
+	flags := flag.NewFlagSet("reconcile-likes", flag.ContinueOnError)
+	fix := flags.Bool("fix", false, "rewrite redis from the likes recorded in mongo")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	// until the likes cached in redis are imported, the fix would take them
+	// for drift and drop them
+	if *fix {
+		imported, err := migration.GetRunner().IsApplied(context.Background(), migration.ImportCachedLikes)
+		if err != nil {
+			log.Logger.Errorf("reconcile-likes failed: %v", err)
+			return 1
+		}
+		if !imported {
+			log.Logger.Errorf("reconcile-likes -fix refused: the likes cached in redis are not imported yet, run migrate first")
+			return 1
+		}
+	}
+
+	report, err := service.GetReviewServiceInstance().ReconcileLikes(context.Background(), *fix)
+	if err != nil {
+		log.Logger.Errorf("reconcile-likes failed: %v", err)
+		return 1
+	}
+	for _, drift := range report.Drifts {
+		log.Logger.Warnf("like drift\treview_id=%s\tcount=%d\tcached_count=%d\tmissing_likers=%v\textra_likers=%v",
+			drift.ReviewID, drift.Count, drift.CachedCount, drift.MissingLikers, drift.ExtraLikers)
+	}
+	log.Logger.Infof("reconcile-likes: %d reviews, %d likes, %d drifted, fixed=%t",
+		report.Reviews, report.Likes, len(report.Drifts), report.Fixed)
+	return 0
+}
@@ -0,0 +1,26 @@
+package log
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type loggerKey struct{}
+
+// WithFields returns a copy of ctx whose logger adds the key value pairs to
+// every line, after the fields already carried by ctx.
+func WithFields(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	return context.WithValue(ctx, loggerKey{}, Ctx(ctx).With(keysAndValues...))
+}
+
+// Ctx returns the logger carried by ctx, e.g. with the request id, user id
+// and route of the request being served, or Logger when it carries none.
+func Ctx(ctx context.Context) *zap.SugaredLogger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*zap.SugaredLogger); ok {
+			return logger
+		}
+	}
+	return Logger
+}
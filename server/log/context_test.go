@@ -0,0 +1,36 @@
+package log
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCtx(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	Logger = zap.New(core).Sugar()
+
+	// a context without a logger gets the global one
+	assert.Same(t, Logger, Ctx(context.Background()))
+
+	ctx := WithFields(context.Background(), "request_id", "r1")
+	ctx = WithFields(ctx, "user_id", 7)
+	Ctx(ctx).Infof("liked")
+
+	entries := logs.All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, map[string]interface{}{"request_id": "r1", "user_id": int64(7)}, entries[0].ContextMap())
+}
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, ValidRequestID("3f2a9c1e-7b4d-4e8a-9c1e-7b4d4e8a9c1e"))
+	assert.True(t, ValidRequestID(NewRequestID()))
+	assert.False(t, ValidRequestID(""))
+	assert.False(t, ValidRequestID(strings.Repeat("a", maxRequestIDLength+1)))
+	assert.False(t, ValidRequestID("r1\nFAKE log line"))
+}
@@ -10,6 +10,12 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// Formats of the log lines.
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
 var (
 	Logger *zap.SugaredLogger
 )
@@ -44,6 +50,9 @@ func getEncoder() zapcore.Encoder {
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05")
 	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	if config.Config.LogConfig.Format == FormatJSON {
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
 	return zapcore.NewConsoleEncoder(encoderConfig)
 }
 
@@ -0,0 +1,37 @@
+package log
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RequestIDHeader carries the request id in http requests and responses. In
+// grpc metadata it is lowercased.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+// NewRequestID returns a random request id.
+func NewRequestID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// ValidRequestID reports whether a request id sent by a caller can be kept.
+// It has to be short and made of letters, digits and -_.: so it cannot forge
+// log lines.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
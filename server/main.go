@@ -1,33 +1,101 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/grpc"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/health"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/http"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/job"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository"
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mongo"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/tracing"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-user-mservice/common/utils"
 )
 
+const defaultShutdownTimeout = 15 * time.Second
+
 var (
 	sigCh = make(chan os.Signal, 1)
 )
 
 func main() {
-	config.Init()
+	profile := flag.String("profile", "", "config profile, reads resources/config-<profile>.yml, default reads config.yml (env "+config.ProfileEnv+")")
+	flag.Parse()
+	args := flag.Args()
+	config.Init(*profile)
 	log.InitLogger()
+	// config prints the effective config, secrets masked, and exits
+	if len(args) > 0 && args[0] == "config" {
+		os.Exit(job.PrintConfig())
+	}
+	log.Logger.Infof("Config profile %s read from %s", config.Profile, config.File)
 	repository.Init()
-	mongo.Init()
+	// reconcile-likes [-fix] checks the like cache in redis against mongo and exits
+	if len(args) > 0 && args[0] == "reconcile-likes" {
+		os.Exit(job.ReconcileLikes(args[1:]))
+	}
+	// migrate [-dry-run] applies the pending schema migrations and exits
+	if len(args) > 0 && args[0] == "migrate" {
+		os.Exit(job.Migrate(args[1:]))
+	}
+	if config.Config.MongoConfig.MigrateOnStart {
+		job.MigrateOnStart()
+	}
 	utils.InitJwtSecret()
-	go grpc.Init(sigCh)
-	go http.Init(sigCh)
+	tracing.Init()
+	grpc.Init()
+	http.Init()
+	go grpc.Serve(sigCh)
+	go http.Serve(sigCh)
+	go job.Init()
+	health.SetReady(true)
 	// listen terminage signal
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	sig := <-sigCh // Block until signal is received
 	log.Logger.Infof("Received signal: %v, shutting down...", sig)
+	shutdown()
+}
+
+// shutdown stops taking traffic, drains the servers and stops the purge job
+// within the shutdown timeout, then disconnects from the stores. The servers
+// keep serving for the shutdown delay after they report not ready, until the
+// probes take them out of rotation.
+func shutdown() {
+	health.SetReady(false)
+	if delay := config.Config.ShutdownDelay; delay > 0 {
+		log.Logger.Infof("Not ready, draining in %v", delay)
+		time.Sleep(delay)
+	}
+	timeout := defaultShutdownTimeout
+	if config.Config.ShutdownTimeout > 0 {
+		timeout = config.Config.ShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, stop := range []func(context.Context) error{http.Shutdown, grpc.Shutdown, job.Stop} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = stop(ctx) // failures are logged by each stop
+		}()
+	}
+	wg.Wait()
+
+	// the stores get a fresh timeout, draining may have used up the first
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), timeout)
+	defer closeCancel()
+	_ = tracing.Shutdown(closeCtx) // the spans of the drained requests
+	repository.Close(closeCtx)
+	log.Logger.Infof("Shutdown complete")
+	_ = log.Logger.Sync()
 }
@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "comment"
+
+// Backends of the dao operations.
+const (
+	BackendMongo = "mongo"
+	BackendRedis = "redis"
+)
+
+// Outcomes of the dao operations.
+const (
+	OutcomeOK       = "ok"
+	OutcomeNotFound = "not_found"
+	OutcomeError    = "error"
+)
+
+// unmatchedRoute labels http requests that matched no route, so unknown paths
+// do not each add a series.
+const unmatchedRoute = "unmatched"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	grpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_server_handled_total",
+		Help:      "gRPC calls by method and status code.",
+	}, []string{"method", "code"})
+	grpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_server_handling_seconds",
+		Help:      "Latency of gRPC calls by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	daoDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "dao_operation_duration_seconds",
+		Help:      "Latency of the review dao operations by method, backend and outcome.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"method", "backend", "outcome"})
+
+	// ReviewsCreated counts saved reviews by kind: review, reply or
+	// merchant_reply.
+	ReviewsCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reviews_created_total",
+		Help:      "Reviews and replies created.",
+	}, []string{"kind"})
+	// Likes counts likes that were added or taken back, by action: like or
+	// unlike. Repeated likes of the same user are not counted.
+	Likes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "review_likes_total",
+		Help:      "Likes added and taken back.",
+	}, []string{"action"})
+	// Pins counts pin changes by action: pin or unpin.
+	Pins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "review_pins_total",
+		Help:      "Reviews pinned and unpinned by merchants.",
+	}, []string{"action"})
+	// Deletes counts reviews moved to the trash by who deleted them: author
+	// or merchant, and by purge the ones purged from the trash.
+	Deletes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reviews_deleted_total",
+		Help:      "Reviews moved to the trash and purged from it.",
+	}, []string{"by"})
+)
+
+// Handler serves the metrics to Prometheus.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// GinMiddleware counts the http requests and observes their latency, labelled
+// by the route pattern rather than the path.
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// UnaryServerInterceptor counts the grpc calls and observes their latency.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err).String()
+		grpcRequests.WithLabelValues(info.FullMethod, code).Inc()
+		grpcDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+// ObserveDao observes the latency of a dao operation started at start.
+func ObserveDao(method string, backend string, outcome string, start time.Time) {
+	daoDuration.WithLabelValues(method, backend, outcome).Observe(time.Since(start).Seconds())
+}
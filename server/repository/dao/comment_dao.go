@@ -2,9 +2,11 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
 	myMongo "github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mongo"
@@ -24,17 +26,104 @@ type CommentDao interface {
 	Delete(ctx context.Context, id string) (err error)
 	HIncr(ctx context.Context, key string, member string, deta int) (err error)
 	SAdd(ctx context.Context, key string, member string) (err error)
-	GetListByUserID(ctx context.Context, userID int) (list []*model.Comment, err error)
-	GetListByProductID(ctx context.Context, productId int) (list []*model.Comment, err error)
-	GetListByQuery(ctx context.Context, productId int, stars int) (list []*model.Comment, err error)
+	GetListByUserID(ctx context.Context, userID int, page Page) (list []*model.Comment, nextCursor string, err error)
+	GetListByProductID(ctx context.Context, productId int, page Page) (list []*model.Comment, nextCursor string, err error)
+	GetListByQuery(ctx context.Context, query ReviewQuery, page Page) (list []*model.Comment, nextCursor string, err error)
+	SearchByText(ctx context.Context, search TextSearch, page Page) (list []*model.Comment, nextCursor string, err error)
 	HMGet(ctx context.Context, key string, members []string) (likesCntMap map[string]int, err error)
+	HGetAll(ctx context.Context, key string) (cntMap map[string]int, err error)
 	SMembers(ctx context.Context, key string) (likedReviewIds []string, err error)
+	SMIsMember(ctx context.Context, key string, members []string) (isMember map[string]bool, err error)
 	HGet(ctx context.Context, key string, member string) (value string, err error)
 	HDel(ctx context.Context, key string, member string) (err error)
 	HSet(ctx context.Context, key string, member string, value string) (err error)
 	UpdateIsPinnedByID(ctx context.Context, id string, isPinned bool) error
+	SetPinOrder(ctx context.Context, productId int, pins []*model.Comment) error
+	ClearStalePins(ctx context.Context, productId int, now time.Time) error
+	GetPinnedByProductID(ctx context.Context, productId int, now time.Time) (list []*model.Comment, err error)
+	AcquireLock(ctx context.Context, key string, token string, ttl time.Duration) (acquired bool, err error)
+	ReleaseLock(ctx context.Context, key string, token string) (err error)
+	SAddHIncr(ctx context.Context, setKey string, hashKey string, member string, indexKey string, indexMember string) (changed bool, err error)
+	SRemHDecr(ctx context.Context, setKey string, hashKey string, member string, indexKey string, indexMember string) (changed bool, err error)
+	AggregateRatingByProductID(ctx context.Context, productId int) (*model.RatingStats, error)
+	GetStr(ctx context.Context, key string) (value string, err error)
+	SetEx(ctx context.Context, key string, value string, expiration time.Duration) (err error)
+	Del(ctx context.Context, key string) (err error)
+	Incr(ctx context.Context, key string) (err error)
+	GetListByParentIDs(ctx context.Context, parentIDs []string) (list []*model.Comment, err error)
+	CountByParentIDs(ctx context.Context, parentIDs []string) (countMap map[string]int, err error)
+	GetListByStatus(ctx context.Context, productId int, status string, page Page) (list []*model.Comment, nextCursor string, err error)
+	UpdateStatusByID(ctx context.Context, id string, status string, reason string, moderatedBy int, moderatedAt time.Time) error
+	SoftDelete(ctx context.Context, id string, deletedBy int, deletedAt time.Time) error
+	Restore(ctx context.Context, id string) error
+	GetListDeleted(ctx context.Context, productId int, page Page) (list []*model.Comment, nextCursor string, err error)
+	GetListDeletedBefore(ctx context.Context, before time.Time, limit int) (list []*model.Comment, err error)
+	GetAllReplies(ctx context.Context, parentID string) (list []*model.Comment, err error)
+	SRem(ctx context.Context, key string, member string) (err error)
+	UpdateContentByID(ctx context.Context, id string, edit *model.Comment, previous model.Revision) error
+	GetRevisions(ctx context.Context, id string) (revisions []model.Revision, err error)
+	IncLikeCount(ctx context.Context, id string, delta int) error
+	SetLikeCount(ctx context.Context, id string, count int) error
+	GetLikeCounts(ctx context.Context) (countMap map[string]int, err error)
+	AddUnhelpfulVote(ctx context.Context, id string, userID int) (added bool, err error)
+	RemoveUnhelpfulVote(ctx context.Context, id string, userID int) (removed bool, err error)
+	IncMerchantReplyCount(ctx context.Context, id string, delta int) error
 }
 
+// ErrNotFound is returned when the requested comment does not exist.
+var ErrNotFound = errors.New("review not found")
+
+// ErrPinConflict is returned when a pin position of the product is already
+// taken, i.e. another writer changed the pins concurrently.
+var ErrPinConflict = errors.New("pinned reviews were changed concurrently")
+
+// topLevelParentIDs are the parent_id values of comments that are not replies.
+var topLevelParentIDs = bson.A{"", "0"}
+
+// visibleStatuses are the status values of comments shown to customers.
+// Comments saved before moderation existed have no status and are visible.
+var visibleStatuses = bson.A{model.StatusApproved, nil}
+
+// commentProjection keeps the revision history, only loaded by GetRevisions,
+// and the unhelpful voters out of comment reads.
+var commentProjection = bson.M{"revisions": 0, "unhelpful_by": 0}
+
+// likeScript adds member ARGV[1] to the set at KEYS[1] and, only when it was
+// not already there, increments field ARGV[1] of the hash at KEYS[2] and adds
+// ARGV[2] to the reverse index set at KEYS[3].
+var likeScript = redis.NewScript(`
+if redis.call('SADD', KEYS[1], ARGV[1]) == 1 then
+	redis.call('HINCRBY', KEYS[2], ARGV[1], 1)
+	redis.call('SADD', KEYS[3], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// releaseLockScript deletes the lock at KEYS[1] only when it still holds the
+// token ARGV[1], so an expired lock taken over by another owner is left alone.
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// unlikeScript removes member ARGV[1] from the set at KEYS[1] and, only when it
+// was there, decrements field ARGV[1] of the hash at KEYS[2] without going
+// below zero and removes ARGV[2] from the reverse index set at KEYS[3].
+var unlikeScript = redis.NewScript(`
+if redis.call('SREM', KEYS[1], ARGV[1]) == 1 then
+	local cnt = redis.call('HINCRBY', KEYS[2], ARGV[1], -1)
+	if cnt < 0 then
+		redis.call('HSET', KEYS[2], ARGV[1], 0)
+	end
+	redis.call('SREM', KEYS[3], ARGV[2])
+	return 1
+end
+return 0
+`)
+
 var (
 	commentDaoInstance CommentDao
 	commentSyncOnce    sync.Once
@@ -42,9 +131,12 @@ var (
 
 func GetCommentDao() CommentDao {
 	commentSyncOnce.Do(func() {
-		commentDaoInstance = &CommentDaoImpl{
-			collection:  myMongo.CommentCollection,
-			redisClient: myRedis.RedisClient,
+		commentDaoInstance = &instrumentedCommentDao{
+			next: &CommentDaoImpl{
+				collection:  myMongo.CommentCollection,
+				redisClient: myRedis.RedisClient,
+			},
+			collection: "comments",
 		}
 	})
 	return commentDaoInstance
@@ -60,12 +152,16 @@ func (c *CommentDaoImpl) Get(ctx context.Context, id string) (*model.Comment, er
 	var returnComment model.Comment
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		log.Logger.Errorf("parse id failed.\terr=%v", err)
-		return nil, err
+		log.Ctx(ctx).Errorf("parse id failed.\terr=%v", err)
+		return nil, ErrNotFound
 	}
-	err = c.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&returnComment)
+	findOptions := options.FindOne().SetProjection(commentProjection)
+	err = c.collection.FindOne(ctx, bson.M{"_id": objectID}, findOptions).Decode(&returnComment)
 	if err != nil {
-		log.Logger.Errorf("failed to get comment by id %s: %v", id, err)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Errorf("failed to get comment by id %s: %v", id, err)
 		return nil, err
 	}
 	return &returnComment, nil
@@ -75,28 +171,28 @@ func (c *CommentDaoImpl) Get(ctx context.Context, id string) (*model.Comment, er
 func (c *CommentDaoImpl) Save(ctx context.Context, comment *model.Comment) error {
 	ret, err := c.collection.InsertOne(ctx, comment)
 	if err != nil {
-		log.Logger.Errorf("failed to save comment: %v", err)
+		log.Ctx(ctx).Errorf("failed to save comment: %v", err)
 		return err
 	}
 	// 将 InsertedID 转换为字符串
 	objectID, ok := ret.InsertedID.(primitive.ObjectID)
 	if !ok {
-		log.Logger.Fatalf("InsertedID is not of type primitive.ObjectID")
+		log.Ctx(ctx).Fatalf("InsertedID is not of type primitive.ObjectID")
 	} else {
 		comment.ID = objectID.Hex()
 	}
-	log.Logger.Infof("comment saved with id: %v", ret.InsertedID)
+	log.Ctx(ctx).Debugf("comment saved with id: %v", ret.InsertedID)
 	return nil
 }
 
 func (c *CommentDaoImpl) HIncr(ctx context.Context, key string, member string, deta int) (err error) {
 	if c.redisClient == nil {
-		log.Logger.Errorf("redis client is nil")
+		log.Ctx(ctx).Errorf("redis client is nil")
 		return nil
 	}
 	cmd := c.redisClient.HIncrBy(ctx, key, member, int64(deta))
 	if cmd.Err() != nil {
-		log.Logger.Errorf("HIncr failed\tkey=%s\tmember=%s\tdeta=%d\terr=%v", key, member, deta, cmd.Err())
+		log.Ctx(ctx).Errorf("HIncr failed\tkey=%s\tmember=%s\tdeta=%d\terr=%v", key, member, deta, cmd.Err())
 		return cmd.Err()
 	}
 	return nil
@@ -105,17 +201,17 @@ func (c *CommentDaoImpl) HIncr(ctx context.Context, key string, member string, d
 // Delete removes a comment document by its hex id
 func (c *CommentDaoImpl) Delete(ctx context.Context, id string) (err error) {
 	if c.collection == nil {
-		log.Logger.Errorf("mongo collection is nil")
+		log.Ctx(ctx).Errorf("mongo collection is nil")
 		return nil
 	}
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		log.Logger.Errorf("parse id failed. id=%s err=%v", id, err)
+		log.Ctx(ctx).Errorf("parse id failed. id=%s err=%v", id, err)
 		return err
 	}
 	_, err = c.collection.DeleteOne(ctx, bson.M{"_id": objectID})
 	if err != nil {
-		log.Logger.Errorf("DeleteOne failed id=%s err=%v", id, err)
+		log.Ctx(ctx).Errorf("DeleteOne failed id=%s err=%v", id, err)
 		return err
 	}
 	return nil
@@ -123,130 +219,169 @@ func (c *CommentDaoImpl) Delete(ctx context.Context, id string) (err error) {
 
 func (c *CommentDaoImpl) SAdd(ctx context.Context, key string, member string) (err error) {
 	if c.redisClient == nil {
-		log.Logger.Errorf("redis client is nil")
+		log.Ctx(ctx).Errorf("redis client is nil")
 		return nil
 	}
 	cmd := c.redisClient.SAdd(ctx, key, member)
 	if cmd.Err() != nil {
-		log.Logger.Errorf("SAdd failed\tkey=%s\tmember=%s\terr=%v", key, member, cmd.Err())
+		log.Ctx(ctx).Errorf("SAdd failed\tkey=%s\tmember=%s\terr=%v", key, member, cmd.Err())
 		return cmd.Err()
 	}
 	return nil
 }
 
-func (c *CommentDaoImpl) GetListByUserID(ctx context.Context, userID int) (list []*model.Comment, err error) {
+func (c *CommentDaoImpl) GetListByUserID(ctx context.Context, userID int, page Page) (list []*model.Comment, nextCursor string, err error) {
 	if c.collection == nil {
-		log.Logger.Errorf("mongo collection is nil")
-		return nil, nil
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil, "", nil
 	}
-	cursor, err := c.collection.Find(ctx, bson.M{"user_id": userID})
+	list, nextCursor, err = c.findPage(ctx, bson.M{"user_id": userID, "deleted_at": nil}, page)
 	if err != nil {
-		log.Logger.Errorf("Find by user_id failed\tuser_id=%d\terr=%v", userID, err)
-		return nil, err
+		log.Ctx(ctx).Errorf("Find by user_id failed\tuser_id=%d\terr=%v", userID, err)
+		return nil, "", err
 	}
-	defer func() {
-		if err := cursor.Close(ctx); err != nil {
-			log.Logger.Errorf("failed to close cursor: %v", err)
-		}
-	}()
-	var results []*model.Comment
-	for cursor.Next(ctx) {
-		var cm model.Comment
-		if err := cursor.Decode(&cm); err != nil {
-			log.Logger.Errorf("Decode comment failed\terr=%v", err)
-			return nil, err
-		}
-		log.Logger.Infof("cm = %+v\n", cm)
-		results = append(results, &cm)
+	return list, nextCursor, nil
+}
+
+func (c *CommentDaoImpl) GetListByProductID(ctx context.Context, productId int, page Page) (list []*model.Comment, nextCursor string, err error) {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil, "", nil
 	}
-	if err := cursor.Err(); err != nil {
-		log.Logger.Errorf("cursor iteration error\terr=%v", err)
-		return nil, err
+	filter := bson.M{
+		"product_id": productId,
+		"parent_id":  bson.M{"$in": topLevelParentIDs},
+		"status":     bson.M{"$in": visibleStatuses},
+		"deleted_at": nil,
 	}
-	return results, nil
+	list, nextCursor, err = c.findPage(ctx, filter, page)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Find by product_id failed\tproduct_id=%d\terr=%v", productId, err)
+		return nil, "", err
+	}
+	return list, nextCursor, nil
 }
 
-func (c *CommentDaoImpl) GetListByProductID(ctx context.Context, productId int) (list []*model.Comment, err error) {
+// GetListByQuery returns the top level reviews matching query in the order of
+// page.Sort.
+func (c *CommentDaoImpl) GetListByQuery(ctx context.Context, query ReviewQuery, page Page) (list []*model.Comment, nextCursor string, err error) {
 	if c.collection == nil {
-		log.Logger.Errorf("mongo collection is nil")
-		return nil, nil
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil, "", nil
 	}
-	cursor, err := c.collection.Find(ctx, bson.M{"product_id": productId})
+	list, nextCursor, err = c.findPage(ctx, query.filter(), page)
 	if err != nil {
-		log.Logger.Errorf("Find by product_id failed\tproduct_id=%d\terr=%v", productId, err)
-		return nil, err
+		log.Ctx(ctx).Errorf("Find by query failed\tquery=%+v\terr=%v", query, err)
+		return nil, "", err
 	}
-	defer func() {
-		if err := cursor.Close(ctx); err != nil {
-			log.Logger.Errorf("failed to close cursor: %v", err)
-		}
-	}()
-	var results []*model.Comment
-	for cursor.Next(ctx) {
-		var cm model.Comment
-		if err := cursor.Decode(&cm); err != nil {
-			log.Logger.Errorf("Decode comment failed\terr=%v", err)
-			return nil, err
-		}
-		results = append(results, &cm)
+	return list, nextCursor, nil
+}
+
+// SearchByText returns one page of the reviews matching search, the most
+// relevant first unless page.Sort asks for another order. Every result has its
+// text score.
+func (c *CommentDaoImpl) SearchByText(ctx context.Context, search TextSearch, page Page) (list []*model.Comment, nextCursor string, err error) {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil, "", nil
 	}
-	if err := cursor.Err(); err != nil {
-		log.Logger.Errorf("cursor iteration error\terr=%v", err)
-		return nil, err
+	sort, spec, err := normalizeSearchSort(page.Sort)
+	if err != nil {
+		return nil, "", err
 	}
-	return results, nil
+	after, err := decodeCursor(page.Cursor, sort)
+	if err != nil {
+		return nil, "", err
+	}
+	limit := normalizeLimit(page.Limit)
+	// the text score is only known once matched, so the cursor is applied
+	// after it is added
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: search.filter()}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}},
+	}
+	if after != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: after.filter(spec)}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: spec.order()}},
+		// fetch one extra document to know whether there is a next page
+		bson.D{{Key: "$limit", Value: limit + 1}},
+		bson.D{{Key: "$project", Value: commentProjection}},
+	)
+
+	cursor, err := c.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Search by text failed\tquery=%q\terr=%v", search.Query, err)
+		return nil, "", err
+	}
+	return readPage(ctx, cursor, limit, sort, spec)
 }
 
-// GetListByQuery returns comments for a product filtered by stars (if stars>0)
-// and ordered by created_at descending.
-func (c *CommentDaoImpl) GetListByQuery(ctx context.Context, productId int, stars int) (list []*model.Comment, err error) {
-	if c.collection == nil {
-		log.Logger.Errorf("mongo collection is nil")
-		return nil, nil
+// findPage returns one page of comments matching filter in the order of
+// page.Sort, newest first by default, and the cursor of the next page if any.
+func (c *CommentDaoImpl) findPage(ctx context.Context, filter bson.M, page Page) (list []*model.Comment, nextCursor string, err error) {
+	sort, spec, err := normalizeSort(page.Sort)
+	if err != nil {
+		return nil, "", err
 	}
-	// build filter
-	filter := bson.M{}
-	if productId > 0 {
-		filter["product_id"] = productId
+	after, err := decodeCursor(page.Cursor, sort)
+	if err != nil {
+		return nil, "", err
 	}
-	if stars > 0 {
-		filter["stars"] = stars
+	if after != nil {
+		filter = bson.M{"$and": bson.A{filter, after.filter(spec)}}
 	}
+	limit := normalizeLimit(page.Limit)
 	findOptions := options.Find()
-	findOptions.SetSort(bson.D{{Key: "created_at", Value: -1}})
+	findOptions.SetSort(spec.order())
+	findOptions.SetProjection(commentProjection)
+	// fetch one extra document to know whether there is a next page
+	findOptions.SetLimit(int64(limit + 1))
 
 	cursor, err := c.collection.Find(ctx, filter, findOptions)
 	if err != nil {
-		log.Logger.Errorf("Find by product_id and stars failed\tproduct_id=%d\tstars=%d\terr=%v", productId, stars, err)
-		return nil, err
+		return nil, "", err
 	}
+	return readPage(ctx, cursor, limit, sort, spec)
+}
+
+// readPage decodes a page of comments queried with one extra document, and
+// returns the cursor of the next page when there is that document.
+func readPage(ctx context.Context, cursor *mongo.Cursor, limit int, sort string, spec sortSpec) (list []*model.Comment, nextCursor string, err error) {
 	defer func() {
 		if err := cursor.Close(ctx); err != nil {
-			log.Logger.Errorf("failed to close cursor: %v", err)
+			log.Ctx(ctx).Errorf("failed to close cursor: %v", err)
 		}
 	}()
 	var results []*model.Comment
+	lastKeyMissing := false
 	for cursor.Next(ctx) {
 		var cm model.Comment
 		if err := cursor.Decode(&cm); err != nil {
-			log.Logger.Errorf("Decode comment failed\terr=%v", err)
-			return nil, err
+			log.Ctx(ctx).Errorf("Decode comment failed\terr=%v", err)
+			return nil, "", err
+		}
+		if len(results) == limit-1 {
+			lastKeyMissing = sortKeyMissing(cursor.Current, spec)
 		}
 		results = append(results, &cm)
 	}
 	if err := cursor.Err(); err != nil {
-		log.Logger.Errorf("cursor iteration error\terr=%v", err)
-		return nil, err
+		log.Ctx(ctx).Errorf("cursor iteration error\terr=%v", err)
+		return nil, "", err
 	}
-	return results, nil
+	if len(results) > limit {
+		results = results[:limit]
+		nextCursor = encodeCursor(results[limit-1], sort, lastKeyMissing)
+	}
+	return results, nextCursor, nil
 }
 
-// NOTE: using options.Find() directly above; no helper needed.
-
 func (c *CommentDaoImpl) HMGet(ctx context.Context, key string, members []string) (likesCntMap map[string]int, err error) {
 	likesCntMap = make(map[string]int, len(members))
 	if c.redisClient == nil {
-		log.Logger.Errorf("redis client is nil")
+		log.Ctx(ctx).Errorf("redis client is nil")
 		return likesCntMap, nil
 	}
 	if len(members) == 0 {
@@ -255,7 +390,7 @@ func (c *CommentDaoImpl) HMGet(ctx context.Context, key string, members []string
 	// redis HMGet accepts variadic keys
 	vals, err := c.redisClient.HMGet(ctx, key, members...).Result()
 	if err != nil {
-		log.Logger.Errorf("HMGet failed\tkey=%s\tmembers=%v\terr=%v", key, members, err)
+		log.Ctx(ctx).Errorf("HMGet failed\tkey=%s\tmembers=%v\terr=%v", key, members, err)
 		return nil, err
 	}
 	for i, v := range vals {
@@ -278,7 +413,7 @@ func (c *CommentDaoImpl) HMGet(ctx context.Context, key string, members []string
 		// parse int
 		cnt, perr := strconv.Atoi(s)
 		if perr != nil {
-			log.Logger.Errorf("parse HMGet value failed\tkey=%s\tmember=%s\tvalue=%v\terr=%v", key, member, v, perr)
+			log.Ctx(ctx).Errorf("parse HMGet value failed\tkey=%s\tmember=%s\tvalue=%v\terr=%v", key, member, v, perr)
 			likesCntMap[member] = 0
 			continue
 		}
@@ -287,9 +422,33 @@ func (c *CommentDaoImpl) HMGet(ctx context.Context, key string, members []string
 	return likesCntMap, nil
 }
 
+// HGetAll returns every field of the counter hash at key. Fields that are not
+// integers are logged and skipped.
+func (c *CommentDaoImpl) HGetAll(ctx context.Context, key string) (cntMap map[string]int, err error) {
+	cntMap = make(map[string]int)
+	if c.redisClient == nil {
+		log.Ctx(ctx).Errorf("redis client is nil")
+		return cntMap, nil
+	}
+	vals, err := c.redisClient.HGetAll(ctx, key).Result()
+	if err != nil {
+		log.Ctx(ctx).Errorf("HGetAll failed\tkey=%s\terr=%v", key, err)
+		return nil, err
+	}
+	for member, v := range vals {
+		cnt, perr := strconv.Atoi(v)
+		if perr != nil {
+			log.Ctx(ctx).Errorf("parse HGetAll value failed\tkey=%s\tmember=%s\tvalue=%s\terr=%v", key, member, v, perr)
+			continue
+		}
+		cntMap[member] = cnt
+	}
+	return cntMap, nil
+}
+
 func (c *CommentDaoImpl) SMembers(ctx context.Context, key string) (likedReviewIds []string, err error) {
 	if c.redisClient == nil {
-		log.Logger.Errorf("redis client is nil")
+		log.Ctx(ctx).Errorf("redis client is nil")
 		return nil, nil
 	}
 	vals, err := c.redisClient.SMembers(ctx, key).Result()
@@ -297,15 +456,41 @@ func (c *CommentDaoImpl) SMembers(ctx context.Context, key string) (likedReviewI
 		if err == redis.Nil {
 			return nil, nil
 		}
-		log.Logger.Errorf("SMembers failed\tkey=%s\terr=%v", key, err)
+		log.Ctx(ctx).Errorf("SMembers failed\tkey=%s\terr=%v", key, err)
 		return nil, err
 	}
 	return vals, nil
 }
 
+// SMIsMember reports which of members are in the set at key, in one round
+// trip however large the set is.
+func (c *CommentDaoImpl) SMIsMember(ctx context.Context, key string, members []string) (isMember map[string]bool, err error) {
+	isMember = make(map[string]bool, len(members))
+	if c.redisClient == nil {
+		log.Ctx(ctx).Errorf("redis client is nil")
+		return isMember, nil
+	}
+	if len(members) == 0 {
+		return isMember, nil
+	}
+	args := make([]interface{}, len(members))
+	for idx, member := range members {
+		args[idx] = member
+	}
+	vals, err := c.redisClient.SMIsMember(ctx, key, args...).Result()
+	if err != nil {
+		log.Ctx(ctx).Errorf("SMIsMember failed\tkey=%s\tmembers=%v\terr=%v", key, members, err)
+		return nil, err
+	}
+	for idx, member := range members {
+		isMember[member] = vals[idx]
+	}
+	return isMember, nil
+}
+
 func (c *CommentDaoImpl) HGet(ctx context.Context, key string, member string) (value string, err error) {
 	if c.redisClient == nil {
-		log.Logger.Errorf("redis client is nil")
+		log.Ctx(ctx).Errorf("redis client is nil")
 		return "", nil
 	}
 	cmd := c.redisClient.HGet(ctx, key, member)
@@ -315,7 +500,7 @@ func (c *CommentDaoImpl) HGet(ctx context.Context, key string, member string) (v
 		if cmd.Err() == redis.Nil {
 			return "", nil
 		}
-		log.Logger.Errorf("HGet failed\tkey=%s\tmember=%s\terr=%v", key, member, cmd.Err())
+		log.Ctx(ctx).Errorf("HGet failed\tkey=%s\tmember=%s\terr=%v", key, member, cmd.Err())
 		return "", cmd.Err()
 	}
 	val, err := cmd.Result()
@@ -323,7 +508,7 @@ func (c *CommentDaoImpl) HGet(ctx context.Context, key string, member string) (v
 		if err == redis.Nil {
 			return "", nil
 		}
-		log.Logger.Errorf("HGet result failed\tkey=%s\tmember=%s\terr=%v", key, member, err)
+		log.Ctx(ctx).Errorf("HGet result failed\tkey=%s\tmember=%s\terr=%v", key, member, err)
 		return "", err
 	}
 	return val, nil
@@ -331,12 +516,12 @@ func (c *CommentDaoImpl) HGet(ctx context.Context, key string, member string) (v
 
 func (c *CommentDaoImpl) HDel(ctx context.Context, key string, member string) (err error) {
 	if c.redisClient == nil {
-		log.Logger.Errorf("redis client is nil")
+		log.Ctx(ctx).Errorf("redis client is nil")
 		return nil
 	}
 	cmd := c.redisClient.HDel(ctx, key, member)
 	if cmd.Err() != nil {
-		log.Logger.Errorf("HDel failed\tkey=%s\tmember=%s\terr=%v", key, member, cmd.Err())
+		log.Ctx(ctx).Errorf("HDel failed\tkey=%s\tmember=%s\terr=%v", key, member, cmd.Err())
 		return cmd.Err()
 	}
 	return nil
@@ -344,12 +529,12 @@ func (c *CommentDaoImpl) HDel(ctx context.Context, key string, member string) (e
 
 func (c *CommentDaoImpl) HSet(ctx context.Context, key string, member string, value string) (err error) {
 	if c.redisClient == nil {
-		log.Logger.Errorf("redis client is nil")
+		log.Ctx(ctx).Errorf("redis client is nil")
 		return nil
 	}
 	cmd := c.redisClient.HSet(ctx, key, member, value)
 	if cmd.Err() != nil {
-		log.Logger.Errorf("HSet failed\tkey=%s\tmember=%s\tvalue=%s\terr=%v", key, member, value, cmd.Err())
+		log.Ctx(ctx).Errorf("HSet failed\tkey=%s\tmember=%s\tvalue=%s\terr=%v", key, member, value, cmd.Err())
 		return cmd.Err()
 	}
 	return nil
@@ -357,20 +542,815 @@ func (c *CommentDaoImpl) HSet(ctx context.Context, key string, member string, va
 
 func (c *CommentDaoImpl) UpdateIsPinnedByID(ctx context.Context, id string, isPinned bool) error {
 	if c.collection == nil {
-		log.Logger.Errorf("mongo collection is nil")
+		log.Ctx(ctx).Errorf("mongo collection is nil")
 		return nil
 	}
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		log.Logger.Errorf("parse id failed. id=%s err=%v", id, err)
+		log.Ctx(ctx).Errorf("parse id failed. id=%s err=%v", id, err)
 		return err
 	}
 	filter := bson.M{"_id": objectID}
 	update := bson.M{"$set": bson.M{"is_pinned": isPinned}}
+	if !isPinned {
+		update["$unset"] = bson.M{"pin_position": "", "pinned_until": ""}
+	}
 
 	_, err = c.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		log.Logger.Errorf("UpdateOne failed id=%s err=%v", id, err)
+		log.Ctx(ctx).Errorf("UpdateOne failed id=%s err=%v", id, err)
+		return err
+	}
+	return nil
+}
+
+// SetPinOrder writes the pin position and expiry of each given comment of the
+// product. The positions of all of them are cleared first, so reordering does
+// not trip over the unique (product_id, pin_position) index on the way. A
+// position held by a comment outside pins fails with ErrPinConflict.
+func (c *CommentDaoImpl) SetPinOrder(ctx context.Context, productId int, pins []*model.Comment) error {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil
+	}
+	if len(pins) == 0 {
+		return nil
+	}
+	objectIDs := make(bson.A, 0, len(pins))
+	for _, p := range pins {
+		objectID, err := primitive.ObjectIDFromHex(p.ID)
+		if err != nil {
+			return ErrNotFound
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+	writes := []mongo.WriteModel{
+		mongo.NewUpdateManyModel().
+			SetFilter(bson.M{"_id": bson.M{"$in": objectIDs}, "product_id": productId}).
+			SetUpdate(bson.M{"$unset": bson.M{"pin_position": ""}}),
+	}
+	for idx, p := range pins {
+		update := bson.M{"$set": bson.M{"is_pinned": true, "pin_position": p.PinPosition}}
+		if p.PinnedUntil != nil {
+			update["$set"].(bson.M)["pinned_until"] = *p.PinnedUntil
+		} else {
+			update["$unset"] = bson.M{"pinned_until": ""}
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": objectIDs[idx], "product_id": productId, "deleted_at": nil}).
+			SetUpdate(update))
+	}
+	_, err := c.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			log.Ctx(ctx).Warnf("SetPinOrder conflict\tproduct_id=%d\terr=%v", productId, err)
+			return ErrPinConflict
+		}
+		log.Ctx(ctx).Errorf("SetPinOrder failed\tproduct_id=%d\terr=%v", productId, err)
+		return err
+	}
+	return nil
+}
+
+// ClearStalePins unpins the product's comments whose pin expired at now or
+// that are no longer shown, freeing their positions.
+func (c *CommentDaoImpl) ClearStalePins(ctx context.Context, productId int, now time.Time) error {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil
+	}
+	filter := bson.M{
+		"product_id": productId,
+		"is_pinned":  true,
+		"$or": bson.A{
+			bson.M{"pinned_until": bson.M{"$lte": now}},
+			bson.M{"deleted_at": bson.M{"$ne": nil}},
+			bson.M{"status": bson.M{"$nin": visibleStatuses}},
+		},
+	}
+	update := bson.M{
+		"$set":   bson.M{"is_pinned": false},
+		"$unset": bson.M{"pin_position": "", "pinned_until": ""},
+	}
+	result, err := c.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		log.Ctx(ctx).Errorf("ClearStalePins failed\tproduct_id=%d\terr=%v", productId, err)
+		return err
+	}
+	if result.ModifiedCount > 0 {
+		log.Ctx(ctx).Infof("ClearStalePins\tproduct_id=%d\tcleared=%d", productId, result.ModifiedCount)
+	}
+	return nil
+}
+
+// GetPinnedByProductID returns the visible pinned reviews of the product that
+// have not expired at now, in pin position order.
+func (c *CommentDaoImpl) GetPinnedByProductID(ctx context.Context, productId int, now time.Time) (list []*model.Comment, err error) {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil, nil
+	}
+	findOptions := options.Find()
+	// pins saved before positions existed have none and come first
+	findOptions.SetSort(bson.D{{Key: "pin_position", Value: 1}, {Key: "created_at", Value: -1}})
+	findOptions.SetProjection(commentProjection)
+	filter := bson.M{
+		"product_id": productId,
+		"is_pinned":  true,
+		"parent_id":  bson.M{"$in": topLevelParentIDs},
+		"status":     bson.M{"$in": visibleStatuses},
+		"deleted_at": nil,
+		"$or": bson.A{
+			bson.M{"pinned_until": nil},
+			bson.M{"pinned_until": bson.M{"$gt": now}},
+		},
+	}
+	cursor, err := c.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Find pinned failed\tproduct_id=%d\terr=%v", productId, err)
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Ctx(ctx).Errorf("failed to close cursor: %v", err)
+		}
+	}()
+	var results []*model.Comment
+	if err := cursor.All(ctx, &results); err != nil {
+		log.Ctx(ctx).Errorf("Decode pinned failed\tproduct_id=%d\terr=%v", productId, err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// SAddHIncr atomically adds member to setKey and, when the set membership
+// actually changed, increments member in hashKey and adds indexMember to
+// indexKey so the sets holding member can be found again.
+func (c *CommentDaoImpl) SAddHIncr(ctx context.Context, setKey string, hashKey string, member string, indexKey string, indexMember string) (changed bool, err error) {
+	if c.redisClient == nil {
+		log.Ctx(ctx).Errorf("redis client is nil")
+		return false, nil
+	}
+	ret, err := likeScript.Run(ctx, c.redisClient, []string{setKey, hashKey, indexKey}, member, indexMember).Int()
+	if err != nil {
+		log.Ctx(ctx).Errorf("SAddHIncr failed\tsetKey=%s\thashKey=%s\tmember=%s\terr=%v", setKey, hashKey, member, err)
+		return false, err
+	}
+	return ret == 1, nil
+}
+
+// SRemHDecr atomically removes member from setKey and, when the set membership
+// actually changed, decrements member in hashKey and removes indexMember from
+// indexKey.
+func (c *CommentDaoImpl) SRemHDecr(ctx context.Context, setKey string, hashKey string, member string, indexKey string, indexMember string) (changed bool, err error) {
+	if c.redisClient == nil {
+		log.Ctx(ctx).Errorf("redis client is nil")
+		return false, nil
+	}
+	ret, err := unlikeScript.Run(ctx, c.redisClient, []string{setKey, hashKey, indexKey}, member, indexMember).Int()
+	if err != nil {
+		log.Ctx(ctx).Errorf("SRemHDecr failed\tsetKey=%s\thashKey=%s\tmember=%s\terr=%v", setKey, hashKey, member, err)
+		return false, err
+	}
+	return ret == 1, nil
+}
+
+// AggregateRatingByProductID computes the star histogram, photo review count
+// and reply count of a product in a single pass over its comments. Replies
+// whose parent is gone or in the trash are not counted.
+func (c *CommentDaoImpl) AggregateRatingByProductID(ctx context.Context, productId int) (*model.RatingStats, error) {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return &model.RatingStats{}, nil
+	}
+	isTopLevel := bson.M{"$in": bson.A{"$parent_id", topLevelParentIDs}}
+	countIf := func(cond interface{}) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{cond, 1, 0}}}
+	}
+	starIs := func(stars int) bson.M {
+		return bson.M{"$and": bson.A{isTopLevel, bson.M{"$eq": bson.A{"$stars", stars}}}}
+	}
+	hasPhotos := bson.M{"$gt": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{"$pic_info", bson.A{}}}}, 0}}
+	// replies only count while their parent is there and not in the trash,
+	// top level reviews look up no parent
+	parentID := bson.M{"$cond": bson.A{isTopLevel, nil, bson.M{"$convert": bson.M{
+		"input": "$parent_id", "to": "objectId", "onError": nil, "onNull": nil,
+	}}}}
+	lookupParent := bson.M{
+		"from": c.collection.Name(),
+		"let":  bson.M{"parent_id": parentID},
+		"pipeline": mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{
+				"$expr":      bson.M{"$eq": bson.A{"$_id", "$$parent_id"}},
+				"deleted_at": nil,
+			}}},
+			{{Key: "$project", Value: bson.M{"_id": 1}}},
+		},
+		"as": "live_parent",
+	}
+	hasLiveParent := bson.M{"$gt": bson.A{bson.M{"$size": "$live_parent"}, 0}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"product_id": productId,
+			"status":     bson.M{"$in": visibleStatuses},
+			"deleted_at": nil,
+		}}},
+		{{Key: "$lookup", Value: lookupParent}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         nil,
+			"total_count": countIf(isTopLevel),
+			"star_sum":    bson.M{"$sum": bson.M{"$cond": bson.A{isTopLevel, "$stars", 0}}},
+			"star_1":      countIf(starIs(1)),
+			"star_2":      countIf(starIs(2)),
+			"star_3":      countIf(starIs(3)),
+			"star_4":      countIf(starIs(4)),
+			"star_5":      countIf(starIs(5)),
+			"photo_count": countIf(bson.M{"$and": bson.A{isTopLevel, hasPhotos}}),
+			"reply_count": countIf(bson.M{"$and": bson.A{bson.M{"$not": bson.A{isTopLevel}}, hasLiveParent}}),
+		}}},
+	}
+	cursor, err := c.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Aggregate rating failed\tproduct_id=%d\terr=%v", productId, err)
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Ctx(ctx).Errorf("failed to close cursor: %v", err)
+		}
+	}()
+	var stats model.RatingStats
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&stats); err != nil {
+			log.Ctx(ctx).Errorf("Decode rating stats failed\tproduct_id=%d\terr=%v", productId, err)
+			return nil, err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		log.Ctx(ctx).Errorf("cursor iteration error\terr=%v", err)
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (c *CommentDaoImpl) GetStr(ctx context.Context, key string) (value string, err error) {
+	if c.redisClient == nil {
+		log.Ctx(ctx).Errorf("redis client is nil")
+		return "", nil
+	}
+	val, err := c.redisClient.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", nil
+		}
+		log.Ctx(ctx).Errorf("Get failed\tkey=%s\terr=%v", key, err)
+		return "", err
+	}
+	return val, nil
+}
+
+func (c *CommentDaoImpl) SetEx(ctx context.Context, key string, value string, expiration time.Duration) (err error) {
+	if c.redisClient == nil {
+		log.Ctx(ctx).Errorf("redis client is nil")
+		return nil
+	}
+	cmd := c.redisClient.Set(ctx, key, value, expiration)
+	if cmd.Err() != nil {
+		log.Ctx(ctx).Errorf("SetEx failed\tkey=%s\texpiration=%v\terr=%v", key, expiration, cmd.Err())
+		return cmd.Err()
+	}
+	return nil
+}
+
+func (c *CommentDaoImpl) Del(ctx context.Context, key string) (err error) {
+	if c.redisClient == nil {
+		log.Ctx(ctx).Errorf("redis client is nil")
+		return nil
+	}
+	cmd := c.redisClient.Del(ctx, key)
+	if cmd.Err() != nil {
+		log.Ctx(ctx).Errorf("Del failed\tkey=%s\terr=%v", key, cmd.Err())
+		return cmd.Err()
+	}
+	return nil
+}
+
+// Incr adds one to the counter at key, a missing key counts from zero.
+func (c *CommentDaoImpl) Incr(ctx context.Context, key string) (err error) {
+	if c.redisClient == nil {
+		log.Ctx(ctx).Errorf("redis client is nil")
+		return nil
+	}
+	cmd := c.redisClient.Incr(ctx, key)
+	if cmd.Err() != nil {
+		log.Ctx(ctx).Errorf("Incr failed\tkey=%s\terr=%v", key, cmd.Err())
+		return cmd.Err()
+	}
+	return nil
+}
+
+// GetListByParentIDs returns the approved direct replies of the given comments, oldest first.
+func (c *CommentDaoImpl) GetListByParentIDs(ctx context.Context, parentIDs []string) (list []*model.Comment, err error) {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil, nil
+	}
+	if len(parentIDs) == 0 {
+		return nil, nil
+	}
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	findOptions.SetProjection(commentProjection)
+	filter := bson.M{
+		"parent_id":  bson.M{"$in": parentIDs},
+		"status":     bson.M{"$in": visibleStatuses},
+		"deleted_at": nil,
+	}
+	cursor, err := c.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Find by parent_id failed\tparent_ids=%v\terr=%v", parentIDs, err)
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Ctx(ctx).Errorf("failed to close cursor: %v", err)
+		}
+	}()
+	var results []*model.Comment
+	if err := cursor.All(ctx, &results); err != nil {
+		log.Ctx(ctx).Errorf("Decode replies failed\tparent_ids=%v\terr=%v", parentIDs, err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// CountByParentIDs returns the number of approved direct replies of each given comment.
+// Comments without replies are absent from the map.
+func (c *CommentDaoImpl) CountByParentIDs(ctx context.Context, parentIDs []string) (countMap map[string]int, err error) {
+	countMap = make(map[string]int, len(parentIDs))
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return countMap, nil
+	}
+	if len(parentIDs) == 0 {
+		return countMap, nil
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"parent_id":  bson.M{"$in": parentIDs},
+			"status":     bson.M{"$in": visibleStatuses},
+			"deleted_at": nil,
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": "$parent_id", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := c.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Count by parent_id failed\tparent_ids=%v\terr=%v", parentIDs, err)
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Ctx(ctx).Errorf("failed to close cursor: %v", err)
+		}
+	}()
+	for cursor.Next(ctx) {
+		var row struct {
+			ParentID string `bson:"_id"`
+			Count    int    `bson:"count"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			log.Ctx(ctx).Errorf("Decode reply count failed\terr=%v", err)
+			return nil, err
+		}
+		countMap[row.ParentID] = row.Count
+	}
+	if err := cursor.Err(); err != nil {
+		log.Ctx(ctx).Errorf("cursor iteration error\terr=%v", err)
+		return nil, err
+	}
+	return countMap, nil
+}
+
+// GetListByStatus returns comments of the product in the given moderation
+// status that are not in the trash, newest first.
+func (c *CommentDaoImpl) GetListByStatus(ctx context.Context, productId int, status string, page Page) (list []*model.Comment, nextCursor string, err error) {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil, "", nil
+	}
+	filter := bson.M{"product_id": productId, "status": status, "deleted_at": nil}
+	if status == model.StatusApproved {
+		filter["status"] = bson.M{"$in": visibleStatuses}
+	}
+	list, nextCursor, err = c.findPage(ctx, filter, page)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Find by status failed\tproduct_id=%d\tstatus=%s\terr=%v", productId, status, err)
+		return nil, "", err
+	}
+	return list, nextCursor, nil
+}
+
+// UpdateStatusByID records a moderation decision on the comment.
+func (c *CommentDaoImpl) UpdateStatusByID(ctx context.Context, id string, status string, reason string, moderatedBy int, moderatedAt time.Time) error {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Ctx(ctx).Errorf("parse id failed. id=%s err=%v", id, err)
+		return ErrNotFound
+	}
+	update := bson.M{"$set": bson.M{
+		"status":            status,
+		"moderation_reason": reason,
+		"moderated_by":      moderatedBy,
+		"moderated_at":      moderatedAt,
+	}}
+	ret, err := c.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Update status failed\tid=%s\tstatus=%s\terr=%v", id, status, err)
+		return err
+	}
+	if ret.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SoftDelete moves the comment to the trash. Comments already in the trash
+// are reported as not found.
+func (c *CommentDaoImpl) SoftDelete(ctx context.Context, id string, deletedBy int, deletedAt time.Time) error {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Ctx(ctx).Errorf("parse id failed. id=%s err=%v", id, err)
+		return ErrNotFound
+	}
+	filter := bson.M{"_id": objectID, "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"deleted_at": deletedAt, "deleted_by": deletedBy}}
+	ret, err := c.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Soft delete failed\tid=%s\terr=%v", id, err)
+		return err
+	}
+	if ret.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Restore takes the comment out of the trash. Comments that are not in the
+// trash are reported as not found.
+func (c *CommentDaoImpl) Restore(ctx context.Context, id string) error {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Ctx(ctx).Errorf("parse id failed. id=%s err=%v", id, err)
+		return ErrNotFound
+	}
+	filter := bson.M{"_id": objectID, "deleted_at": bson.M{"$ne": nil}}
+	update := bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}}
+	ret, err := c.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Restore failed\tid=%s\terr=%v", id, err)
+		return err
+	}
+	if ret.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetListDeleted returns the comments of the product in the trash, newest
+// first.
+func (c *CommentDaoImpl) GetListDeleted(ctx context.Context, productId int, page Page) (list []*model.Comment, nextCursor string, err error) {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil, "", nil
+	}
+	list, nextCursor, err = c.findPage(ctx, bson.M{"product_id": productId, "deleted_at": bson.M{"$ne": nil}}, page)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Find deleted failed\tproduct_id=%d\terr=%v", productId, err)
+		return nil, "", err
+	}
+	return list, nextCursor, nil
+}
+
+// GetListDeletedBefore returns up to limit comments moved to the trash before
+// the given time, oldest deletion first.
+func (c *CommentDaoImpl) GetListDeletedBefore(ctx context.Context, before time.Time, limit int) (list []*model.Comment, err error) {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil, nil
+	}
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "deleted_at", Value: 1}})
+	findOptions.SetProjection(commentProjection)
+	findOptions.SetLimit(int64(limit))
+	cursor, err := c.collection.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": before}}, findOptions)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Find deleted before failed\tbefore=%v\terr=%v", before, err)
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Ctx(ctx).Errorf("failed to close cursor: %v", err)
+		}
+	}()
+	var results []*model.Comment
+	if err := cursor.All(ctx, &results); err != nil {
+		log.Ctx(ctx).Errorf("Decode deleted comments failed\terr=%v", err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetAllReplies returns every direct reply of the comment, whatever its
+// status and including deleted ones.
+func (c *CommentDaoImpl) GetAllReplies(ctx context.Context, parentID string) (list []*model.Comment, err error) {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil, nil
+	}
+	findOptions := options.Find()
+	findOptions.SetProjection(commentProjection)
+	cursor, err := c.collection.Find(ctx, bson.M{"parent_id": parentID}, findOptions)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Find all replies failed\tparent_id=%s\terr=%v", parentID, err)
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Ctx(ctx).Errorf("failed to close cursor: %v", err)
+		}
+	}()
+	var results []*model.Comment
+	if err := cursor.All(ctx, &results); err != nil {
+		log.Ctx(ctx).Errorf("Decode replies failed\tparent_id=%s\terr=%v", parentID, err)
+		return nil, err
+	}
+	return results, nil
+}
+
+func (c *CommentDaoImpl) SRem(ctx context.Context, key string, member string) (err error) {
+	if c.redisClient == nil {
+		log.Ctx(ctx).Errorf("redis client is nil")
+		return nil
+	}
+	err = c.redisClient.SRem(ctx, key, member).Err()
+	if err != nil {
+		log.Ctx(ctx).Errorf("SRem failed\tkey=%s\tmember=%s\terr=%v", key, member, err)
+		return err
+	}
+	return nil
+}
+
+// UpdateContentByID replaces the content, stars, pictures and status of the
+// comment with the ones of edit and appends previous to its revision history.
+// Comments in the trash are reported as not found.
+func (c *CommentDaoImpl) UpdateContentByID(ctx context.Context, id string, edit *model.Comment, previous model.Revision) error {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Ctx(ctx).Errorf("parse id failed. id=%s err=%v", id, err)
+		return ErrNotFound
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"content":   edit.Content,
+			"stars":     edit.Stars,
+			"pic_info":  edit.PicInfo,
+			"status":    edit.Status,
+			"edited_at": edit.EditedAt,
+		},
+		"$push": bson.M{"revisions": previous},
+	}
+	ret, err := c.collection.UpdateOne(ctx, bson.M{"_id": objectID, "deleted_at": nil}, update)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Update content failed\tid=%s\terr=%v", id, err)
+		return err
+	}
+	if ret.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetRevisions returns the revision history of the comment, oldest first.
+func (c *CommentDaoImpl) GetRevisions(ctx context.Context, id string) (revisions []model.Revision, err error) {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil, nil
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Ctx(ctx).Errorf("parse id failed. id=%s err=%v", id, err)
+		return nil, ErrNotFound
+	}
+	var cm model.Comment
+	findOptions := options.FindOne().SetProjection(bson.M{"revisions": 1})
+	err = c.collection.FindOne(ctx, bson.M{"_id": objectID}, findOptions).Decode(&cm)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotFound
+		}
+		log.Ctx(ctx).Errorf("Get revisions failed\tid=%s\terr=%v", id, err)
+		return nil, err
+	}
+	return cm.Revisions, nil
+}
+
+// AcquireLock sets key to token unless it exists, expiring after ttl so a
+// crashed owner cannot hold it forever. Without redis every caller gets the
+// lock, the unique index on pin positions still rejects conflicting writes.
+func (c *CommentDaoImpl) AcquireLock(ctx context.Context, key string, token string, ttl time.Duration) (acquired bool, err error) {
+	if c.redisClient == nil {
+		log.Ctx(ctx).Errorf("redis client is nil")
+		return true, nil
+	}
+	acquired, err = c.redisClient.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		log.Ctx(ctx).Errorf("SetNX failed\tkey=%s\terr=%v", key, err)
+		return false, err
+	}
+	return acquired, nil
+}
+
+// ReleaseLock deletes key if it still holds token.
+func (c *CommentDaoImpl) ReleaseLock(ctx context.Context, key string, token string) (err error) {
+	if c.redisClient == nil {
+		log.Ctx(ctx).Errorf("redis client is nil")
+		return nil
+	}
+	if err := releaseLockScript.Run(ctx, c.redisClient, []string{key}, token).Err(); err != nil {
+		log.Ctx(ctx).Errorf("ReleaseLock failed\tkey=%s\terr=%v", key, err)
+		return err
+	}
+	return nil
+}
+
+// IncLikeCount adds delta to the like count of the comment, the count does not
+// go below zero.
+func (c *CommentDaoImpl) IncLikeCount(ctx context.Context, id string, delta int) error {
+	filter := bson.M{}
+	if delta < 0 {
+		filter["like_count"] = bson.M{"$gte": -delta}
+	}
+	// unhelpful_count is incremented by 0 so comments stored before it existed get one
+	update := bson.M{"$inc": bson.M{"like_count": delta, "unhelpful_count": 0}}
+	if _, err := c.updateVotes(ctx, id, filter, update); err != nil {
+		log.Ctx(ctx).Errorf("Inc like count failed\tid=%s\tdelta=%d\terr=%v", id, delta, err)
+		return err
+	}
+	return nil
+}
+
+// SetLikeCount overwrites the like count of the comment.
+func (c *CommentDaoImpl) SetLikeCount(ctx context.Context, id string, count int) error {
+	update := bson.M{
+		"$set": bson.M{"like_count": count},
+		"$inc": bson.M{"unhelpful_count": 0},
+	}
+	if _, err := c.updateVotes(ctx, id, bson.M{}, update); err != nil {
+		log.Ctx(ctx).Errorf("Set like count failed\tid=%s\tcount=%d\terr=%v", id, count, err)
+		return err
+	}
+	return nil
+}
+
+// GetLikeCounts returns the like count of every comment that has likes.
+func (c *CommentDaoImpl) GetLikeCounts(ctx context.Context) (countMap map[string]int, err error) {
+	countMap = make(map[string]int)
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return countMap, nil
+	}
+	findOptions := options.Find().SetProjection(bson.M{"like_count": 1})
+	cursor, err := c.collection.Find(ctx, bson.M{"like_count": bson.M{"$gt": 0}}, findOptions)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Find like counts failed\terr=%v", err)
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Ctx(ctx).Errorf("failed to close cursor: %v", err)
+		}
+	}()
+	for cursor.Next(ctx) {
+		var cm model.Comment
+		if err := cursor.Decode(&cm); err != nil {
+			log.Ctx(ctx).Errorf("Decode comment failed\terr=%v", err)
+			return nil, err
+		}
+		countMap[cm.ID] = cm.LikeCount
+	}
+	if err := cursor.Err(); err != nil {
+		log.Ctx(ctx).Errorf("cursor iteration error\terr=%v", err)
+		return nil, err
+	}
+	return countMap, nil
+}
+
+// AddUnhelpfulVote records that the user found the comment unhelpful. added is
+// false when the vote was already recorded.
+func (c *CommentDaoImpl) AddUnhelpfulVote(ctx context.Context, id string, userID int) (added bool, err error) {
+	filter := bson.M{"unhelpful_by": bson.M{"$ne": userID}}
+	update := bson.M{
+		"$push": bson.M{"unhelpful_by": userID},
+		"$inc":  bson.M{"unhelpful_count": 1, "like_count": 0},
+	}
+	added, err = c.updateVotes(ctx, id, filter, update)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Add unhelpful vote failed\tid=%s\tuser_id=%d\terr=%v", id, userID, err)
+		return false, err
+	}
+	return added, nil
+}
+
+// RemoveUnhelpfulVote takes back the user's unhelpful vote on the comment.
+// removed is false when there was none.
+func (c *CommentDaoImpl) RemoveUnhelpfulVote(ctx context.Context, id string, userID int) (removed bool, err error) {
+	filter := bson.M{"unhelpful_by": userID}
+	update := bson.M{
+		"$pull": bson.M{"unhelpful_by": userID},
+		"$inc":  bson.M{"unhelpful_count": -1, "like_count": 0},
+	}
+	removed, err = c.updateVotes(ctx, id, filter, update)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Remove unhelpful vote failed\tid=%s\tuser_id=%d\terr=%v", id, userID, err)
+		return false, err
+	}
+	return removed, nil
+}
+
+// updateVotes applies update to the comment if it matches filter, then stores
+// the helpful score of the new counts. The score is only written while the
+// counts are unchanged, a concurrent vote writes the score of its own counts,
+// so the last one always matches the stored counts. changed is false when the
+// comment does not exist or does not match filter.
+func (c *CommentDaoImpl) updateVotes(ctx context.Context, id string, filter bson.M, update bson.M) (changed bool, err error) {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return false, nil
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Ctx(ctx).Errorf("parse id failed. id=%s err=%v", id, err)
+		return false, nil
+	}
+	filter["_id"] = objectID
+	findOptions := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"like_count": 1, "unhelpful_count": 1})
+	var counts model.Comment
+	err = c.collection.FindOneAndUpdate(ctx, filter, update, findOptions).Decode(&counts)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	scoreFilter := bson.M{
+		"_id":             objectID,
+		"like_count":      counts.LikeCount,
+		"unhelpful_count": counts.UnhelpfulCount,
+	}
+	score := bson.M{"$set": bson.M{"helpful_score": model.HelpfulScore(counts.LikeCount, counts.UnhelpfulCount)}}
+	if _, err := c.collection.UpdateOne(ctx, scoreFilter, score); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// IncMerchantReplyCount adds delta to the number of merchant replies of the
+// comment, the count does not go below zero.
+func (c *CommentDaoImpl) IncMerchantReplyCount(ctx context.Context, id string, delta int) error {
+	if c.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Ctx(ctx).Errorf("parse id failed. id=%s err=%v", id, err)
+		return ErrNotFound
+	}
+	filter := bson.M{"_id": objectID}
+	if delta < 0 {
+		filter["merchant_reply_count"] = bson.M{"$gte": -delta}
+	}
+	_, err = c.collection.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"merchant_reply_count": delta}})
+	if err != nil {
+		log.Ctx(ctx).Errorf("Inc merchant reply count failed\tid=%s\tdelta=%d\terr=%v", id, delta, err)
 		return err
 	}
 	return nil
@@ -0,0 +1,400 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/tracing"
+)
+
+// instrumentedCommentDao traces every CommentDao operation in a child span of
+// the caller, tagged with the collection or the keys it touches, and observes
+// its latency by method, backend and outcome.
+type instrumentedCommentDao struct {
+	next       CommentDao
+	collection string
+}
+
+// mongoOp starts an operation of method on the collection. The returned func
+// ends it with the error the operation returned.
+func (d *instrumentedCommentDao) mongoOp(ctx context.Context, method string) (context.Context, func(err error)) {
+	return startOp(ctx, method, metrics.BackendMongo,
+		attribute.String("db.system.name", "mongodb"),
+		attribute.String("db.collection.name", d.collection),
+	)
+}
+
+// redisOp starts an operation of method on the keys. The returned func ends
+// it with the error the operation returned.
+func (d *instrumentedCommentDao) redisOp(ctx context.Context, method string, keys ...string) (context.Context, func(err error)) {
+	return startOp(ctx, method, metrics.BackendRedis,
+		attribute.String("db.system.name", "redis"),
+		attribute.StringSlice("db.redis.keys", keys),
+	)
+}
+
+func startOp(ctx context.Context, method string, backend string, attrs ...attribute.KeyValue) (context.Context, func(err error)) {
+	start := time.Now()
+	attrs = append(attrs, attribute.String("db.operation.name", method))
+	ctx, span := tracing.Tracer().Start(ctx, "CommentDao."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, func(err error) {
+		outcome := daoOutcome(err)
+		if outcome == metrics.OutcomeError {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		metrics.ObserveDao(method, backend, outcome, start)
+	}
+}
+
+// daoOutcome is the outcome label of an operation that returned err.
+func daoOutcome(err error) string {
+	switch {
+	case err == nil:
+		return metrics.OutcomeOK
+	case errors.Is(err, ErrNotFound):
+		return metrics.OutcomeNotFound
+	default:
+		return metrics.OutcomeError
+	}
+}
+
+// Save implements CommentDao.
+func (d *instrumentedCommentDao) Save(ctx context.Context, comment *model.Comment) (err error) {
+	ctx, done := d.mongoOp(ctx, "Save")
+	defer func() { done(err) }()
+	return d.next.Save(ctx, comment)
+}
+
+// Get implements CommentDao.
+func (d *instrumentedCommentDao) Get(ctx context.Context, id string) (comment *model.Comment, err error) {
+	ctx, done := d.mongoOp(ctx, "Get")
+	defer func() { done(err) }()
+	return d.next.Get(ctx, id)
+}
+
+// Delete implements CommentDao.
+func (d *instrumentedCommentDao) Delete(ctx context.Context, id string) (err error) {
+	ctx, done := d.mongoOp(ctx, "Delete")
+	defer func() { done(err) }()
+	return d.next.Delete(ctx, id)
+}
+
+// HIncr implements CommentDao.
+func (d *instrumentedCommentDao) HIncr(ctx context.Context, key string, member string, deta int) (err error) {
+	ctx, done := d.redisOp(ctx, "HIncr", key)
+	defer func() { done(err) }()
+	return d.next.HIncr(ctx, key, member, deta)
+}
+
+// SAdd implements CommentDao.
+func (d *instrumentedCommentDao) SAdd(ctx context.Context, key string, member string) (err error) {
+	ctx, done := d.redisOp(ctx, "SAdd", key)
+	defer func() { done(err) }()
+	return d.next.SAdd(ctx, key, member)
+}
+
+// GetListByUserID implements CommentDao.
+func (d *instrumentedCommentDao) GetListByUserID(ctx context.Context, userID int, page Page) (list []*model.Comment, nextCursor string, err error) {
+	ctx, done := d.mongoOp(ctx, "GetListByUserID")
+	defer func() { done(err) }()
+	return d.next.GetListByUserID(ctx, userID, page)
+}
+
+// GetListByProductID implements CommentDao.
+func (d *instrumentedCommentDao) GetListByProductID(ctx context.Context, productId int, page Page) (list []*model.Comment, nextCursor string, err error) {
+	ctx, done := d.mongoOp(ctx, "GetListByProductID")
+	defer func() { done(err) }()
+	return d.next.GetListByProductID(ctx, productId, page)
+}
+
+// GetListByQuery implements CommentDao.
+func (d *instrumentedCommentDao) GetListByQuery(ctx context.Context, query ReviewQuery, page Page) (list []*model.Comment, nextCursor string, err error) {
+	ctx, done := d.mongoOp(ctx, "GetListByQuery")
+	defer func() { done(err) }()
+	return d.next.GetListByQuery(ctx, query, page)
+}
+
+// SearchByText implements CommentDao.
+func (d *instrumentedCommentDao) SearchByText(ctx context.Context, search TextSearch, page Page) (list []*model.Comment, nextCursor string, err error) {
+	ctx, done := d.mongoOp(ctx, "SearchByText")
+	defer func() { done(err) }()
+	return d.next.SearchByText(ctx, search, page)
+}
+
+// HMGet implements CommentDao.
+func (d *instrumentedCommentDao) HMGet(ctx context.Context, key string, members []string) (likesCntMap map[string]int, err error) {
+	ctx, done := d.redisOp(ctx, "HMGet", key)
+	defer func() { done(err) }()
+	return d.next.HMGet(ctx, key, members)
+}
+
+// HGetAll implements CommentDao.
+func (d *instrumentedCommentDao) HGetAll(ctx context.Context, key string) (cntMap map[string]int, err error) {
+	ctx, done := d.redisOp(ctx, "HGetAll", key)
+	defer func() { done(err) }()
+	return d.next.HGetAll(ctx, key)
+}
+
+// SMembers implements CommentDao.
+func (d *instrumentedCommentDao) SMembers(ctx context.Context, key string) (likedReviewIds []string, err error) {
+	ctx, done := d.redisOp(ctx, "SMembers", key)
+	defer func() { done(err) }()
+	return d.next.SMembers(ctx, key)
+}
+
+// SMIsMember implements CommentDao.
+func (d *instrumentedCommentDao) SMIsMember(ctx context.Context, key string, members []string) (isMember map[string]bool, err error) {
+	ctx, done := d.redisOp(ctx, "SMIsMember", key)
+	defer func() { done(err) }()
+	return d.next.SMIsMember(ctx, key, members)
+}
+
+// HGet implements CommentDao.
+func (d *instrumentedCommentDao) HGet(ctx context.Context, key string, member string) (value string, err error) {
+	ctx, done := d.redisOp(ctx, "HGet", key)
+	defer func() { done(err) }()
+	return d.next.HGet(ctx, key, member)
+}
+
+// HDel implements CommentDao.
+func (d *instrumentedCommentDao) HDel(ctx context.Context, key string, member string) (err error) {
+	ctx, done := d.redisOp(ctx, "HDel", key)
+	defer func() { done(err) }()
+	return d.next.HDel(ctx, key, member)
+}
+
+// HSet implements CommentDao.
+func (d *instrumentedCommentDao) HSet(ctx context.Context, key string, member string, value string) (err error) {
+	ctx, done := d.redisOp(ctx, "HSet", key)
+	defer func() { done(err) }()
+	return d.next.HSet(ctx, key, member, value)
+}
+
+// UpdateIsPinnedByID implements CommentDao.
+func (d *instrumentedCommentDao) UpdateIsPinnedByID(ctx context.Context, id string, isPinned bool) (err error) {
+	ctx, done := d.mongoOp(ctx, "UpdateIsPinnedByID")
+	defer func() { done(err) }()
+	return d.next.UpdateIsPinnedByID(ctx, id, isPinned)
+}
+
+// SetPinOrder implements CommentDao.
+func (d *instrumentedCommentDao) SetPinOrder(ctx context.Context, productId int, pins []*model.Comment) (err error) {
+	ctx, done := d.mongoOp(ctx, "SetPinOrder")
+	defer func() { done(err) }()
+	return d.next.SetPinOrder(ctx, productId, pins)
+}
+
+// ClearStalePins implements CommentDao.
+func (d *instrumentedCommentDao) ClearStalePins(ctx context.Context, productId int, now time.Time) (err error) {
+	ctx, done := d.mongoOp(ctx, "ClearStalePins")
+	defer func() { done(err) }()
+	return d.next.ClearStalePins(ctx, productId, now)
+}
+
+// GetPinnedByProductID implements CommentDao.
+func (d *instrumentedCommentDao) GetPinnedByProductID(ctx context.Context, productId int, now time.Time) (list []*model.Comment, err error) {
+	ctx, done := d.mongoOp(ctx, "GetPinnedByProductID")
+	defer func() { done(err) }()
+	return d.next.GetPinnedByProductID(ctx, productId, now)
+}
+
+// AcquireLock implements CommentDao.
+func (d *instrumentedCommentDao) AcquireLock(ctx context.Context, key string, token string, ttl time.Duration) (acquired bool, err error) {
+	ctx, done := d.redisOp(ctx, "AcquireLock", key)
+	defer func() { done(err) }()
+	return d.next.AcquireLock(ctx, key, token, ttl)
+}
+
+// ReleaseLock implements CommentDao.
+func (d *instrumentedCommentDao) ReleaseLock(ctx context.Context, key string, token string) (err error) {
+	ctx, done := d.redisOp(ctx, "ReleaseLock", key)
+	defer func() { done(err) }()
+	return d.next.ReleaseLock(ctx, key, token)
+}
+
+// SAddHIncr implements CommentDao.
+func (d *instrumentedCommentDao) SAddHIncr(ctx context.Context, setKey string, hashKey string, member string, indexKey string, indexMember string) (changed bool, err error) {
+	ctx, done := d.redisOp(ctx, "SAddHIncr", setKey, hashKey, indexKey)
+	defer func() { done(err) }()
+	return d.next.SAddHIncr(ctx, setKey, hashKey, member, indexKey, indexMember)
+}
+
+// SRemHDecr implements CommentDao.
+func (d *instrumentedCommentDao) SRemHDecr(ctx context.Context, setKey string, hashKey string, member string, indexKey string, indexMember string) (changed bool, err error) {
+	ctx, done := d.redisOp(ctx, "SRemHDecr", setKey, hashKey, indexKey)
+	defer func() { done(err) }()
+	return d.next.SRemHDecr(ctx, setKey, hashKey, member, indexKey, indexMember)
+}
+
+// AggregateRatingByProductID implements CommentDao.
+func (d *instrumentedCommentDao) AggregateRatingByProductID(ctx context.Context, productId int) (stats *model.RatingStats, err error) {
+	ctx, done := d.mongoOp(ctx, "AggregateRatingByProductID")
+	defer func() { done(err) }()
+	return d.next.AggregateRatingByProductID(ctx, productId)
+}
+
+// GetStr implements CommentDao.
+func (d *instrumentedCommentDao) GetStr(ctx context.Context, key string) (value string, err error) {
+	ctx, done := d.redisOp(ctx, "GetStr", key)
+	defer func() { done(err) }()
+	return d.next.GetStr(ctx, key)
+}
+
+// SetEx implements CommentDao.
+func (d *instrumentedCommentDao) SetEx(ctx context.Context, key string, value string, expiration time.Duration) (err error) {
+	ctx, done := d.redisOp(ctx, "SetEx", key)
+	defer func() { done(err) }()
+	return d.next.SetEx(ctx, key, value, expiration)
+}
+
+// Del implements CommentDao.
+func (d *instrumentedCommentDao) Del(ctx context.Context, key string) (err error) {
+	ctx, done := d.redisOp(ctx, "Del", key)
+	defer func() { done(err) }()
+	return d.next.Del(ctx, key)
+}
+
+// Incr implements CommentDao.
+func (d *instrumentedCommentDao) Incr(ctx context.Context, key string) (err error) {
+	ctx, done := d.redisOp(ctx, "Incr", key)
+	defer func() { done(err) }()
+	return d.next.Incr(ctx, key)
+}
+
+// GetListByParentIDs implements CommentDao.
+func (d *instrumentedCommentDao) GetListByParentIDs(ctx context.Context, parentIDs []string) (list []*model.Comment, err error) {
+	ctx, done := d.mongoOp(ctx, "GetListByParentIDs")
+	defer func() { done(err) }()
+	return d.next.GetListByParentIDs(ctx, parentIDs)
+}
+
+// CountByParentIDs implements CommentDao.
+func (d *instrumentedCommentDao) CountByParentIDs(ctx context.Context, parentIDs []string) (countMap map[string]int, err error) {
+	ctx, done := d.mongoOp(ctx, "CountByParentIDs")
+	defer func() { done(err) }()
+	return d.next.CountByParentIDs(ctx, parentIDs)
+}
+
+// GetListByStatus implements CommentDao.
+func (d *instrumentedCommentDao) GetListByStatus(ctx context.Context, productId int, status string, page Page) (list []*model.Comment, nextCursor string, err error) {
+	ctx, done := d.mongoOp(ctx, "GetListByStatus")
+	defer func() { done(err) }()
+	return d.next.GetListByStatus(ctx, productId, status, page)
+}
+
+// UpdateStatusByID implements CommentDao.
+func (d *instrumentedCommentDao) UpdateStatusByID(ctx context.Context, id string, status string, reason string, moderatedBy int, moderatedAt time.Time) (err error) {
+	ctx, done := d.mongoOp(ctx, "UpdateStatusByID")
+	defer func() { done(err) }()
+	return d.next.UpdateStatusByID(ctx, id, status, reason, moderatedBy, moderatedAt)
+}
+
+// SoftDelete implements CommentDao.
+func (d *instrumentedCommentDao) SoftDelete(ctx context.Context, id string, deletedBy int, deletedAt time.Time) (err error) {
+	ctx, done := d.mongoOp(ctx, "SoftDelete")
+	defer func() { done(err) }()
+	return d.next.SoftDelete(ctx, id, deletedBy, deletedAt)
+}
+
+// Restore implements CommentDao.
+func (d *instrumentedCommentDao) Restore(ctx context.Context, id string) (err error) {
+	ctx, done := d.mongoOp(ctx, "Restore")
+	defer func() { done(err) }()
+	return d.next.Restore(ctx, id)
+}
+
+// GetListDeleted implements CommentDao.
+func (d *instrumentedCommentDao) GetListDeleted(ctx context.Context, productId int, page Page) (list []*model.Comment, nextCursor string, err error) {
+	ctx, done := d.mongoOp(ctx, "GetListDeleted")
+	defer func() { done(err) }()
+	return d.next.GetListDeleted(ctx, productId, page)
+}
+
+// GetListDeletedBefore implements CommentDao.
+func (d *instrumentedCommentDao) GetListDeletedBefore(ctx context.Context, before time.Time, limit int) (list []*model.Comment, err error) {
+	ctx, done := d.mongoOp(ctx, "GetListDeletedBefore")
+	defer func() { done(err) }()
+	return d.next.GetListDeletedBefore(ctx, before, limit)
+}
+
+// GetAllReplies implements CommentDao.
+func (d *instrumentedCommentDao) GetAllReplies(ctx context.Context, parentID string) (list []*model.Comment, err error) {
+	ctx, done := d.mongoOp(ctx, "GetAllReplies")
+	defer func() { done(err) }()
+	return d.next.GetAllReplies(ctx, parentID)
+}
+
+// SRem implements CommentDao.
+func (d *instrumentedCommentDao) SRem(ctx context.Context, key string, member string) (err error) {
+	ctx, done := d.redisOp(ctx, "SRem", key)
+	defer func() { done(err) }()
+	return d.next.SRem(ctx, key, member)
+}
+
+// UpdateContentByID implements CommentDao.
+func (d *instrumentedCommentDao) UpdateContentByID(ctx context.Context, id string, edit *model.Comment, previous model.Revision) (err error) {
+	ctx, done := d.mongoOp(ctx, "UpdateContentByID")
+	defer func() { done(err) }()
+	return d.next.UpdateContentByID(ctx, id, edit, previous)
+}
+
+// GetRevisions implements CommentDao.
+func (d *instrumentedCommentDao) GetRevisions(ctx context.Context, id string) (revisions []model.Revision, err error) {
+	ctx, done := d.mongoOp(ctx, "GetRevisions")
+	defer func() { done(err) }()
+	return d.next.GetRevisions(ctx, id)
+}
+
+// IncLikeCount implements CommentDao.
+func (d *instrumentedCommentDao) IncLikeCount(ctx context.Context, id string, delta int) (err error) {
+	ctx, done := d.mongoOp(ctx, "IncLikeCount")
+	defer func() { done(err) }()
+	return d.next.IncLikeCount(ctx, id, delta)
+}
+
+// SetLikeCount implements CommentDao.
+func (d *instrumentedCommentDao) SetLikeCount(ctx context.Context, id string, count int) (err error) {
+	ctx, done := d.mongoOp(ctx, "SetLikeCount")
+	defer func() { done(err) }()
+	return d.next.SetLikeCount(ctx, id, count)
+}
+
+// GetLikeCounts implements CommentDao.
+func (d *instrumentedCommentDao) GetLikeCounts(ctx context.Context) (countMap map[string]int, err error) {
+	ctx, done := d.mongoOp(ctx, "GetLikeCounts")
+	defer func() { done(err) }()
+	return d.next.GetLikeCounts(ctx)
+}
+
+// AddUnhelpfulVote implements CommentDao.
+func (d *instrumentedCommentDao) AddUnhelpfulVote(ctx context.Context, id string, userID int) (added bool, err error) {
+	ctx, done := d.mongoOp(ctx, "AddUnhelpfulVote")
+	defer func() { done(err) }()
+	return d.next.AddUnhelpfulVote(ctx, id, userID)
+}
+
+// RemoveUnhelpfulVote implements CommentDao.
+func (d *instrumentedCommentDao) RemoveUnhelpfulVote(ctx context.Context, id string, userID int) (removed bool, err error) {
+	ctx, done := d.mongoOp(ctx, "RemoveUnhelpfulVote")
+	defer func() { done(err) }()
+	return d.next.RemoveUnhelpfulVote(ctx, id, userID)
+}
+
+// IncMerchantReplyCount implements CommentDao.
+func (d *instrumentedCommentDao) IncMerchantReplyCount(ctx context.Context, id string, delta int) (err error) {
+	ctx, done := d.mongoOp(ctx, "IncMerchantReplyCount")
+	defer func() { done(err) }()
+	return d.next.IncMerchantReplyCount(ctx, id, delta)
+}
@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+)
+
+// fakeCommentDao implements the CommentDao methods the tests call.
+type fakeCommentDao struct {
+	CommentDao
+	err error
+}
+
+func (f *fakeCommentDao) Get(ctx context.Context, id string) (*model.Comment, error) {
+	return nil, f.err
+}
+
+func (f *fakeCommentDao) HMGet(ctx context.Context, key string, members []string) (map[string]int, error) {
+	return map[string]int{}, f.err
+}
+
+func TestDaoOutcome(t *testing.T) {
+	assert.Equal(t, metrics.OutcomeOK, daoOutcome(nil))
+	assert.Equal(t, metrics.OutcomeNotFound, daoOutcome(ErrNotFound))
+	assert.Equal(t, metrics.OutcomeError, daoOutcome(errors.New("connection reset")))
+}
+
+func TestInstrumentedCommentDao_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	next := &fakeCommentDao{err: ErrNotFound}
+	d := &instrumentedCommentDao{next: next, collection: "comments"}
+
+	_, err := d.Get(ctx, "r1")
+	assert.ErrorIs(t, err, ErrNotFound)
+	next.err = errors.New("connection reset")
+	_, err = d.HMGet(ctx, "review_likes", []string{"r1"})
+	assert.Error(t, err)
+	parent.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+	get, hmget := spans[0], spans[1]
+
+	assert.Equal(t, "CommentDao.Get", get.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), get.Parent().SpanID())
+	assert.Contains(t, get.Attributes(), attribute.String("db.collection.name", "comments"))
+	// a missing comment is not a failure of the store
+	assert.Equal(t, codes.Unset, get.Status().Code)
+
+	assert.Equal(t, "CommentDao.HMGet", hmget.Name())
+	assert.Contains(t, hmget.Attributes(), attribute.StringSlice("db.redis.keys", []string{"review_likes"}))
+	assert.Equal(t, codes.Error, hmget.Status().Code)
+}
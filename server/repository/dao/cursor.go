@@ -0,0 +1,220 @@
+package dao
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// Orders of a listing. An empty sort means SortNewest.
+const (
+	SortNewest    = "newest"
+	SortOldest    = "oldest"
+	SortStarsDesc = "stars_desc"
+	SortStarsAsc  = "stars_asc"
+	SortLikes     = "likes"   // most liked first
+	SortHelpful   = "helpful" // highest helpful score first
+	// SortRelevance orders text search results by their text score, it is
+	// the default of searches and not available to the other listings.
+	SortRelevance = "relevance"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrInvalidSort is returned for an unknown sort.
+var ErrInvalidSort = errors.New("invalid sort")
+
+// Page selects one page of a listing. An empty Cursor means the first page.
+// A cursor is only valid for the sort it was returned with.
+type Page struct {
+	Cursor string
+	Limit  int
+	Sort   string
+}
+
+// sortSpec orders a listing by field. Ties are broken by _id, newest first
+// except for SortOldest, so every order is total and pages never overlap.
+// Optional fields may be missing from comments stored before they existed,
+// mongo orders those comments before every value.
+type sortSpec struct {
+	field    string
+	desc     bool
+	tieAsc   bool
+	optional bool
+}
+
+var sortSpecs = map[string]sortSpec{
+	SortNewest:    {field: "created_at", desc: true},
+	SortOldest:    {field: "created_at", tieAsc: true},
+	SortStarsDesc: {field: "stars", desc: true},
+	SortStarsAsc:  {field: "stars"},
+	SortLikes:     {field: "like_count", desc: true, optional: true},
+	SortHelpful:   {field: "helpful_score", desc: true, optional: true},
+	SortRelevance: {field: "score", desc: true},
+}
+
+// IsSort reports whether sort is empty or one of the sorts of the review
+// lists.
+func IsSort(sort string) bool {
+	_, _, err := normalizeSort(sort)
+	return err == nil
+}
+
+// normalizeSort returns the sort of a list page, SortNewest when it is empty.
+func normalizeSort(sort string) (string, sortSpec, error) {
+	if sort == SortRelevance {
+		return "", sortSpec{}, ErrInvalidSort
+	}
+	if sort == "" {
+		sort = SortNewest
+	}
+	spec, ok := sortSpecs[sort]
+	if !ok {
+		return "", sortSpec{}, ErrInvalidSort
+	}
+	return sort, spec, nil
+}
+
+// normalizeSearchSort returns the sort of a search page, SortRelevance when
+// it is empty.
+func normalizeSearchSort(sort string) (string, sortSpec, error) {
+	if sort == "" || sort == SortRelevance {
+		return SortRelevance, sortSpecs[SortRelevance], nil
+	}
+	return normalizeSort(sort)
+}
+
+// order is the find sort of the spec.
+func (s sortSpec) order() bson.D {
+	dir, tieDir := 1, -1
+	if s.desc {
+		dir = -1
+	}
+	if s.tieAsc {
+		tieDir = 1
+	}
+	return bson.D{{Key: s.field, Value: dir}, {Key: "_id", Value: tieDir}}
+}
+
+// pageCursor is the position of the last item of a page: its sort key and
+// _id. Cursors issued before sorting existed have no sort and are newest.
+type pageCursor struct {
+	Sort       string  `json:"s,omitempty"`
+	CreatedAt  int64   `json:"c,omitempty"` // sort key of the created_at sorts
+	Value      float64 `json:"v,omitempty"` // sort key of the other sorts
+	KeyMissing bool    `json:"m,omitempty"` // the item has no value of an optional sort key
+	ID         string  `json:"i"`
+}
+
+// encodeCursor returns the cursor of the page ending with comment. keyMissing
+// tells that the comment has no value of the sort key, it decodes as zero.
+func encodeCursor(comment *model.Comment, sort string, keyMissing bool) string {
+	pc := pageCursor{ID: comment.ID, KeyMissing: keyMissing}
+	if sort != SortNewest {
+		pc.Sort = sort
+	}
+	switch sortSpecs[sort].field {
+	case "created_at":
+		pc.CreatedAt = comment.CreatedAt.UnixMilli()
+	case "stars":
+		pc.Value = float64(comment.Stars)
+	case "like_count":
+		pc.Value = float64(comment.LikeCount)
+	case "helpful_score":
+		pc.Value = comment.HelpfulScore
+	case "score":
+		pc.Value = comment.TextScore
+	}
+	raw, _ := json.Marshal(pc)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor decodes a cursor returned for sort, a cursor of another sort
+// is invalid.
+func decodeCursor(cursor string, sort string) (*pageCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var pc pageCursor
+	if err := json.Unmarshal(raw, &pc); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if !primitive.IsValidObjectID(pc.ID) {
+		return nil, ErrInvalidCursor
+	}
+	if pc.Sort == "" {
+		pc.Sort = SortNewest
+	}
+	if pc.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+	return &pc, nil
+}
+
+// sortKeyMissing reports whether doc has no value of the optional sort key
+// of spec.
+func sortKeyMissing(doc bson.Raw, spec sortSpec) bool {
+	if !spec.optional {
+		return false
+	}
+	value, err := doc.LookupErr(spec.field)
+	return err != nil || value.Type == bson.TypeNull
+}
+
+// filter restricts a query ordered by spec to the items after the cursor.
+// Items without an optional sort key come last in descending order and first
+// in ascending order, a null filter matches them.
+func (pc *pageCursor) filter(spec sortSpec) bson.M {
+	objectID, _ := primitive.ObjectIDFromHex(pc.ID)
+	var key interface{} = pc.Value
+	if spec.field == "created_at" {
+		key = time.UnixMilli(pc.CreatedAt)
+	}
+	op, tieOp := "$gt", "$lt"
+	if spec.desc {
+		op = "$lt"
+	}
+	if spec.tieAsc {
+		tieOp = "$gt"
+	}
+	if spec.optional && pc.KeyMissing {
+		after := bson.A{bson.M{spec.field: nil, "_id": bson.M{tieOp: objectID}}}
+		if !spec.desc {
+			after = append(after, bson.M{spec.field: bson.M{"$ne": nil}})
+		}
+		return bson.M{"$or": after}
+	}
+	after := bson.A{
+		bson.M{spec.field: bson.M{op: key}},
+		bson.M{spec.field: key, "_id": bson.M{tieOp: objectID}},
+	}
+	if spec.optional && spec.desc {
+		after = append(after, bson.M{spec.field: nil})
+	}
+	return bson.M{"$or": after}
+}
+
+func normalizeLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		return maxPageLimit
+	}
+	return limit
+}
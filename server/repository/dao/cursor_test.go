@@ -0,0 +1,163 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+	createdAt := time.Date(2025, 10, 1, 12, 30, 0, 123456789, time.UTC)
+
+	cursor := encodeCursor(&model.Comment{ID: id, CreatedAt: createdAt}, SortNewest, false)
+	pc, err := decodeCursor(cursor, SortNewest)
+	assert.NoError(t, err)
+	assert.Equal(t, id, pc.ID)
+	// mongo keeps millisecond precision, so does the cursor
+	assert.Equal(t, createdAt.UnixMilli(), pc.CreatedAt)
+}
+
+func TestCursor_Empty(t *testing.T) {
+	pc, err := decodeCursor("", SortNewest)
+	assert.NoError(t, err)
+	assert.Nil(t, pc)
+}
+
+func TestCursor_Invalid(t *testing.T) {
+	for _, cursor := range []string{"!!!", "bm90LWpzb24", "eyJjIjoxLCJpIjoieHl6In0"} {
+		_, err := decodeCursor(cursor, SortNewest)
+		assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
+
+func TestCursor_SortKeys(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+	comment := &model.Comment{ID: id, Stars: 4, LikeCount: 12, HelpfulScore: 0.7236}
+
+	for sort, want := range map[string]float64{SortStarsAsc: 4, SortStarsDesc: 4, SortLikes: 12, SortHelpful: 0.7236} {
+		pc, err := decodeCursor(encodeCursor(comment, sort, false), sort)
+		assert.NoError(t, err, sort)
+		assert.Equal(t, want, pc.Value, sort)
+		assert.Equal(t, id, pc.ID, sort)
+	}
+}
+
+func TestCursor_OtherSort(t *testing.T) {
+	cursor := encodeCursor(&model.Comment{ID: primitive.NewObjectID().Hex(), LikeCount: 3}, SortLikes, false)
+	_, err := decodeCursor(cursor, SortHelpful)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestCursor_WithoutSortIsNewest(t *testing.T) {
+	// {"c":1,"i":"000000000000000000000001"}, issued before sorting existed
+	cursor := "eyJjIjoxLCJpIjoiMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAxIn0"
+	pc, err := decodeCursor(cursor, SortNewest)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pc.CreatedAt)
+
+	_, err = decodeCursor(cursor, SortOldest)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestCursor_Filter(t *testing.T) {
+	id := primitive.NewObjectID()
+	pc := &pageCursor{Value: 3, ID: id.Hex()}
+
+	assert.Equal(t, bson.M{"$or": bson.A{
+		bson.M{"stars": bson.M{"$gt": float64(3)}},
+		bson.M{"stars": float64(3), "_id": bson.M{"$lt": id}},
+	}}, pc.filter(sortSpecs[SortStarsAsc]))
+
+	pc = &pageCursor{CreatedAt: 1000, ID: id.Hex()}
+	assert.Equal(t, bson.M{"$or": bson.A{
+		bson.M{"created_at": bson.M{"$gt": time.UnixMilli(1000)}},
+		bson.M{"created_at": time.UnixMilli(1000), "_id": bson.M{"$gt": id}},
+	}}, pc.filter(sortSpecs[SortOldest]))
+}
+
+func TestCursor_FilterOptionalKey(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	// comments without a like count come after every count of the likes sort
+	pc := &pageCursor{Sort: SortLikes, ID: id.Hex()}
+	assert.Equal(t, bson.M{"$or": bson.A{
+		bson.M{"like_count": bson.M{"$lt": float64(0)}},
+		bson.M{"like_count": float64(0), "_id": bson.M{"$lt": id}},
+		bson.M{"like_count": nil},
+	}}, pc.filter(sortSpecs[SortLikes]))
+
+	// past the first of them only the rest of them follow
+	cursor := encodeCursor(&model.Comment{ID: id.Hex()}, SortLikes, true)
+	pc, err := decodeCursor(cursor, SortLikes)
+	assert.NoError(t, err)
+	assert.True(t, pc.KeyMissing)
+	assert.Equal(t, bson.M{"$or": bson.A{
+		bson.M{"like_count": nil, "_id": bson.M{"$lt": id}},
+	}}, pc.filter(sortSpecs[SortLikes]))
+}
+
+func TestSortKeyMissing(t *testing.T) {
+	doc := func(v interface{}) bson.Raw {
+		raw, err := bson.Marshal(v)
+		assert.NoError(t, err)
+		return raw
+	}
+	assert.True(t, sortKeyMissing(doc(bson.M{"stars": 4}), sortSpecs[SortHelpful]))
+	assert.True(t, sortKeyMissing(doc(bson.M{"helpful_score": nil}), sortSpecs[SortHelpful]))
+	assert.False(t, sortKeyMissing(doc(bson.M{"helpful_score": 0.0}), sortSpecs[SortHelpful]))
+	// required keys are never missing
+	assert.False(t, sortKeyMissing(doc(bson.M{}), sortSpecs[SortNewest]))
+}
+
+func TestNormalizeSort(t *testing.T) {
+	sort, spec, err := normalizeSort("")
+	assert.NoError(t, err)
+	assert.Equal(t, SortNewest, sort)
+	assert.Equal(t, bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}, spec.order())
+
+	_, spec, err = normalizeSort(SortHelpful)
+	assert.NoError(t, err)
+	assert.Equal(t, bson.D{{Key: "helpful_score", Value: -1}, {Key: "_id", Value: -1}}, spec.order())
+
+	_, _, err = normalizeSort("random")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+	assert.False(t, IsSort("random"))
+	assert.True(t, IsSort(""))
+}
+
+func TestNormalizeLimit(t *testing.T) {
+	assert.Equal(t, defaultPageLimit, normalizeLimit(0))
+	assert.Equal(t, defaultPageLimit, normalizeLimit(-5))
+	assert.Equal(t, 10, normalizeLimit(10))
+	assert.Equal(t, maxPageLimit, normalizeLimit(maxPageLimit+1))
+}
+
+func TestNormalizeSearchSort(t *testing.T) {
+	sort, spec, err := normalizeSearchSort("")
+	assert.NoError(t, err)
+	assert.Equal(t, SortRelevance, sort)
+	assert.Equal(t, bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: -1}}, spec.order())
+
+	sort, _, err = normalizeSearchSort(SortLikes)
+	assert.NoError(t, err)
+	assert.Equal(t, SortLikes, sort)
+
+	// relevance only orders search results
+	_, _, err = normalizeSort(SortRelevance)
+	assert.ErrorIs(t, err, ErrInvalidSort)
+	assert.False(t, IsSort(SortRelevance))
+}
+
+func TestCursor_Relevance(t *testing.T) {
+	comment := &model.Comment{ID: primitive.NewObjectID().Hex(), TextScore: 1.3333333333333333}
+	pc, err := decodeCursor(encodeCursor(comment, SortRelevance, false), SortRelevance)
+	assert.NoError(t, err)
+	// the score is compared for equality on the next page, it must round trip exactly
+	assert.Equal(t, comment.TextScore, pc.Value)
+}
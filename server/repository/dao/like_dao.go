@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	myMongo "github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mongo"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	options "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LikeDao interface {
+	Add(ctx context.Context, reviewID string, userID int, at time.Time) (added bool, err error)
+	Remove(ctx context.Context, reviewID string, userID int) (removed bool, err error)
+	DeleteByReviewID(ctx context.Context, reviewID string) (deleted int, err error)
+	ForEach(ctx context.Context, fn func(like *model.Like) error) error
+}
+
+var (
+	likeDaoInstance LikeDao
+	likeSyncOnce    sync.Once
+)
+
+func GetLikeDao() LikeDao {
+	likeSyncOnce.Do(func() {
+		likeDaoInstance = &LikeDaoImpl{
+			collection: myMongo.LikeCollection,
+		}
+	})
+	return likeDaoInstance
+}
+
+type LikeDaoImpl struct {
+	collection *mongo.Collection
+}
+
+// Add records the user's like on the review. added is false when it was
+// already recorded.
+func (l *LikeDaoImpl) Add(ctx context.Context, reviewID string, userID int, at time.Time) (added bool, err error) {
+	if l.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return false, nil
+	}
+	filter := bson.M{"review_id": reviewID, "user_id": userID}
+	update := bson.M{"$setOnInsert": bson.M{"created_at": at}}
+	result, err := l.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		// a concurrent like of the same user won the upsert
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		log.Ctx(ctx).Errorf("Add like failed\treview_id=%s\tuser_id=%d\terr=%v", reviewID, userID, err)
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// Remove deletes the user's like on the review. removed is false when there
+// was none.
+func (l *LikeDaoImpl) Remove(ctx context.Context, reviewID string, userID int) (removed bool, err error) {
+	if l.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return false, nil
+	}
+	result, err := l.collection.DeleteOne(ctx, bson.M{"review_id": reviewID, "user_id": userID})
+	if err != nil {
+		log.Ctx(ctx).Errorf("Remove like failed\treview_id=%s\tuser_id=%d\terr=%v", reviewID, userID, err)
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// DeleteByReviewID deletes every like of the review.
+func (l *LikeDaoImpl) DeleteByReviewID(ctx context.Context, reviewID string) (deleted int, err error) {
+	if l.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return 0, nil
+	}
+	result, err := l.collection.DeleteMany(ctx, bson.M{"review_id": reviewID})
+	if err != nil {
+		log.Ctx(ctx).Errorf("Delete likes failed\treview_id=%s\terr=%v", reviewID, err)
+		return 0, err
+	}
+	return int(result.DeletedCount), nil
+}
+
+// ForEach calls fn with every recorded like, ordered by review, and stops at
+// the first error fn returns.
+func (l *LikeDaoImpl) ForEach(ctx context.Context, fn func(like *model.Like) error) error {
+	if l.collection == nil {
+		log.Ctx(ctx).Errorf("mongo collection is nil")
+		return nil
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "review_id", Value: 1}, {Key: "user_id", Value: 1}})
+	cursor, err := l.collection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		log.Ctx(ctx).Errorf("Find likes failed\terr=%v", err)
+		return err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Ctx(ctx).Errorf("failed to close cursor: %v", err)
+		}
+	}()
+	for cursor.Next(ctx) {
+		var like model.Like
+		if err := cursor.Decode(&like); err != nil {
+			log.Ctx(ctx).Errorf("Decode like failed\terr=%v", err)
+			return err
+		}
+		if err := fn(&like); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
@@ -7,7 +7,9 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	dao "github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao"
 	model "github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +37,94 @@ func (m *MockCommentDao) EXPECT() *MockCommentDaoMockRecorder {
 	return m.recorder
 }
 
+// AcquireLock mocks base method.
+func (m *MockCommentDao) AcquireLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireLock", ctx, key, token, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireLock indicates an expected call of AcquireLock.
+func (mr *MockCommentDaoMockRecorder) AcquireLock(ctx, key, token, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLock", reflect.TypeOf((*MockCommentDao)(nil).AcquireLock), ctx, key, token, ttl)
+}
+
+// AddUnhelpfulVote mocks base method.
+func (m *MockCommentDao) AddUnhelpfulVote(ctx context.Context, id string, userID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUnhelpfulVote", ctx, id, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddUnhelpfulVote indicates an expected call of AddUnhelpfulVote.
+func (mr *MockCommentDaoMockRecorder) AddUnhelpfulVote(ctx, id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUnhelpfulVote", reflect.TypeOf((*MockCommentDao)(nil).AddUnhelpfulVote), ctx, id, userID)
+}
+
+// AggregateRatingByProductID mocks base method.
+func (m *MockCommentDao) AggregateRatingByProductID(ctx context.Context, productId int) (*model.RatingStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregateRatingByProductID", ctx, productId)
+	ret0, _ := ret[0].(*model.RatingStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AggregateRatingByProductID indicates an expected call of AggregateRatingByProductID.
+func (mr *MockCommentDaoMockRecorder) AggregateRatingByProductID(ctx, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateRatingByProductID", reflect.TypeOf((*MockCommentDao)(nil).AggregateRatingByProductID), ctx, productId)
+}
+
+// ClearStalePins mocks base method.
+func (m *MockCommentDao) ClearStalePins(ctx context.Context, productId int, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearStalePins", ctx, productId, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearStalePins indicates an expected call of ClearStalePins.
+func (mr *MockCommentDaoMockRecorder) ClearStalePins(ctx, productId, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearStalePins", reflect.TypeOf((*MockCommentDao)(nil).ClearStalePins), ctx, productId, now)
+}
+
+// CountByParentIDs mocks base method.
+func (m *MockCommentDao) CountByParentIDs(ctx context.Context, parentIDs []string) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByParentIDs", ctx, parentIDs)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByParentIDs indicates an expected call of CountByParentIDs.
+func (mr *MockCommentDaoMockRecorder) CountByParentIDs(ctx, parentIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByParentIDs", reflect.TypeOf((*MockCommentDao)(nil).CountByParentIDs), ctx, parentIDs)
+}
+
+// Del mocks base method.
+func (m *MockCommentDao) Del(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Del", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Del indicates an expected call of Del.
+func (mr *MockCommentDaoMockRecorder) Del(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockCommentDao)(nil).Del), ctx, key)
+}
+
 // Delete mocks base method.
 func (m *MockCommentDao) Delete(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -64,49 +154,189 @@ func (mr *MockCommentDaoMockRecorder) Get(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCommentDao)(nil).Get), ctx, id)
 }
 
-// GetListByProductID mocks base method.
-func (m *MockCommentDao) GetListByProductID(ctx context.Context, productId int) ([]*model.Comment, error) {
+// GetAllReplies mocks base method.
+func (m *MockCommentDao) GetAllReplies(ctx context.Context, parentID string) ([]*model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetListByProductID", ctx, productId)
+	ret := m.ctrl.Call(m, "GetAllReplies", ctx, parentID)
 	ret0, _ := ret[0].([]*model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetListByProductID indicates an expected call of GetListByProductID.
-func (mr *MockCommentDaoMockRecorder) GetListByProductID(ctx, productId interface{}) *gomock.Call {
+// GetAllReplies indicates an expected call of GetAllReplies.
+func (mr *MockCommentDaoMockRecorder) GetAllReplies(ctx, parentID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByProductID", reflect.TypeOf((*MockCommentDao)(nil).GetListByProductID), ctx, productId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllReplies", reflect.TypeOf((*MockCommentDao)(nil).GetAllReplies), ctx, parentID)
 }
 
-// GetListByQuery mocks base method.
-func (m *MockCommentDao) GetListByQuery(ctx context.Context, productId, stars int) ([]*model.Comment, error) {
+// GetLikeCounts mocks base method.
+func (m *MockCommentDao) GetLikeCounts(ctx context.Context) (map[string]int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetListByQuery", ctx, productId, stars)
+	ret := m.ctrl.Call(m, "GetLikeCounts", ctx)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLikeCounts indicates an expected call of GetLikeCounts.
+func (mr *MockCommentDaoMockRecorder) GetLikeCounts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLikeCounts", reflect.TypeOf((*MockCommentDao)(nil).GetLikeCounts), ctx)
+}
+
+// GetListByParentIDs mocks base method.
+func (m *MockCommentDao) GetListByParentIDs(ctx context.Context, parentIDs []string) ([]*model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByParentIDs", ctx, parentIDs)
 	ret0, _ := ret[0].([]*model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetListByParentIDs indicates an expected call of GetListByParentIDs.
+func (mr *MockCommentDaoMockRecorder) GetListByParentIDs(ctx, parentIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByParentIDs", reflect.TypeOf((*MockCommentDao)(nil).GetListByParentIDs), ctx, parentIDs)
+}
+
+// GetListByProductID mocks base method.
+func (m *MockCommentDao) GetListByProductID(ctx context.Context, productId int, page dao.Page) ([]*model.Comment, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByProductID", ctx, productId, page)
+	ret0, _ := ret[0].([]*model.Comment)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetListByProductID indicates an expected call of GetListByProductID.
+func (mr *MockCommentDaoMockRecorder) GetListByProductID(ctx, productId, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByProductID", reflect.TypeOf((*MockCommentDao)(nil).GetListByProductID), ctx, productId, page)
+}
+
+// GetListByQuery mocks base method.
+func (m *MockCommentDao) GetListByQuery(ctx context.Context, query dao.ReviewQuery, page dao.Page) ([]*model.Comment, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByQuery", ctx, query, page)
+	ret0, _ := ret[0].([]*model.Comment)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // GetListByQuery indicates an expected call of GetListByQuery.
-func (mr *MockCommentDaoMockRecorder) GetListByQuery(ctx, productId, stars interface{}) *gomock.Call {
+func (mr *MockCommentDaoMockRecorder) GetListByQuery(ctx, query, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByQuery", reflect.TypeOf((*MockCommentDao)(nil).GetListByQuery), ctx, query, page)
+}
+
+// GetListByStatus mocks base method.
+func (m *MockCommentDao) GetListByStatus(ctx context.Context, productId int, status string, page dao.Page) ([]*model.Comment, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByStatus", ctx, productId, status, page)
+	ret0, _ := ret[0].([]*model.Comment)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetListByStatus indicates an expected call of GetListByStatus.
+func (mr *MockCommentDaoMockRecorder) GetListByStatus(ctx, productId, status, page interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByQuery", reflect.TypeOf((*MockCommentDao)(nil).GetListByQuery), ctx, productId, stars)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByStatus", reflect.TypeOf((*MockCommentDao)(nil).GetListByStatus), ctx, productId, status, page)
 }
 
 // GetListByUserID mocks base method.
-func (m *MockCommentDao) GetListByUserID(ctx context.Context, userID int) ([]*model.Comment, error) {
+func (m *MockCommentDao) GetListByUserID(ctx context.Context, userID int, page dao.Page) ([]*model.Comment, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetListByUserID", ctx, userID)
+	ret := m.ctrl.Call(m, "GetListByUserID", ctx, userID, page)
+	ret0, _ := ret[0].([]*model.Comment)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetListByUserID indicates an expected call of GetListByUserID.
+func (mr *MockCommentDaoMockRecorder) GetListByUserID(ctx, userID, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByUserID", reflect.TypeOf((*MockCommentDao)(nil).GetListByUserID), ctx, userID, page)
+}
+
+// GetListDeleted mocks base method.
+func (m *MockCommentDao) GetListDeleted(ctx context.Context, productId int, page dao.Page) ([]*model.Comment, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListDeleted", ctx, productId, page)
+	ret0, _ := ret[0].([]*model.Comment)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetListDeleted indicates an expected call of GetListDeleted.
+func (mr *MockCommentDaoMockRecorder) GetListDeleted(ctx, productId, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListDeleted", reflect.TypeOf((*MockCommentDao)(nil).GetListDeleted), ctx, productId, page)
+}
+
+// GetListDeletedBefore mocks base method.
+func (m *MockCommentDao) GetListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListDeletedBefore", ctx, before, limit)
 	ret0, _ := ret[0].([]*model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetListByUserID indicates an expected call of GetListByUserID.
-func (mr *MockCommentDaoMockRecorder) GetListByUserID(ctx, userID interface{}) *gomock.Call {
+// GetListDeletedBefore indicates an expected call of GetListDeletedBefore.
+func (mr *MockCommentDaoMockRecorder) GetListDeletedBefore(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListDeletedBefore", reflect.TypeOf((*MockCommentDao)(nil).GetListDeletedBefore), ctx, before, limit)
+}
+
+// GetPinnedByProductID mocks base method.
+func (m *MockCommentDao) GetPinnedByProductID(ctx context.Context, productId int, now time.Time) ([]*model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPinnedByProductID", ctx, productId, now)
+	ret0, _ := ret[0].([]*model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPinnedByProductID indicates an expected call of GetPinnedByProductID.
+func (mr *MockCommentDaoMockRecorder) GetPinnedByProductID(ctx, productId, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPinnedByProductID", reflect.TypeOf((*MockCommentDao)(nil).GetPinnedByProductID), ctx, productId, now)
+}
+
+// GetRevisions mocks base method.
+func (m *MockCommentDao) GetRevisions(ctx context.Context, id string) ([]model.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, id)
+	ret0, _ := ret[0].([]model.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockCommentDaoMockRecorder) GetRevisions(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockCommentDao)(nil).GetRevisions), ctx, id)
+}
+
+// GetStr mocks base method.
+func (m *MockCommentDao) GetStr(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStr", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStr indicates an expected call of GetStr.
+func (mr *MockCommentDaoMockRecorder) GetStr(ctx, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByUserID", reflect.TypeOf((*MockCommentDao)(nil).GetListByUserID), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStr", reflect.TypeOf((*MockCommentDao)(nil).GetStr), ctx, key)
 }
 
 // HDel mocks base method.
@@ -138,6 +368,21 @@ func (mr *MockCommentDaoMockRecorder) HGet(ctx, key, member interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockCommentDao)(nil).HGet), ctx, key, member)
 }
 
+// HGetAll mocks base method.
+func (m *MockCommentDao) HGetAll(ctx context.Context, key string) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGetAll", ctx, key)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGetAll indicates an expected call of HGetAll.
+func (mr *MockCommentDaoMockRecorder) HGetAll(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGetAll", reflect.TypeOf((*MockCommentDao)(nil).HGetAll), ctx, key)
+}
+
 // HIncr mocks base method.
 func (m *MockCommentDao) HIncr(ctx context.Context, key, member string, deta int) error {
 	m.ctrl.T.Helper()
@@ -181,6 +426,91 @@ func (mr *MockCommentDaoMockRecorder) HSet(ctx, key, member, value interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockCommentDao)(nil).HSet), ctx, key, member, value)
 }
 
+// IncLikeCount mocks base method.
+func (m *MockCommentDao) IncLikeCount(ctx context.Context, id string, delta int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncLikeCount", ctx, id, delta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncLikeCount indicates an expected call of IncLikeCount.
+func (mr *MockCommentDaoMockRecorder) IncLikeCount(ctx, id, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncLikeCount", reflect.TypeOf((*MockCommentDao)(nil).IncLikeCount), ctx, id, delta)
+}
+
+// IncMerchantReplyCount mocks base method.
+func (m *MockCommentDao) IncMerchantReplyCount(ctx context.Context, id string, delta int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncMerchantReplyCount", ctx, id, delta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncMerchantReplyCount indicates an expected call of IncMerchantReplyCount.
+func (mr *MockCommentDaoMockRecorder) IncMerchantReplyCount(ctx, id, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncMerchantReplyCount", reflect.TypeOf((*MockCommentDao)(nil).IncMerchantReplyCount), ctx, id, delta)
+}
+
+// Incr mocks base method.
+func (m *MockCommentDao) Incr(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Incr", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Incr indicates an expected call of Incr.
+func (mr *MockCommentDaoMockRecorder) Incr(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockCommentDao)(nil).Incr), ctx, key)
+}
+
+// ReleaseLock mocks base method.
+func (m *MockCommentDao) ReleaseLock(ctx context.Context, key, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseLock", ctx, key, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseLock indicates an expected call of ReleaseLock.
+func (mr *MockCommentDaoMockRecorder) ReleaseLock(ctx, key, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseLock", reflect.TypeOf((*MockCommentDao)(nil).ReleaseLock), ctx, key, token)
+}
+
+// RemoveUnhelpfulVote mocks base method.
+func (m *MockCommentDao) RemoveUnhelpfulVote(ctx context.Context, id string, userID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUnhelpfulVote", ctx, id, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveUnhelpfulVote indicates an expected call of RemoveUnhelpfulVote.
+func (mr *MockCommentDaoMockRecorder) RemoveUnhelpfulVote(ctx, id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUnhelpfulVote", reflect.TypeOf((*MockCommentDao)(nil).RemoveUnhelpfulVote), ctx, id, userID)
+}
+
+// Restore mocks base method.
+func (m *MockCommentDao) Restore(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockCommentDaoMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockCommentDao)(nil).Restore), ctx, id)
+}
+
 // SAdd mocks base method.
 func (m *MockCommentDao) SAdd(ctx context.Context, key, member string) error {
 	m.ctrl.T.Helper()
@@ -195,6 +525,36 @@ func (mr *MockCommentDaoMockRecorder) SAdd(ctx, key, member interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAdd", reflect.TypeOf((*MockCommentDao)(nil).SAdd), ctx, key, member)
 }
 
+// SAddHIncr mocks base method.
+func (m *MockCommentDao) SAddHIncr(ctx context.Context, setKey, hashKey, member, indexKey, indexMember string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SAddHIncr", ctx, setKey, hashKey, member, indexKey, indexMember)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SAddHIncr indicates an expected call of SAddHIncr.
+func (mr *MockCommentDaoMockRecorder) SAddHIncr(ctx, setKey, hashKey, member, indexKey, indexMember interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAddHIncr", reflect.TypeOf((*MockCommentDao)(nil).SAddHIncr), ctx, setKey, hashKey, member, indexKey, indexMember)
+}
+
+// SMIsMember mocks base method.
+func (m *MockCommentDao) SMIsMember(ctx context.Context, key string, members []string) (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMIsMember", ctx, key, members)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SMIsMember indicates an expected call of SMIsMember.
+func (mr *MockCommentDaoMockRecorder) SMIsMember(ctx, key, members interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMIsMember", reflect.TypeOf((*MockCommentDao)(nil).SMIsMember), ctx, key, members)
+}
+
 // SMembers mocks base method.
 func (m *MockCommentDao) SMembers(ctx context.Context, key string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -210,6 +570,35 @@ func (mr *MockCommentDaoMockRecorder) SMembers(ctx, key interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMembers", reflect.TypeOf((*MockCommentDao)(nil).SMembers), ctx, key)
 }
 
+// SRem mocks base method.
+func (m *MockCommentDao) SRem(ctx context.Context, key, member string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SRem", ctx, key, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SRem indicates an expected call of SRem.
+func (mr *MockCommentDaoMockRecorder) SRem(ctx, key, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SRem", reflect.TypeOf((*MockCommentDao)(nil).SRem), ctx, key, member)
+}
+
+// SRemHDecr mocks base method.
+func (m *MockCommentDao) SRemHDecr(ctx context.Context, setKey, hashKey, member, indexKey, indexMember string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SRemHDecr", ctx, setKey, hashKey, member, indexKey, indexMember)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SRemHDecr indicates an expected call of SRemHDecr.
+func (mr *MockCommentDaoMockRecorder) SRemHDecr(ctx, setKey, hashKey, member, indexKey, indexMember interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SRemHDecr", reflect.TypeOf((*MockCommentDao)(nil).SRemHDecr), ctx, setKey, hashKey, member, indexKey, indexMember)
+}
+
 // Save mocks base method.
 func (m *MockCommentDao) Save(ctx context.Context, comment *model.Comment) error {
 	m.ctrl.T.Helper()
@@ -224,6 +613,92 @@ func (mr *MockCommentDaoMockRecorder) Save(ctx, comment interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCommentDao)(nil).Save), ctx, comment)
 }
 
+// SearchByText mocks base method.
+func (m *MockCommentDao) SearchByText(ctx context.Context, search dao.TextSearch, page dao.Page) ([]*model.Comment, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchByText", ctx, search, page)
+	ret0, _ := ret[0].([]*model.Comment)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchByText indicates an expected call of SearchByText.
+func (mr *MockCommentDaoMockRecorder) SearchByText(ctx, search, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchByText", reflect.TypeOf((*MockCommentDao)(nil).SearchByText), ctx, search, page)
+}
+
+// SetEx mocks base method.
+func (m *MockCommentDao) SetEx(ctx context.Context, key, value string, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEx", ctx, key, value, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEx indicates an expected call of SetEx.
+func (mr *MockCommentDaoMockRecorder) SetEx(ctx, key, value, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEx", reflect.TypeOf((*MockCommentDao)(nil).SetEx), ctx, key, value, expiration)
+}
+
+// SetLikeCount mocks base method.
+func (m *MockCommentDao) SetLikeCount(ctx context.Context, id string, count int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLikeCount", ctx, id, count)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLikeCount indicates an expected call of SetLikeCount.
+func (mr *MockCommentDaoMockRecorder) SetLikeCount(ctx, id, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLikeCount", reflect.TypeOf((*MockCommentDao)(nil).SetLikeCount), ctx, id, count)
+}
+
+// SetPinOrder mocks base method.
+func (m *MockCommentDao) SetPinOrder(ctx context.Context, productId int, pins []*model.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPinOrder", ctx, productId, pins)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPinOrder indicates an expected call of SetPinOrder.
+func (mr *MockCommentDaoMockRecorder) SetPinOrder(ctx, productId, pins interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPinOrder", reflect.TypeOf((*MockCommentDao)(nil).SetPinOrder), ctx, productId, pins)
+}
+
+// SoftDelete mocks base method.
+func (m *MockCommentDao) SoftDelete(ctx context.Context, id string, deletedBy int, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDelete", ctx, id, deletedBy, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SoftDelete indicates an expected call of SoftDelete.
+func (mr *MockCommentDaoMockRecorder) SoftDelete(ctx, id, deletedBy, deletedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockCommentDao)(nil).SoftDelete), ctx, id, deletedBy, deletedAt)
+}
+
+// UpdateContentByID mocks base method.
+func (m *MockCommentDao) UpdateContentByID(ctx context.Context, id string, edit *model.Comment, previous model.Revision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateContentByID", ctx, id, edit, previous)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateContentByID indicates an expected call of UpdateContentByID.
+func (mr *MockCommentDaoMockRecorder) UpdateContentByID(ctx, id, edit, previous interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContentByID", reflect.TypeOf((*MockCommentDao)(nil).UpdateContentByID), ctx, id, edit, previous)
+}
+
 // UpdateIsPinnedByID mocks base method.
 func (m *MockCommentDao) UpdateIsPinnedByID(ctx context.Context, id string, isPinned bool) error {
 	m.ctrl.T.Helper()
@@ -237,3 +712,17 @@ func (mr *MockCommentDaoMockRecorder) UpdateIsPinnedByID(ctx, id, isPinned inter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIsPinnedByID", reflect.TypeOf((*MockCommentDao)(nil).UpdateIsPinnedByID), ctx, id, isPinned)
 }
+
+// UpdateStatusByID mocks base method.
+func (m *MockCommentDao) UpdateStatusByID(ctx context.Context, id, status, reason string, moderatedBy int, moderatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatusByID", ctx, id, status, reason, moderatedBy, moderatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatusByID indicates an expected call of UpdateStatusByID.
+func (mr *MockCommentDaoMockRecorder) UpdateStatusByID(ctx, id, status, reason, moderatedBy, moderatedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusByID", reflect.TypeOf((*MockCommentDao)(nil).UpdateStatusByID), ctx, id, status, reason, moderatedBy, moderatedAt)
+}
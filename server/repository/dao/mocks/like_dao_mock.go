@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./dao/like_dao.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLikeDao is a mock of LikeDao interface.
+type MockLikeDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockLikeDaoMockRecorder
+}
+
+// MockLikeDaoMockRecorder is the mock recorder for MockLikeDao.
+type MockLikeDaoMockRecorder struct {
+	mock *MockLikeDao
+}
+
+// NewMockLikeDao creates a new mock instance.
+func NewMockLikeDao(ctrl *gomock.Controller) *MockLikeDao {
+	mock := &MockLikeDao{ctrl: ctrl}
+	mock.recorder = &MockLikeDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLikeDao) EXPECT() *MockLikeDaoMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockLikeDao) Add(ctx context.Context, reviewID string, userID int, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, reviewID, userID, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockLikeDaoMockRecorder) Add(ctx, reviewID, userID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockLikeDao)(nil).Add), ctx, reviewID, userID, at)
+}
+
+// DeleteByReviewID mocks base method.
+func (m *MockLikeDao) DeleteByReviewID(ctx context.Context, reviewID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByReviewID", ctx, reviewID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByReviewID indicates an expected call of DeleteByReviewID.
+func (mr *MockLikeDaoMockRecorder) DeleteByReviewID(ctx, reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByReviewID", reflect.TypeOf((*MockLikeDao)(nil).DeleteByReviewID), ctx, reviewID)
+}
+
+// ForEach mocks base method.
+func (m *MockLikeDao) ForEach(ctx context.Context, fn func(*model.Like) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEach", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEach indicates an expected call of ForEach.
+func (mr *MockLikeDaoMockRecorder) ForEach(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEach", reflect.TypeOf((*MockLikeDao)(nil).ForEach), ctx, fn)
+}
+
+// Remove mocks base method.
+func (m *MockLikeDao) Remove(ctx context.Context, reviewID string, userID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, reviewID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockLikeDaoMockRecorder) Remove(ctx, reviewID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockLikeDao)(nil).Remove), ctx, reviewID, userID)
+}
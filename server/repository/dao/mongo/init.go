@@ -2,24 +2,61 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
 )
 
+var ErrNotConnected = errors.New("mongo is not connected")
+
 var (
-	CommentCollection *mongo.Collection
+	client *mongo.Client
+
+	CommentCollection   *mongo.Collection
+	LikeCollection      *mongo.Collection
+	MigrationCollection *mongo.Collection // versions of the applied schema migrations
 )
 
+// Init connects to mongo. Indexes and backfills of the collections are
+// schema migrations, see the migration package.
 func Init() {
 	url := fmt.Sprintf("mongodb://%s:%d", config.Config.MongoConfig.Host, config.Config.MongoConfig.Port)
-	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(url))
+	var err error
+	opts := options.Client().ApplyURI(url)
+	if config.Config.MongoConfig.Username != "" {
+		opts.SetAuth(options.Credential{
+			Username: config.Config.MongoConfig.Username,
+			Password: config.Config.MongoConfig.Password,
+		})
+	}
+	client, err = mongo.Connect(context.TODO(), opts)
 	if err != nil {
 		panic(err)
 	}
 	database := client.Database(config.Config.MongoConfig.Database)
 	CommentCollection = database.Collection("comments")
+	LikeCollection = database.Collection("review_likes")
+	MigrationCollection = database.Collection("schema_migrations")
+}
+
+// Close disconnects from mongo, waiting for the operations in flight until
+// ctx is done.
+func Close(ctx context.Context) error {
+	if client == nil {
+		return nil
+	}
+	return client.Disconnect(ctx)
+}
+
+// Ping checks that the primary answers within ctx.
+func Ping(ctx context.Context) error {
+	if client == nil {
+		return ErrNotConnected
+	}
+	return client.Ping(ctx, readpref.Primary())
 }
@@ -0,0 +1,109 @@
+package dao
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewQuery filters the top level reviews of the merchant review list.
+// Zero values do not filter.
+type ReviewQuery struct {
+	ProductID   int
+	Stars       int // exact stars
+	MinStars    int
+	MaxStars    int
+	CreatedFrom *time.Time // inclusive
+	CreatedTo   *time.Time // exclusive
+	HasPhotos   *bool
+	IsAnonymous *bool
+	HasReply    *bool  // has a reply of the merchant
+	Status      string // moderation status, empty means the reviews shown to customers
+	UserIDs     []int
+	Keyword     string // case insensitive, matched anywhere in the content
+}
+
+// filter builds the mongo filter of the query.
+func (q ReviewQuery) filter() bson.M {
+	filter := bson.M{
+		"parent_id":  bson.M{"$in": topLevelParentIDs},
+		"status":     bson.M{"$in": visibleStatuses},
+		"deleted_at": nil,
+	}
+	if q.Status != "" && q.Status != model.StatusApproved {
+		filter["status"] = q.Status
+	}
+	if q.ProductID > 0 {
+		filter["product_id"] = q.ProductID
+	}
+
+	stars := bson.M{}
+	if q.MinStars > 0 {
+		stars["$gte"] = q.MinStars
+	}
+	if q.MaxStars > 0 {
+		stars["$lte"] = q.MaxStars
+	}
+	if q.Stars > 0 {
+		filter["stars"] = q.Stars
+	} else if len(stars) > 0 {
+		filter["stars"] = stars
+	}
+
+	createdAt := bson.M{}
+	if q.CreatedFrom != nil {
+		createdAt["$gte"] = *q.CreatedFrom
+	}
+	if q.CreatedTo != nil {
+		createdAt["$lt"] = *q.CreatedTo
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+
+	if q.HasPhotos != nil {
+		filter["pic_info.0"] = bson.M{"$exists": *q.HasPhotos}
+	}
+	if q.IsAnonymous != nil {
+		filter["is_anonymous"] = *q.IsAnonymous
+	}
+	if q.HasReply != nil {
+		if *q.HasReply {
+			filter["merchant_reply_count"] = bson.M{"$gt": 0}
+		} else {
+			// reviews without merchant replies have no count
+			filter["merchant_reply_count"] = bson.M{"$not": bson.M{"$gt": 0}}
+		}
+	}
+	if len(q.UserIDs) > 0 {
+		filter["user_id"] = bson.M{"$in": q.UserIDs}
+	}
+	if q.Keyword != "" {
+		filter["content"] = primitive.Regex{Pattern: regexp.QuoteMeta(q.Keyword), Options: "i"}
+	}
+	return filter
+}
+
+// TextSearch searches the content of top level reviews through the text index.
+type TextSearch struct {
+	Query       string // mongo $text syntax: terms, "phrases" and -negated terms
+	ProductIDs  []int  // at least one product
+	AllStatuses bool   // also search reviews customers do not see
+}
+
+// filter builds the mongo filter of the search.
+func (s TextSearch) filter() bson.M {
+	filter := bson.M{
+		"$text":      bson.M{"$search": s.Query},
+		"product_id": bson.M{"$in": s.ProductIDs},
+		"parent_id":  bson.M{"$in": topLevelParentIDs},
+		"deleted_at": nil,
+	}
+	if !s.AllStatuses {
+		filter["status"] = bson.M{"$in": visibleStatuses}
+	}
+	return filter
+}
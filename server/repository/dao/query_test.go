@@ -0,0 +1,73 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+)
+
+func TestReviewQuery_Empty(t *testing.T) {
+	assert.Equal(t, bson.M{
+		"parent_id":  bson.M{"$in": topLevelParentIDs},
+		"status":     bson.M{"$in": visibleStatuses},
+		"deleted_at": nil,
+	}, ReviewQuery{}.filter())
+}
+
+func TestReviewQuery_AllFilters(t *testing.T) {
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	yes, no := true, false
+
+	filter := ReviewQuery{
+		ProductID:   7,
+		MinStars:    2,
+		MaxStars:    4,
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		HasPhotos:   &yes,
+		IsAnonymous: &no,
+		HasReply:    &no,
+		Status:      model.StatusHidden,
+		UserIDs:     []int{1, 2},
+		Keyword:     "a+b",
+	}.filter()
+
+	assert.Equal(t, bson.M{
+		"parent_id":            bson.M{"$in": topLevelParentIDs},
+		"status":               model.StatusHidden,
+		"deleted_at":           nil,
+		"product_id":           7,
+		"stars":                bson.M{"$gte": 2, "$lte": 4},
+		"created_at":           bson.M{"$gte": from, "$lt": to},
+		"pic_info.0":           bson.M{"$exists": true},
+		"is_anonymous":         false,
+		"merchant_reply_count": bson.M{"$not": bson.M{"$gt": 0}},
+		"user_id":              bson.M{"$in": []int{1, 2}},
+		// the keyword is matched literally
+		"content": primitive.Regex{Pattern: `a\+b`, Options: "i"},
+	}, filter)
+}
+
+func TestReviewQuery_ExactStarsWins(t *testing.T) {
+	filter := ReviewQuery{Stars: 5, MinStars: 1, Status: model.StatusApproved}.filter()
+	assert.Equal(t, 5, filter["stars"])
+	// approved also matches reviews stored before moderation
+	assert.Equal(t, bson.M{"$in": visibleStatuses}, filter["status"])
+}
+
+func TestTextSearch_Filter(t *testing.T) {
+	filter := TextSearch{Query: `"hand made" -chip`, ProductIDs: []int{3}}.filter()
+	assert.Equal(t, bson.M{"$search": `"hand made" -chip`}, filter["$text"])
+	assert.Equal(t, bson.M{"$in": []int{3}}, filter["product_id"])
+	assert.Equal(t, bson.M{"$in": visibleStatuses}, filter["status"])
+
+	// merchants also find reviews customers do not see
+	filter = TextSearch{Query: "mug", ProductIDs: []int{3, 4}, AllStatuses: true}.filter()
+	assert.NotContains(t, filter, "status")
+}
@@ -1,20 +1,40 @@
 package redis
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
 	"github.com/redis/go-redis/v9"
 )
 
+var ErrNotConnected = errors.New("redis is not connected")
+
 var RedisClient *redis.Client
 
 func Init() {
 	addr := fmt.Sprintf("%s:%d", config.Config.RedisConfig.Host, config.Config.RedisConfig.Port)
 	RedisClient = redis.NewClient(&redis.Options{
 		Addr: addr,
-		Password: "",
+		Password: config.Config.RedisConfig.Password,
 		DB: 0,
 		PoolSize: 20,
 	})
-}
\ No newline at end of file
+}
+
+// Close closes the connections to redis.
+func Close() error {
+	if RedisClient == nil {
+		return nil
+	}
+	return RedisClient.Close()
+}
+
+// Ping checks that redis answers within ctx.
+func Ping(ctx context.Context) error {
+	if RedisClient == nil {
+		return ErrNotConnected
+	}
+	return RedisClient.Ping(ctx).Err()
+}
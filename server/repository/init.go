@@ -1,11 +1,29 @@
 package repository
 
 import (
+	"context"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/health"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mongo"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/redis"
 )
 
+// Init connects to the stores and registers them as dependencies of the
+// readiness checks.
 func Init() {
 	mongo.Init()
 	redis.Init()
+	health.Register("mongo", mongo.Ping)
+	health.Register("redis", redis.Ping)
+}
+
+// Close disconnects from the stores, it is called last at shutdown.
+func Close(ctx context.Context) {
+	if err := mongo.Close(ctx); err != nil {
+		log.Logger.Errorf("mongo disconnect failed: %v", err)
+	}
+	if err := redis.Close(); err != nil {
+		log.Logger.Errorf("redis close failed: %v", err)
+	}
 }
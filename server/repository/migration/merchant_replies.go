@@ -0,0 +1,326 @@
+package migration
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/authz"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	myMongo "github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mongo"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+)
+
+// replyBatchSize is the number of replies read, looked up and written at a time.
+const replyBatchSize = 500
+
+// errProductServiceRequired is returned by backfillMerchantReplies when no
+// product service tells the owners of the products.
+var errProductServiceRequired = errors.New("the product service must be configured to backfill merchant replies")
+
+// backfillMerchantReplies marks the merchant replies saved before replies
+// were marked when they are posted, and counts the merchant replies of every
+// review for the has_reply filter of the merchant review list. Those replies
+// were saved like any other, so a reply is taken for the merchant's when its
+// author owns the product of the thread. That guess is why it is opt-in, the
+// operator decides whether it fits the data.
+func backfillMerchantReplies(ctx context.Context) error {
+	if myMongo.CommentCollection == nil {
+		log.Logger.Errorf("mongo collection is nil")
+		return nil
+	}
+	if config.Config.ProductConfig == nil {
+		return errProductServiceRequired
+	}
+	if err := markMerchantReplies(ctx, authz.GetOwnershipResolver()); err != nil {
+		return err
+	}
+	return countMerchantReplies(ctx)
+}
+
+// markMerchantReplies sets is_merchant_reply on the replies whose author owns
+// the product of their thread. The replies are read and marked a batch at a
+// time, the owner of each product is asked once.
+func markMerchantReplies(ctx context.Context, resolver authz.ProductOwnershipResolver) error {
+	filter := bson.M{
+		"parent_id":         bson.M{"$nin": bson.A{"", "0"}},
+		"is_merchant_reply": bson.M{"$ne": true},
+	}
+	findOptions := options.Find().
+		SetProjection(bson.M{"user_id": 1, "product_id": 1, "parent_id": 1}).
+		SetBatchSize(replyBatchSize)
+	cursor, err := myMongo.CommentCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		log.Logger.Errorf("Find replies failed\terr=%v", err)
+		return err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Logger.Errorf("failed to close cursor: %v", err)
+		}
+	}()
+
+	owners := make(map[int]int) // product id -> merchant id
+	batch := make([]*model.Comment, 0, replyBatchSize)
+	marked := 0
+	flush := func() error {
+		count, err := markBatch(ctx, batch, resolver, owners)
+		marked += count
+		batch = batch[:0]
+		return err
+	}
+	for cursor.Next(ctx) {
+		var reply model.Comment
+		if err := cursor.Decode(&reply); err != nil {
+			log.Logger.Errorf("Decode reply failed\terr=%v", err)
+			return err
+		}
+		batch = append(batch, &reply)
+		if len(batch) == replyBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		log.Logger.Errorf("cursor iteration error\terr=%v", err)
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	log.Logger.Infof("marked %d merchant replies", marked)
+	return nil
+}
+
+// markBatch marks the replies of the batch written by the owner of the
+// product of their thread, and returns how many it marked. owners caches the
+// owners of the products already asked for.
+func markBatch(ctx context.Context, replies []*model.Comment, resolver authz.ProductOwnershipResolver, owners map[int]int) (int, error) {
+	if len(replies) == 0 {
+		return 0, nil
+	}
+	products, err := threadProducts(ctx, replies)
+	if err != nil {
+		return 0, err
+	}
+	merchantReplyIDs := bson.A{}
+	for _, reply := range replies {
+		productID := products[reply.ID]
+		if productID == 0 {
+			continue
+		}
+		owner, asked := owners[productID]
+		if !asked {
+			owner, err = resolver.ProductOwner(ctx, productID)
+			if err != nil {
+				return 0, err
+			}
+			owners[productID] = owner
+		}
+		if owner == 0 || owner != reply.UserID {
+			continue
+		}
+		if objectID, err := primitive.ObjectIDFromHex(reply.ID); err == nil {
+			merchantReplyIDs = append(merchantReplyIDs, objectID)
+		}
+	}
+	if len(merchantReplyIDs) == 0 {
+		return 0, nil
+	}
+	ret, err := myMongo.CommentCollection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": merchantReplyIDs}},
+		bson.M{"$set": bson.M{"is_merchant_reply": true}})
+	if err != nil {
+		log.Logger.Errorf("Mark merchant replies failed\terr=%v", err)
+		return 0, err
+	}
+	return int(ret.ModifiedCount), nil
+}
+
+// threadProducts returns the product of the thread of each reply by reply
+// id. Older replies may leave it to their thread, the parents of the whole
+// batch are then read one level at a time.
+func threadProducts(ctx context.Context, replies []*model.Comment) (map[string]int, error) {
+	products := make(map[string]int, len(replies))
+	waiting := make(map[string][]string) // parent id -> ids of the replies waiting for its product
+	for _, reply := range replies {
+		if reply.ProductID != 0 {
+			products[reply.ID] = reply.ProductID
+		} else if model.IsReplyParentID(reply.ParentID) {
+			waiting[reply.ParentID] = append(waiting[reply.ParentID], reply.ID)
+		}
+	}
+
+	findOptions := options.Find().SetProjection(bson.M{"product_id": 1, "parent_id": 1})
+	for len(waiting) > 0 {
+		parentIDs := bson.A{}
+		for parentID := range waiting {
+			if objectID, err := primitive.ObjectIDFromHex(parentID); err == nil {
+				parentIDs = append(parentIDs, objectID)
+			}
+		}
+		cursor, err := myMongo.CommentCollection.Find(ctx, bson.M{"_id": bson.M{"$in": parentIDs}}, findOptions)
+		if err != nil {
+			log.Logger.Errorf("Find parents failed\terr=%v", err)
+			return nil, err
+		}
+		var parents []*model.Comment
+		if err := cursor.All(ctx, &parents); err != nil {
+			log.Logger.Errorf("Decode parents failed\terr=%v", err)
+			return nil, err
+		}
+
+		next := make(map[string][]string)
+		for _, parent := range parents {
+			replyIDs := waiting[parent.ID]
+			if parent.ProductID != 0 {
+				for _, replyID := range replyIDs {
+					products[replyID] = parent.ProductID
+				}
+			} else if model.IsReplyParentID(parent.ParentID) {
+				next[parent.ParentID] = append(next[parent.ParentID], replyIDs...)
+			}
+		}
+		waiting = next
+	}
+	return products, nil
+}
+
+// countMerchantReplies sets the merchant reply count of every review to the
+// number of its merchant replies not in the trash, and drops the counts of
+// reviews left without any. Merchant replies posted while it runs may leave a
+// count off by one.
+func countMerchantReplies(ctx context.Context) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"is_merchant_reply": true, "deleted_at": nil}}},
+		{{Key: "$group", Value: bson.M{"_id": "$parent_id", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := myMongo.CommentCollection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		log.Logger.Errorf("Count merchant replies failed\terr=%v", err)
+		return err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Logger.Errorf("failed to close cursor: %v", err)
+		}
+	}()
+
+	counts := &bulkWriter{}
+	counted := make(map[string]bool)
+	for cursor.Next(ctx) {
+		var count struct {
+			ParentID string `bson:"_id"`
+			Count    int    `bson:"count"`
+		}
+		if err := cursor.Decode(&count); err != nil {
+			log.Logger.Errorf("Decode merchant reply count failed\terr=%v", err)
+			return err
+		}
+		objectID, err := primitive.ObjectIDFromHex(count.ParentID)
+		if err != nil {
+			continue
+		}
+		counted[count.ParentID] = true
+		err = counts.add(ctx, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": objectID}).
+			SetUpdate(bson.M{"$set": bson.M{"merchant_reply_count": count.Count}}))
+		if err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		log.Logger.Errorf("cursor iteration error\terr=%v", err)
+		return err
+	}
+	if err := counts.flush(ctx); err != nil {
+		return err
+	}
+
+	cleared, err := clearMerchantReplyCounts(ctx, counted)
+	if err != nil {
+		return err
+	}
+	log.Logger.Infof("counted merchant replies of %d reviews, cleared %d counts", counts.written, cleared)
+	return nil
+}
+
+// clearMerchantReplyCounts drops the merchant reply count of the reviews
+// that are not counted, and returns how many it dropped.
+func clearMerchantReplyCounts(ctx context.Context, counted map[string]bool) (int, error) {
+	findOptions := options.Find().SetProjection(bson.M{"_id": 1}).SetBatchSize(replyBatchSize)
+	cursor, err := myMongo.CommentCollection.Find(ctx, bson.M{"merchant_reply_count": bson.M{"$exists": true}}, findOptions)
+	if err != nil {
+		log.Logger.Errorf("Find merchant reply counts failed\terr=%v", err)
+		return 0, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Logger.Errorf("failed to close cursor: %v", err)
+		}
+	}()
+
+	clears := &bulkWriter{}
+	for cursor.Next(ctx) {
+		var review struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&review); err != nil {
+			log.Logger.Errorf("Decode review failed\terr=%v", err)
+			return 0, err
+		}
+		if counted[review.ID.Hex()] {
+			continue
+		}
+		err := clears.add(ctx, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": review.ID}).
+			SetUpdate(bson.M{"$unset": bson.M{"merchant_reply_count": ""}}))
+		if err != nil {
+			return 0, err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		log.Logger.Errorf("cursor iteration error\terr=%v", err)
+		return 0, err
+	}
+	if err := clears.flush(ctx); err != nil {
+		return 0, err
+	}
+	return clears.written, nil
+}
+
+// bulkWriter writes updates of the comments a batch at a time.
+type bulkWriter struct {
+	writes  []mongo.WriteModel
+	written int
+}
+
+// add queues the update and writes the batch once it is full.
+func (b *bulkWriter) add(ctx context.Context, write mongo.WriteModel) error {
+	b.writes = append(b.writes, write)
+	if len(b.writes) < replyBatchSize {
+		return nil
+	}
+	return b.flush(ctx)
+}
+
+// flush writes the queued updates.
+func (b *bulkWriter) flush(ctx context.Context) error {
+	if len(b.writes) == 0 {
+		return nil
+	}
+	_, err := myMongo.CommentCollection.BulkWrite(ctx, b.writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		log.Logger.Errorf("Bulk write comments failed\terr=%v", err)
+		return err
+	}
+	b.written += len(b.writes)
+	b.writes = b.writes[:0]
+	return nil
+}
@@ -0,0 +1,197 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	myMongo "github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mongo"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+)
+
+// ErrInvalidVersions is returned when the versions of the migrations are not
+// unique, positive and ascending.
+var ErrInvalidVersions = errors.New("migration versions must be positive and ascending")
+
+// ErrUnknownMigration is returned for a migration name the runner does not
+// know.
+var ErrUnknownMigration = errors.New("unknown migration")
+
+// Migration is a versioned change of the stored data. Up must be idempotent:
+// a migration that failed halfway runs again in full, and replicas starting
+// at the same time may run it concurrently.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context) error
+	// OptIn migrations are only applied by runs that opt in to them. Other
+	// runs leave them pending and go on with the later migrations.
+	OptIn bool
+}
+
+// RunOptions select what a run does.
+type RunOptions struct {
+	DryRun bool // only report the pending migrations
+	OptIn  bool // also apply the opt-in migrations
+}
+
+// Report lists the migrations a run found pending and the ones it applied. A
+// dry run applies none. Skipped lists the pending opt-in migrations of a run
+// that did not opt in, they are not in Pending.
+type Report struct {
+	Pending []Migration
+	Applied []Migration
+	Skipped []Migration
+}
+
+// history stores the versions of the applied migrations.
+type history interface {
+	Versions(ctx context.Context) (versions map[int]bool, err error)
+	Record(ctx context.Context, applied *model.SchemaMigration) error
+}
+
+// Runner applies the migrations missing from the history, in version order.
+type Runner struct {
+	migrations []Migration
+	history    history
+}
+
+var (
+	runnerInstance *Runner
+	runnerSyncOnce sync.Once
+)
+
+// GetRunner returns the runner of the migrations of this service, recording
+// them in the schema_migrations collection.
+func GetRunner() *Runner {
+	runnerSyncOnce.Do(func() {
+		runnerInstance = &Runner{
+			migrations: migrations(),
+			history:    &mongoHistory{collection: myMongo.MigrationCollection},
+		}
+	})
+	return runnerInstance
+}
+
+// Run applies the pending migrations and stops at the first one that fails,
+// the migrations applied before it stay recorded. A dry run only reports the
+// pending migrations.
+func (r *Runner) Run(ctx context.Context, opts RunOptions) (report Report, err error) {
+	known := make(map[int]bool, len(r.migrations))
+	for idx, m := range r.migrations {
+		if m.Version <= 0 || (idx > 0 && m.Version <= r.migrations[idx-1].Version) {
+			log.Logger.Errorf("invalid migration version\tversion=%d\tname=%s", m.Version, m.Name)
+			return Report{}, ErrInvalidVersions
+		}
+		known[m.Version] = true
+	}
+
+	versions, err := r.history.Versions(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+	for version := range versions {
+		if !known[version] {
+			log.Logger.Warnf("unknown migration applied, the database was migrated by a newer release\tversion=%d", version)
+		}
+	}
+	for _, m := range r.migrations {
+		switch {
+		case versions[m.Version]:
+		case m.OptIn && !opts.OptIn:
+			report.Skipped = append(report.Skipped, m)
+		default:
+			report.Pending = append(report.Pending, m)
+		}
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for _, m := range report.Pending {
+		start := time.Now()
+		if err := m.Up(ctx); err != nil {
+			log.Logger.Errorf("migration failed\tversion=%d\tname=%s\terr=%v", m.Version, m.Name, err)
+			return report, err
+		}
+		applied := &model.SchemaMigration{
+			Version:    m.Version,
+			Name:       m.Name,
+			AppliedAt:  time.Now(),
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err := r.history.Record(ctx, applied); err != nil {
+			return report, err
+		}
+		log.Logger.Infof("migration applied\tversion=%d\tname=%s\tduration_ms=%d", m.Version, m.Name, applied.DurationMs)
+		report.Applied = append(report.Applied, m)
+	}
+	return report, nil
+}
+
+// IsApplied reports whether the migration of the given name was applied.
+func (r *Runner) IsApplied(ctx context.Context, name string) (bool, error) {
+	for _, m := range r.migrations {
+		if m.Name != name {
+			continue
+		}
+		versions, err := r.history.Versions(ctx)
+		if err != nil {
+			return false, err
+		}
+		return versions[m.Version], nil
+	}
+	return false, ErrUnknownMigration
+}
+
+// mongoHistory stores the applied migrations in a collection, one document
+// per version.
+type mongoHistory struct {
+	collection *mongo.Collection
+}
+
+// Versions implements history.
+func (h *mongoHistory) Versions(ctx context.Context) (versions map[int]bool, err error) {
+	versions = make(map[int]bool)
+	if h.collection == nil {
+		log.Logger.Errorf("mongo collection is nil")
+		return versions, nil
+	}
+	cursor, err := h.collection.Find(ctx, bson.M{})
+	if err != nil {
+		log.Logger.Errorf("Find schema migrations failed\terr=%v", err)
+		return nil, err
+	}
+	var applied []model.SchemaMigration
+	if err := cursor.All(ctx, &applied); err != nil {
+		log.Logger.Errorf("Decode schema migrations failed\terr=%v", err)
+		return nil, err
+	}
+	for _, m := range applied {
+		versions[m.Version] = true
+	}
+	return versions, nil
+}
+
+// Record implements history.
+func (h *mongoHistory) Record(ctx context.Context, applied *model.SchemaMigration) error {
+	if h.collection == nil {
+		log.Logger.Errorf("mongo collection is nil")
+		return nil
+	}
+	_, err := h.collection.InsertOne(ctx, applied)
+	// a replica starting at the same time applied it too
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	if err != nil {
+		log.Logger.Errorf("Record schema migration failed\tversion=%d\terr=%v", applied.Version, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+)
+
+func init() {
+	logger, _ := zap.NewDevelopment()
+	log.Logger = logger.Sugar()
+}
+
+type fakeHistory struct {
+	versions map[int]bool
+	recorded []int
+}
+
+func (h *fakeHistory) Versions(ctx context.Context) (map[int]bool, error) {
+	return h.versions, nil
+}
+
+func (h *fakeHistory) Record(ctx context.Context, applied *model.SchemaMigration) error {
+	h.versions[applied.Version] = true
+	h.recorded = append(h.recorded, applied.Version)
+	return nil
+}
+
+// tracked returns migrations of the versions that append their version to ran.
+func tracked(ran *[]int, versions ...int) []Migration {
+	list := make([]Migration, 0, len(versions))
+	for _, version := range versions {
+		version := version
+		list = append(list, Migration{Version: version, Name: "test", Up: func(ctx context.Context) error {
+			*ran = append(*ran, version)
+			return nil
+		}})
+	}
+	return list
+}
+
+func TestRun_AppliesPendingInOrder(t *testing.T) {
+	var ran []int
+	history := &fakeHistory{versions: map[int]bool{2: true}}
+	runner := &Runner{migrations: tracked(&ran, 1, 2, 3), history: history}
+
+	report, err := runner.Run(context.Background(), RunOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, ran)
+	assert.Equal(t, []int{1, 3}, history.recorded)
+	assert.Len(t, report.Pending, 2)
+	assert.Len(t, report.Applied, 2)
+
+	// a second run finds nothing to do
+	report, err = runner.Run(context.Background(), RunOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, report.Pending)
+	assert.Equal(t, []int{1, 3}, ran)
+}
+
+func TestRun_DryRun(t *testing.T) {
+	var ran []int
+	history := &fakeHistory{versions: map[int]bool{1: true}}
+	runner := &Runner{migrations: tracked(&ran, 1, 2), history: history}
+
+	report, err := runner.Run(context.Background(), RunOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.Empty(t, ran)
+	assert.Empty(t, history.recorded)
+	assert.Len(t, report.Pending, 1)
+	assert.Equal(t, 2, report.Pending[0].Version)
+	assert.Empty(t, report.Applied)
+}
+
+func TestRun_OptIn(t *testing.T) {
+	var ran []int
+	history := &fakeHistory{versions: map[int]bool{}}
+	list := tracked(&ran, 1, 2, 3)
+	list[1].OptIn = true
+	runner := &Runner{migrations: list, history: history}
+
+	// the migrations after a skipped one are applied
+	report, err := runner.Run(context.Background(), RunOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, ran)
+	assert.Len(t, report.Skipped, 1)
+	assert.Equal(t, 2, report.Skipped[0].Version)
+
+	report, err = runner.Run(context.Background(), RunOptions{OptIn: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3, 2}, ran)
+	assert.Empty(t, report.Skipped)
+	assert.Len(t, report.Applied, 1)
+}
+
+func TestRun_StopsAtFailure(t *testing.T) {
+	var ran []int
+	history := &fakeHistory{versions: map[int]bool{}}
+	list := tracked(&ran, 1, 2, 3)
+	list[1].Up = func(ctx context.Context) error { return assert.AnError }
+	runner := &Runner{migrations: list, history: history}
+
+	report, err := runner.Run(context.Background(), RunOptions{})
+	assert.ErrorIs(t, err, assert.AnError)
+	// the migration before the failure stays applied, the ones after wait
+	assert.Equal(t, []int{1}, ran)
+	assert.Equal(t, []int{1}, history.recorded)
+	assert.Len(t, report.Applied, 1)
+}
+
+func TestRun_InvalidVersions(t *testing.T) {
+	var ran []int
+	for _, versions := range [][]int{{1, 1}, {2, 1}, {0}} {
+		runner := &Runner{migrations: tracked(&ran, versions...), history: &fakeHistory{versions: map[int]bool{}}}
+		_, err := runner.Run(context.Background(), RunOptions{})
+		assert.ErrorIs(t, err, ErrInvalidVersions, "versions %v", versions)
+	}
+	assert.Empty(t, ran)
+}
+
+func TestMigrations_VersionsAscending(t *testing.T) {
+	list := migrations()
+	for idx := range list {
+		assert.NotEmpty(t, list[idx].Name)
+		assert.NotNil(t, list[idx].Up)
+		if idx > 0 {
+			assert.Greater(t, list[idx].Version, list[idx-1].Version)
+		}
+	}
+}
+
+func TestIsApplied(t *testing.T) {
+	var ran []int
+	list := tracked(&ran, 1, 2)
+	list[1].Name = "second"
+	runner := &Runner{migrations: list, history: &fakeHistory{versions: map[int]bool{1: true}}}
+
+	applied, err := runner.IsApplied(context.Background(), "second")
+	assert.NoError(t, err)
+	assert.False(t, applied)
+
+	_, err = runner.Run(context.Background(), RunOptions{})
+	assert.NoError(t, err)
+	applied, err = runner.IsApplied(context.Background(), "second")
+	assert.NoError(t, err)
+	assert.True(t, applied)
+
+	_, err = runner.IsApplied(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrUnknownMigration)
+}
@@ -0,0 +1,229 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao"
+	myMongo "github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mongo"
+	myRedis "github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/redis"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+)
+
+const (
+	// userLikesKeyFmt is the set of reviews a user liked, cached in redis
+	userLikesKeyFmt = "user:%d:likes"
+	// reviewLikersKeyFmt is the set of users who liked a review, the reverse
+	// index of the like sets of the users
+	reviewLikersKeyFmt = "review:%s:likers"
+	scanCount          = 500
+)
+
+// ImportCachedLikes names the migration recording the likes that were only
+// cached in redis. Until it is applied the recorded likes are incomplete.
+const ImportCachedLikes = "import_cached_likes"
+
+// migrations returns the migrations of the service in version order. New
+// migrations are appended, a released one is never changed or removed.
+func migrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "create_like_indexes", Up: createLikeIndexes},
+		{Version: 2, Name: "create_query_indexes", Up: createQueryIndexes},
+		{Version: 3, Name: "backfill_vote_counts", Up: backfillVoteCounts},
+		{Version: 4, Name: ImportCachedLikes, Up: importCachedLikes},
+		{Version: 5, Name: "count_likes", Up: countLikes},
+		{Version: 6, Name: "create_pin_position_index", Up: createPinPositionIndex},
+		{Version: 7, Name: "backfill_merchant_replies", Up: backfillMerchantReplies, OptIn: true},
+	}
+}
+
+// createLikeIndexes makes a user like a review at most once.
+func createLikeIndexes(ctx context.Context) error {
+	reviewUser := mongo.IndexModel{
+		Keys:    bson.D{{Key: "review_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetName("uniq_review_user").SetUnique(true),
+	}
+	return createIndexes(ctx, myMongo.LikeCollection, reviewUser)
+}
+
+// createQueryIndexes backs the review listings and the search. The keys of
+// the listings end with their sort key and _id, the tie break of the
+// pagination cursor.
+func createQueryIndexes(ctx context.Context) error {
+	index := func(name string, keys bson.D) mongo.IndexModel {
+		return mongo.IndexModel{Keys: keys, Options: options.Index().SetName(name)}
+	}
+	return createIndexes(ctx, myMongo.CommentCollection,
+		// product lists and the merchant review list in every sort
+		index("product_created", bson.D{{Key: "product_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}),
+		index("product_stars_created", bson.D{{Key: "product_id", Value: 1}, {Key: "stars", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}),
+		index("product_like_count", bson.D{{Key: "product_id", Value: 1}, {Key: "like_count", Value: -1}, {Key: "_id", Value: -1}}),
+		index("product_helpful_score", bson.D{{Key: "product_id", Value: 1}, {Key: "helpful_score", Value: -1}, {Key: "_id", Value: -1}}),
+		// the merchant review list filtered by status or authors
+		index("status_created", bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}),
+		index("user_created", bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}),
+		// full-text search of the review content
+		mongo.IndexModel{Keys: bson.D{{Key: "content", Value: "text"}}, Options: options.Index().SetName("content_text")},
+		// replies of a page of reviews
+		index("parent_created", bson.D{{Key: "parent_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+}
+
+// createPinPositionIndex allows at most one pinned review per position of a
+// product. Pins saved before positions existed have none and are not
+// indexed. It fails while stored pins break it, they have to be fixed first.
+func createPinPositionIndex(ctx context.Context) error {
+	pinPosition := mongo.IndexModel{
+		Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "pin_position", Value: 1}},
+		Options: options.Index().
+			SetName("uniq_product_pin_position").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{
+				"is_pinned":    true,
+				"pin_position": bson.M{"$exists": true},
+			}),
+	}
+	return createIndexes(ctx, myMongo.CommentCollection, pinPosition)
+}
+
+// createIndexes creates the indexes missing from the collection, an existing
+// index with the same name and keys is left as is.
+func createIndexes(ctx context.Context, collection *mongo.Collection, indexes ...mongo.IndexModel) error {
+	if collection == nil {
+		log.Logger.Errorf("mongo collection is nil")
+		return nil
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		log.Logger.Errorf("Create indexes failed\tcollection=%s\terr=%v", collection.Name(), err)
+		return err
+	}
+	return nil
+}
+
+// backfillVoteCounts gives comments stored before votes were counted zero
+// counts, so the like and helpful sorts can page through them.
+func backfillVoteCounts(ctx context.Context) error {
+	if myMongo.CommentCollection == nil {
+		log.Logger.Errorf("mongo collection is nil")
+		return nil
+	}
+	defaults := bson.M{"like_count": 0, "unhelpful_count": 0, "helpful_score": 0.0}
+	missing := bson.A{}
+	set := bson.M{}
+	for field, value := range defaults {
+		missing = append(missing, bson.M{field: bson.M{"$exists": false}})
+		set[field] = bson.M{"$ifNull": bson.A{"$" + field, value}}
+	}
+	filter := bson.M{"$or": missing}
+	update := mongo.Pipeline{{{Key: "$set", Value: set}}}
+	ret, err := myMongo.CommentCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		log.Logger.Errorf("Backfill vote counts failed\terr=%v", err)
+		return err
+	}
+	log.Logger.Infof("backfilled vote counts of %d comments", ret.ModifiedCount)
+	return nil
+}
+
+// importCachedLikes records the likes that were only cached in the like sets
+// of the users in redis, from before likes were recorded in mongo, and adds
+// those users to the likers of the reviews, which were not kept then. Likes
+// of reviews that no longer exist are left out. The time of those likes is
+// unknown, they are recorded at the time of the import.
+func importCachedLikes(ctx context.Context) error {
+	if myRedis.RedisClient == nil {
+		log.Logger.Errorf("redis client is nil")
+		return nil
+	}
+	commentDao, likeDao := dao.GetCommentDao(), dao.GetLikeDao()
+	now := time.Now()
+	exists := make(map[string]bool)
+	imported := 0
+
+	iter := myRedis.RedisClient.Scan(ctx, 0, "user:*:likes", scanCount).Iterator()
+	for iter.Next(ctx) {
+		var userID int
+		if _, err := fmt.Sscanf(iter.Val(), userLikesKeyFmt, &userID); err != nil {
+			continue
+		}
+		reviewIDs, err := commentDao.SMembers(ctx, iter.Val())
+		if err != nil {
+			return err
+		}
+		for _, reviewID := range reviewIDs {
+			found, checked := exists[reviewID]
+			if !checked {
+				_, err := commentDao.Get(ctx, reviewID)
+				if err != nil && !errors.Is(err, dao.ErrNotFound) {
+					return err
+				}
+				found = err == nil
+				exists[reviewID] = found
+			}
+			if !found {
+				continue
+			}
+			if err := commentDao.SAdd(ctx, fmt.Sprintf(reviewLikersKeyFmt, reviewID), strconv.Itoa(userID)); err != nil {
+				return err
+			}
+			added, err := likeDao.Add(ctx, reviewID, userID, now)
+			if err != nil {
+				return err
+			}
+			if added {
+				imported++
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		log.Logger.Errorf("Scan user like sets failed\terr=%v", err)
+		return err
+	}
+	log.Logger.Infof("imported %d likes cached in redis", imported)
+	return nil
+}
+
+// countLikes sets the like count of every review to the number of its
+// recorded likes. Likes made while it runs may leave a count off by one,
+// reconcile-likes reports and fixes it.
+func countLikes(ctx context.Context) error {
+	commentDao, likeDao := dao.GetCommentDao(), dao.GetLikeDao()
+	counts := make(map[string]int)
+	err := likeDao.ForEach(ctx, func(like *model.Like) error {
+		counts[like.ReviewID]++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	stored, err := commentDao.GetLikeCounts(ctx)
+	if err != nil {
+		return err
+	}
+	for reviewID := range stored {
+		if _, ok := counts[reviewID]; !ok {
+			counts[reviewID] = 0
+		}
+	}
+
+	updated := 0
+	for reviewID, count := range counts {
+		if stored[reviewID] == count {
+			continue
+		}
+		if err := commentDao.SetLikeCount(ctx, reviewID, count); err != nil {
+			return err
+		}
+		updated++
+	}
+	log.Logger.Infof("counted likes, %d like counts updated", updated)
+	return nil
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"math"
 	"time"
 )
 
@@ -15,4 +16,101 @@ type Comment struct {
 	IsPinned    bool      `bson:"is_pinned" json:"is_pinned"`
 	PicInfo     []string  `bson:"pic_info" json:"pic_info"`
 	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+
+	IsMerchantReply    bool `bson:"is_merchant_reply,omitempty" json:"is_merchant_reply,omitempty"`       // a reply of the product's merchant
+	MerchantReplyCount int  `bson:"merchant_reply_count,omitempty" json:"merchant_reply_count,omitempty"` // replies of the merchant not in the trash
+
+	Status           string     `bson:"status,omitempty" json:"status"`
+	ModerationReason string     `bson:"moderation_reason,omitempty" json:"moderation_reason,omitempty"`
+	ModeratedBy      int        `bson:"moderated_by,omitempty" json:"moderated_by,omitempty"`
+	ModeratedAt      *time.Time `bson:"moderated_at,omitempty" json:"moderated_at,omitempty"`
+
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // set while the comment is in the trash
+	DeletedBy int        `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+
+	PinPosition int        `bson:"pin_position,omitempty" json:"pin_position,omitempty"` // 1 based order among the product's pinned reviews
+	PinnedUntil *time.Time `bson:"pinned_until,omitempty" json:"pinned_until,omitempty"` // the pin expires after this time, never when nil
+
+	LikeCount      int     `bson:"like_count" json:"like_count"` // likes recorded in review_likes
+	UnhelpfulCount int     `bson:"unhelpful_count" json:"unhelpful_count"`
+	UnhelpfulBy    []int   `bson:"unhelpful_by,omitempty" json:"-"`    // users who found the comment unhelpful, not loaded by default
+	HelpfulScore   float64 `bson:"helpful_score" json:"helpful_score"` // HelpfulScore of the like and unhelpful counts
+
+	TextScore float64 `bson:"score,omitempty" json:"-"` // relevance, only set on text search results
+
+	EditedAt  *time.Time `bson:"edited_at,omitempty" json:"edited_at,omitempty"`
+	Revisions []Revision `bson:"revisions,omitempty" json:"-"` // previous versions, oldest first, not loaded by default
+}
+
+// Revision is a version of a comment replaced by an edit of its author.
+type Revision struct {
+	Content   string    `bson:"content" json:"content"`
+	Stars     int       `bson:"stars" json:"stars"`
+	PicInfo   []string  `bson:"pic_info" json:"pic_info"`
+	RevisedAt time.Time `bson:"revised_at" json:"revised_at"` // when this version was replaced
+}
+
+// Moderation statuses of a comment. Only approved comments are shown to customers.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+	StatusHidden   = "hidden"
+)
+
+// IsModerationStatus reports whether status is one of the known moderation statuses.
+func IsModerationStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusApproved, StatusRejected, StatusHidden:
+		return true
+	}
+	return false
+}
+
+// ModerationStatus returns the status of the comment. Comments stored before
+// moderation existed have no status and count as approved.
+func (c *Comment) ModerationStatus() string {
+	if c.Status == "" {
+		return StatusApproved
+	}
+	return c.Status
+}
+
+// IsReply reports whether the comment replies to another comment.
+func (c *Comment) IsReply() bool {
+	return IsReplyParentID(c.ParentID)
+}
+
+// IsDeleted reports whether the comment is in the trash.
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// IsPinnedAt reports whether the comment is pinned and its pin has not
+// expired at t.
+func (c *Comment) IsPinnedAt(t time.Time) bool {
+	return c.IsPinned && (c.PinnedUntil == nil || c.PinnedUntil.After(t))
+}
+
+// helpfulZ is the z-score of the 95% confidence level used by HelpfulScore.
+const helpfulZ = 1.96
+
+// HelpfulScore ranks a review by the lower bound of the Wilson score interval
+// of the share of its votes that are helpful, likes count as helpful votes. A
+// review with a few votes ranks below one with as good a share of many votes.
+// It is 0 without votes.
+func HelpfulScore(helpful, unhelpful int) float64 {
+	n := float64(helpful + unhelpful)
+	if n <= 0 {
+		return 0
+	}
+	p := float64(helpful) / n
+	z2 := helpfulZ * helpfulZ
+	return (p + z2/(2*n) - helpfulZ*math.Sqrt((p*(1-p)+z2/(4*n))/n)) / (1 + z2/n)
+}
+
+// IsReplyParentID reports whether parentID refers to another comment. Top level
+// reviews have an empty parent id, "0" is also accepted for older documents.
+func IsReplyParentID(parentID string) bool {
+	return parentID != "" && parentID != "0"
 }
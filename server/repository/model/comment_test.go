@@ -0,0 +1,20 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelpfulScore(t *testing.T) {
+	assert.Equal(t, 0.0, HelpfulScore(0, 0))
+	assert.Equal(t, 0.0, HelpfulScore(0, 5))
+	assert.InDelta(t, 0.2065, HelpfulScore(1, 0), 1e-4)
+
+	// the same share ranks higher with more votes
+	assert.Greater(t, HelpfulScore(90, 10), HelpfulScore(9, 1))
+	// a single like does not beat a well liked review with a few downvotes
+	assert.Greater(t, HelpfulScore(40, 5), HelpfulScore(1, 0))
+	// more unhelpful votes lower the score
+	assert.Greater(t, HelpfulScore(10, 1), HelpfulScore(10, 4))
+}
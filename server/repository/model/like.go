@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+)
+
+// Like records that a user liked a comment. It is the durable copy of the
+// like counters and like sets cached in redis.
+type Like struct {
+	ID        string    `bson:"_id,omitempty" json:"id"`
+	ReviewID  string    `bson:"review_id" json:"review_id"`
+	UserID    int       `bson:"user_id" json:"user_id"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
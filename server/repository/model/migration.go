@@ -0,0 +1,13 @@
+package model
+
+import (
+	"time"
+)
+
+// SchemaMigration records a schema migration applied to the database.
+type SchemaMigration struct {
+	Version    int       `bson:"_id" json:"version"`
+	Name       string    `bson:"name" json:"name"`
+	AppliedAt  time.Time `bson:"applied_at" json:"applied_at"`
+	DurationMs int64     `bson:"duration_ms" json:"duration_ms"`
+}
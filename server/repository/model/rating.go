@@ -0,0 +1,15 @@
+package model
+
+// RatingStats is the result of aggregating the comments of one product.
+// Only top level reviews are counted in the star figures.
+type RatingStats struct {
+	TotalCount int `bson:"total_count"`
+	StarSum    int `bson:"star_sum"`
+	Star1      int `bson:"star_1"`
+	Star2      int `bson:"star_2"`
+	Star3      int `bson:"star_3"`
+	Star4      int `bson:"star_4"`
+	Star5      int `bson:"star_5"`
+	PhotoCount int `bson:"photo_count"`
+	ReplyCount int `bson:"reply_count"`
+}
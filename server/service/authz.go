@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+)
+
+// checkProductOwner returns ErrForbidden unless the merchant owns the product.
+func (r *ReviewServiceImpl) checkProductOwner(ctx context.Context, merchantID int, productID int) error {
+	if r.ownership == nil {
+		return ErrForbidden
+	}
+	isOwner, err := r.ownership.IsProductOwner(ctx, merchantID, productID)
+	if err != nil {
+		return err
+	}
+	if !isOwner {
+		log.Ctx(ctx).Warnf("merchant %d is not the owner of product %d", merchantID, productID)
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+)
+
+func TestPinReview_NotProductOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(1, 50)}
+
+	mockDao.EXPECT().Get(gomock.Any(), "a1").Return(&model.Comment{ID: "a1", ProductID: 50}, nil)
+
+	err := svc.PinReview(context.Background(), "a1", types.PinReviewRequest{IsPinned: true}, 2)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestDeleteReview_NoResolverForbids(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	mockDao.EXPECT().Get(gomock.Any(), "a2").Return(&model.Comment{ID: "a2", ProductID: 50}, nil)
+
+	err := svc.DeleteReview(context.Background(), "a2", 1)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestDeleteOwnReview_Author(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	mockDao.EXPECT().Get(gomock.Any(), "a3").Return(&model.Comment{ID: "a3", ProductID: 50, UserID: 30}, nil)
+	mockDao.EXPECT().SoftDelete(gomock.Any(), "a3", 30, gomock.Any()).Return(nil)
+	mockDao.EXPECT().Incr(gomock.Any(), "product:50:rating_version").Return(nil)
+
+	err := svc.DeleteOwnReview(context.Background(), "a3", 30)
+	assert.NoError(t, err)
+}
+
+func TestDeleteOwnReview_NotAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	mockDao.EXPECT().Get(gomock.Any(), "a4").Return(&model.Comment{ID: "a4", ProductID: 50, UserID: 30}, nil)
+
+	err := svc.DeleteOwnReview(context.Background(), "a4", 31)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestReplyReview_ProductOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(1, 50)}
+
+	req := types.CreateReviewRequest{ParentID: "a5", Content: "thanks for the feedback"}
+	parent := &model.Comment{ID: "a5", ProductID: 50}
+
+	// once for the ownership check and once when the reply is created
+	mockDao.EXPECT().Get(gomock.Any(), "a5").Return(parent, nil).Times(2)
+	mockDao.EXPECT().Save(gomock.Any(), gomock.AssignableToTypeOf(&model.Comment{})).
+		DoAndReturn(func(ctx context.Context, reply *model.Comment) error {
+			assert.True(t, reply.IsMerchantReply)
+			return nil
+		})
+	mockDao.EXPECT().Incr(gomock.Any(), "product:50:rating_version").Return(nil)
+	mockDao.EXPECT().IncMerchantReplyCount(gomock.Any(), "a5", 1).Return(nil)
+
+	err := svc.ReplyReview(context.Background(), req, 1)
+	assert.NoError(t, err)
+}
+
+func TestReplyReview_NotProductOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(1, 50)}
+
+	mockDao.EXPECT().Get(gomock.Any(), "a6").Return(&model.Comment{ID: "a6", ProductID: 50}, nil)
+
+	err := svc.ReplyReview(context.Background(), types.CreateReviewRequest{ParentID: "a6", Content: "hi"}, 2)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
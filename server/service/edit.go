@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/validation"
+)
+
+const defaultEditWindow = 7 * 24 * time.Hour
+
+func editWindow() time.Duration {
+	if config.Config.ReviewConfig != nil && config.Config.ReviewConfig.EditWindow > 0 {
+		return config.Config.ReviewConfig.EditWindow
+	}
+	return defaultEditWindow
+}
+
+// EditReview lets the author change the content, stars and pictures of a
+// review within the edit window. The replaced version is kept as a revision.
+// With the hold moderation policy the edited review goes back to pending.
+func (r *ReviewServiceImpl) EditReview(ctx context.Context, reviewID string, req types.EditReviewRequest, userID int) (detail types.ReviewInfo, err error) {
+	commentRaw, err := r.reviewDao.Get(ctx, reviewID)
+	if err != nil {
+		return types.ReviewInfo{}, err
+	}
+	if commentRaw.IsDeleted() {
+		return types.ReviewInfo{}, ErrReviewNotFound
+	}
+	if commentRaw.UserID != userID {
+		return types.ReviewInfo{}, ErrForbidden
+	}
+	if err := validation.ValidateEditReview(req, commentRaw.IsReply()); err != nil {
+		return types.ReviewInfo{}, err
+	}
+	now := time.Now()
+	if now.Sub(commentRaw.CreatedAt) > editWindow() {
+		return types.ReviewInfo{}, ErrEditWindowClosed
+	}
+
+	edit := &model.Comment{
+		Content:  commentRaw.Content,
+		Stars:    commentRaw.Stars,
+		PicInfo:  commentRaw.PicInfo,
+		Status:   commentRaw.ModerationStatus(),
+		EditedAt: &now,
+	}
+	if req.Content != nil {
+		edit.Content = *req.Content
+	}
+	if req.Stars != nil {
+		edit.Stars = *req.Stars
+	}
+	if req.PicInfo != nil {
+		edit.PicInfo = *req.PicInfo
+	}
+	if initialStatus() == model.StatusPending {
+		edit.Status = model.StatusPending
+	}
+
+	previous := model.Revision{
+		Content:   commentRaw.Content,
+		Stars:     commentRaw.Stars,
+		PicInfo:   commentRaw.PicInfo,
+		RevisedAt: now,
+	}
+	if err := r.reviewDao.UpdateContentByID(ctx, reviewID, edit, previous); err != nil {
+		return types.ReviewInfo{}, err
+	}
+	r.invalidateRatingSummary(ctx, commentRaw.ProductID)
+	if commentRaw.IsPinned {
+		r.invalidatePinnedCache(ctx, commentRaw.ProductID)
+	}
+
+	return r.getReviewDetail(ctx, reviewID, userID)
+}
+
+// GetRevisions returns the versions of a review of one of the merchant's
+// products replaced by edits, oldest first.
+func (r *ReviewServiceImpl) GetRevisions(ctx context.Context, reviewID string, merchantID int) (resp types.ReviewRevisionsResponse, err error) {
+	commentRaw, err := r.reviewDao.Get(ctx, reviewID)
+	if err != nil {
+		return types.ReviewRevisionsResponse{}, err
+	}
+	if err := r.checkProductOwner(ctx, merchantID, commentRaw.ProductID); err != nil {
+		return types.ReviewRevisionsResponse{}, err
+	}
+
+	revisions, err := r.reviewDao.GetRevisions(ctx, reviewID)
+	if err != nil {
+		return types.ReviewRevisionsResponse{}, err
+	}
+
+	resp = types.ReviewRevisionsResponse{
+		ReviewID:  reviewID,
+		Revisions: make([]types.ReviewRevision, len(revisions)),
+	}
+	for idx, revision := range revisions {
+		resp.Revisions[idx] = types.ReviewRevision{
+			Content:   revision.Content,
+			Stars:     revision.Stars,
+			PicInfo:   revision.PicInfo,
+			RevisedAt: revision.RevisedAt,
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+)
+
+func TestEditReview_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	userID := 10
+	original := &model.Comment{
+		ID:        "e1",
+		UserID:    userID,
+		ProductID: 3,
+		Content:   "ok",
+		Stars:     3,
+		PicInfo:   []string{"a.jpg"},
+		CreatedAt: time.Now().Add(-time.Hour),
+	}
+	content := "great after a week"
+	stars := 5
+	req := types.EditReviewRequest{Content: &content, Stars: &stars}
+
+	mockDao.EXPECT().Get(gomock.Any(), "e1").Return(original, nil)
+	mockDao.EXPECT().UpdateContentByID(gomock.Any(), "e1", gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, id string, edit *model.Comment, previous model.Revision) error {
+			assert.Equal(t, content, edit.Content)
+			assert.Equal(t, stars, edit.Stars)
+			// pictures were left out of the request and are kept
+			assert.Equal(t, []string{"a.jpg"}, edit.PicInfo)
+			assert.Equal(t, model.StatusApproved, edit.Status)
+			assert.NotNil(t, edit.EditedAt)
+			assert.Equal(t, "ok", previous.Content)
+			assert.Equal(t, 3, previous.Stars)
+			return nil
+		})
+	mockDao.EXPECT().Incr(gomock.Any(), "product:3:rating_version").Return(nil)
+
+	editedAt := time.Now()
+	mockDao.EXPECT().Get(gomock.Any(), "e1").Return(&model.Comment{
+		ID: "e1", UserID: userID, Content: content, Stars: stars, EditedAt: &editedAt,
+	}, nil)
+	mockDao.EXPECT().HGet(gomock.Any(), reviewLikesCntKey, "e1").Return("", nil)
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:10:likes", gomock.Any()).Return(map[string]bool{}, nil)
+
+	detail, err := svc.EditReview(context.Background(), "e1", req, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, content, detail.Content)
+	assert.NotNil(t, detail.EditedAt)
+}
+
+func TestEditReview_HoldPolicyBackToPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	old := config.Config.ReviewConfig
+	config.Config.ReviewConfig = &config.ReviewConfig{ModerationPolicy: "hold"}
+	defer func() { config.Config.ReviewConfig = old }()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	content := "edited"
+	mockDao.EXPECT().Get(gomock.Any(), "e2").Return(&model.Comment{ID: "e2", UserID: 1, CreatedAt: time.Now()}, nil)
+	mockDao.EXPECT().UpdateContentByID(gomock.Any(), "e2", gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, id string, edit *model.Comment, previous model.Revision) error {
+			assert.Equal(t, model.StatusPending, edit.Status)
+			return nil
+		})
+	mockDao.EXPECT().Incr(gomock.Any(), gomock.Any()).Return(nil)
+	mockDao.EXPECT().Get(gomock.Any(), "e2").Return(&model.Comment{ID: "e2", Status: model.StatusPending}, nil)
+	mockDao.EXPECT().HGet(gomock.Any(), reviewLikesCntKey, "e2").Return("", nil)
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:1:likes", gomock.Any()).Return(map[string]bool{}, nil)
+
+	detail, err := svc.EditReview(context.Background(), "e2", types.EditReviewRequest{Content: &content}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusPending, detail.Status)
+}
+
+func TestEditReview_NotAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	mockDao.EXPECT().Get(gomock.Any(), "e3").Return(&model.Comment{ID: "e3", UserID: 1, CreatedAt: time.Now()}, nil)
+
+	_, err := svc.EditReview(context.Background(), "e3", types.EditReviewRequest{}, 2)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestEditReview_WindowClosed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	createdAt := time.Now().Add(-defaultEditWindow - time.Minute)
+	mockDao.EXPECT().Get(gomock.Any(), "e4").Return(&model.Comment{ID: "e4", UserID: 1, CreatedAt: createdAt}, nil)
+
+	_, err := svc.EditReview(context.Background(), "e4", types.EditReviewRequest{}, 1)
+	assert.ErrorIs(t, err, ErrEditWindowClosed)
+}
+
+func TestGetRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 2)}
+
+	revisedAt := time.Now()
+	mockDao.EXPECT().Get(gomock.Any(), "e5").Return(&model.Comment{ID: "e5", ProductID: 2}, nil)
+	mockDao.EXPECT().GetRevisions(gomock.Any(), "e5").Return([]model.Revision{
+		{Content: "first", Stars: 2, RevisedAt: revisedAt},
+	}, nil)
+
+	resp, err := svc.GetRevisions(context.Background(), "e5", 9)
+	assert.NoError(t, err)
+	assert.Equal(t, "e5", resp.ReviewID)
+	assert.Len(t, resp.Revisions, 1)
+	assert.Equal(t, "first", resp.Revisions[0].Content)
+	assert.Equal(t, revisedAt, resp.Revisions[0].RevisedAt)
+}
+
+func TestGetRevisions_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 2)}
+
+	mockDao.EXPECT().Get(gomock.Any(), "e6").Return(nil, dao.ErrNotFound)
+
+	_, err := svc.GetRevisions(context.Background(), "e6", 9)
+	assert.ErrorIs(t, err, ErrReviewNotFound)
+}
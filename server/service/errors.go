@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao"
+)
+
+var (
+	// ErrInvalidCursor is returned when the pagination cursor of a list request is malformed.
+	ErrInvalidCursor = dao.ErrInvalidCursor
+	// ErrInvalidSort is returned when a list request asks for an unknown sort.
+	ErrInvalidSort = dao.ErrInvalidSort
+	// ErrReviewNotFound is returned when the review does not exist.
+	ErrReviewNotFound = dao.ErrNotFound
+	// ErrParentNotFound is returned when a reply refers to a review that does not exist.
+	ErrParentNotFound = errors.New("parent review not found")
+	// ErrParentProductMismatch is returned when a reply and its parent belong to different products.
+	ErrParentProductMismatch = errors.New("parent review belongs to another product")
+	// ErrInvalidStatus is returned for an unknown or disallowed moderation status.
+	ErrInvalidStatus = errors.New("invalid moderation status")
+	// ErrReasonRequired is returned when a review is rejected or hidden without a reason.
+	ErrReasonRequired = errors.New("moderation reason is required")
+	// ErrReviewNotApproved is returned when an action needs an approved review.
+	ErrReviewNotApproved = errors.New("review is not approved")
+	// ErrForbidden is returned when the caller may not act on the review.
+	ErrForbidden = errors.New("forbidden")
+	// ErrEditWindowClosed is returned when the author edits a review after the edit window.
+	ErrEditWindowClosed = errors.New("review can no longer be edited")
+	// ErrTooManyPinned is returned when pinning a review would exceed the pinned reviews allowed per product.
+	ErrTooManyPinned = errors.New("too many pinned reviews")
+	// ErrInvalidPinExpiry is returned when a pin expiry time is not in the future.
+	ErrInvalidPinExpiry = errors.New("pin expiry must be in the future")
+	// ErrPinBusy is returned when another change of the product's pinned reviews holds the lock for too long.
+	ErrPinBusy = errors.New("pinned reviews are being changed, retry later")
+	// ErrPinConflict is returned when the pinned reviews were changed concurrently.
+	ErrPinConflict = dao.ErrPinConflict
+	// ErrCannotPinReply is returned when a merchant pins a reply.
+	ErrCannotPinReply = errors.New("replies cannot be pinned")
+)
@@ -0,0 +1,415 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/review.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	types "github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReviewService is a mock of ReviewService interface.
+type MockReviewService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewServiceMockRecorder
+}
+
+// MockReviewServiceMockRecorder is the mock recorder for MockReviewService.
+type MockReviewServiceMockRecorder struct {
+	mock *MockReviewService
+}
+
+// NewMockReviewService creates a new mock instance.
+func NewMockReviewService(ctrl *gomock.Controller) *MockReviewService {
+	mock := &MockReviewService{ctrl: ctrl}
+	mock.recorder = &MockReviewServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewService) EXPECT() *MockReviewServiceMockRecorder {
+	return m.recorder
+}
+
+// BatchGetRatingSummary mocks base method.
+func (m *MockReviewService) BatchGetRatingSummary(ctx context.Context, productIds []int) (map[int]types.RatingSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchGetRatingSummary", ctx, productIds)
+	ret0, _ := ret[0].(map[int]types.RatingSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGetRatingSummary indicates an expected call of BatchGetRatingSummary.
+func (mr *MockReviewServiceMockRecorder) BatchGetRatingSummary(ctx, productIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGetRatingSummary", reflect.TypeOf((*MockReviewService)(nil).BatchGetRatingSummary), ctx, productIds)
+}
+
+// CreateReview mocks base method.
+func (m *MockReviewService) CreateReview(ctx context.Context, req types.CreateReviewRequest, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReview", ctx, req, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReview indicates an expected call of CreateReview.
+func (mr *MockReviewServiceMockRecorder) CreateReview(ctx, req, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReview", reflect.TypeOf((*MockReviewService)(nil).CreateReview), ctx, req, userID)
+}
+
+// DeleteOwnReview mocks base method.
+func (m *MockReviewService) DeleteOwnReview(ctx context.Context, reviewID string, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOwnReview", ctx, reviewID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOwnReview indicates an expected call of DeleteOwnReview.
+func (mr *MockReviewServiceMockRecorder) DeleteOwnReview(ctx, reviewID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOwnReview", reflect.TypeOf((*MockReviewService)(nil).DeleteOwnReview), ctx, reviewID, userID)
+}
+
+// DeleteReview mocks base method.
+func (m *MockReviewService) DeleteReview(ctx context.Context, reviewID string, merchantID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", ctx, reviewID, merchantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockReviewServiceMockRecorder) DeleteReview(ctx, reviewID, merchantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockReviewService)(nil).DeleteReview), ctx, reviewID, merchantID)
+}
+
+// EditReview mocks base method.
+func (m *MockReviewService) EditReview(ctx context.Context, reviewID string, req types.EditReviewRequest, userID int) (types.ReviewInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditReview", ctx, reviewID, req, userID)
+	ret0, _ := ret[0].(types.ReviewInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditReview indicates an expected call of EditReview.
+func (mr *MockReviewServiceMockRecorder) EditReview(ctx, reviewID, req, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditReview", reflect.TypeOf((*MockReviewService)(nil).EditReview), ctx, reviewID, req, userID)
+}
+
+// GetListByProductID mocks base method.
+func (m *MockReviewService) GetListByProductID(ctx context.Context, req types.ListReviewRequest, userID int) (types.ListReviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByProductID", ctx, req, userID)
+	ret0, _ := ret[0].(types.ListReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListByProductID indicates an expected call of GetListByProductID.
+func (mr *MockReviewServiceMockRecorder) GetListByProductID(ctx, req, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByProductID", reflect.TypeOf((*MockReviewService)(nil).GetListByProductID), ctx, req, userID)
+}
+
+// GetListByQuery mocks base method.
+func (m *MockReviewService) GetListByQuery(ctx context.Context, req types.ListReviewRequest, userID int) (types.ListReviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByQuery", ctx, req, userID)
+	ret0, _ := ret[0].(types.ListReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListByQuery indicates an expected call of GetListByQuery.
+func (mr *MockReviewServiceMockRecorder) GetListByQuery(ctx, req, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByQuery", reflect.TypeOf((*MockReviewService)(nil).GetListByQuery), ctx, req, userID)
+}
+
+// GetListByUserID mocks base method.
+func (m *MockReviewService) GetListByUserID(ctx context.Context, req types.ListReviewRequest, userID int) (types.ListReviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByUserID", ctx, req, userID)
+	ret0, _ := ret[0].(types.ListReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListByUserID indicates an expected call of GetListByUserID.
+func (mr *MockReviewServiceMockRecorder) GetListByUserID(ctx, req, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByUserID", reflect.TypeOf((*MockReviewService)(nil).GetListByUserID), ctx, req, userID)
+}
+
+// GetRatingSummary mocks base method.
+func (m *MockReviewService) GetRatingSummary(ctx context.Context, productId int) (types.RatingSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRatingSummary", ctx, productId)
+	ret0, _ := ret[0].(types.RatingSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRatingSummary indicates an expected call of GetRatingSummary.
+func (mr *MockReviewServiceMockRecorder) GetRatingSummary(ctx, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRatingSummary", reflect.TypeOf((*MockReviewService)(nil).GetRatingSummary), ctx, productId)
+}
+
+// GetReview mocks base method.
+func (m *MockReviewService) GetReview(ctx context.Context, reviewID string, userID int) (types.ReviewInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReview", ctx, reviewID, userID)
+	ret0, _ := ret[0].(types.ReviewInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReview indicates an expected call of GetReview.
+func (mr *MockReviewServiceMockRecorder) GetReview(ctx, reviewID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReview", reflect.TypeOf((*MockReviewService)(nil).GetReview), ctx, reviewID, userID)
+}
+
+// GetRevisions mocks base method.
+func (m *MockReviewService) GetRevisions(ctx context.Context, reviewID string, merchantID int) (types.ReviewRevisionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, reviewID, merchantID)
+	ret0, _ := ret[0].(types.ReviewRevisionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockReviewServiceMockRecorder) GetRevisions(ctx, reviewID, merchantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockReviewService)(nil).GetRevisions), ctx, reviewID, merchantID)
+}
+
+// Like mocks base method.
+func (m *MockReviewService) Like(ctx context.Context, req types.LikeRequest, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Like", ctx, req, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Like indicates an expected call of Like.
+func (mr *MockReviewServiceMockRecorder) Like(ctx, req, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Like", reflect.TypeOf((*MockReviewService)(nil).Like), ctx, req, userID)
+}
+
+// ListModerationQueue mocks base method.
+func (m *MockReviewService) ListModerationQueue(ctx context.Context, req types.ModerationQueueRequest, merchantID int) (types.ListReviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListModerationQueue", ctx, req, merchantID)
+	ret0, _ := ret[0].(types.ListReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListModerationQueue indicates an expected call of ListModerationQueue.
+func (mr *MockReviewServiceMockRecorder) ListModerationQueue(ctx, req, merchantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModerationQueue", reflect.TypeOf((*MockReviewService)(nil).ListModerationQueue), ctx, req, merchantID)
+}
+
+// ListTrash mocks base method.
+func (m *MockReviewService) ListTrash(ctx context.Context, req types.ListReviewRequest, merchantID int) (types.ListReviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, req, merchantID)
+	ret0, _ := ret[0].(types.ListReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockReviewServiceMockRecorder) ListTrash(ctx, req, merchantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockReviewService)(nil).ListTrash), ctx, req, merchantID)
+}
+
+// MarkUnhelpful mocks base method.
+func (m *MockReviewService) MarkUnhelpful(ctx context.Context, reviewID string, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUnhelpful", ctx, reviewID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkUnhelpful indicates an expected call of MarkUnhelpful.
+func (mr *MockReviewServiceMockRecorder) MarkUnhelpful(ctx, reviewID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUnhelpful", reflect.TypeOf((*MockReviewService)(nil).MarkUnhelpful), ctx, reviewID, userID)
+}
+
+// ModerateReview mocks base method.
+func (m *MockReviewService) ModerateReview(ctx context.Context, reviewID string, req types.ModerateReviewRequest, moderatorID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateReview", ctx, reviewID, req, moderatorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModerateReview indicates an expected call of ModerateReview.
+func (mr *MockReviewServiceMockRecorder) ModerateReview(ctx, reviewID, req, moderatorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateReview", reflect.TypeOf((*MockReviewService)(nil).ModerateReview), ctx, reviewID, req, moderatorID)
+}
+
+// PinReview mocks base method.
+func (m *MockReviewService) PinReview(ctx context.Context, reviewID string, req types.PinReviewRequest, merchantID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinReview", ctx, reviewID, req, merchantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PinReview indicates an expected call of PinReview.
+func (mr *MockReviewServiceMockRecorder) PinReview(ctx, reviewID, req, merchantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinReview", reflect.TypeOf((*MockReviewService)(nil).PinReview), ctx, reviewID, req, merchantID)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockReviewService) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockReviewServiceMockRecorder) PurgeDeleted(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockReviewService)(nil).PurgeDeleted), ctx, before)
+}
+
+// ReconcileLikes mocks base method.
+func (m *MockReviewService) ReconcileLikes(ctx context.Context, fix bool) (types.LikeReconcileReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileLikes", ctx, fix)
+	ret0, _ := ret[0].(types.LikeReconcileReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileLikes indicates an expected call of ReconcileLikes.
+func (mr *MockReviewServiceMockRecorder) ReconcileLikes(ctx, fix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileLikes", reflect.TypeOf((*MockReviewService)(nil).ReconcileLikes), ctx, fix)
+}
+
+// ReplyReview mocks base method.
+func (m *MockReviewService) ReplyReview(ctx context.Context, req types.CreateReviewRequest, merchantID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplyReview", ctx, req, merchantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplyReview indicates an expected call of ReplyReview.
+func (mr *MockReviewServiceMockRecorder) ReplyReview(ctx, req, merchantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplyReview", reflect.TypeOf((*MockReviewService)(nil).ReplyReview), ctx, req, merchantID)
+}
+
+// RestoreReview mocks base method.
+func (m *MockReviewService) RestoreReview(ctx context.Context, reviewID string, merchantID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreReview", ctx, reviewID, merchantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreReview indicates an expected call of RestoreReview.
+func (mr *MockReviewServiceMockRecorder) RestoreReview(ctx, reviewID, merchantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreReview", reflect.TypeOf((*MockReviewService)(nil).RestoreReview), ctx, reviewID, merchantID)
+}
+
+// SearchMerchantReviews mocks base method.
+func (m *MockReviewService) SearchMerchantReviews(ctx context.Context, req types.SearchReviewRequest, merchantID int) (types.ListReviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchMerchantReviews", ctx, req, merchantID)
+	ret0, _ := ret[0].(types.ListReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchMerchantReviews indicates an expected call of SearchMerchantReviews.
+func (mr *MockReviewServiceMockRecorder) SearchMerchantReviews(ctx, req, merchantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchMerchantReviews", reflect.TypeOf((*MockReviewService)(nil).SearchMerchantReviews), ctx, req, merchantID)
+}
+
+// SearchProductReviews mocks base method.
+func (m *MockReviewService) SearchProductReviews(ctx context.Context, productID int, req types.SearchReviewRequest, userID int) (types.ListReviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchProductReviews", ctx, productID, req, userID)
+	ret0, _ := ret[0].(types.ListReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchProductReviews indicates an expected call of SearchProductReviews.
+func (mr *MockReviewServiceMockRecorder) SearchProductReviews(ctx, productID, req, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchProductReviews", reflect.TypeOf((*MockReviewService)(nil).SearchProductReviews), ctx, productID, req, userID)
+}
+
+// Unlike mocks base method.
+func (m *MockReviewService) Unlike(ctx context.Context, req types.LikeRequest, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlike", ctx, req, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlike indicates an expected call of Unlike.
+func (mr *MockReviewServiceMockRecorder) Unlike(ctx, req, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlike", reflect.TypeOf((*MockReviewService)(nil).Unlike), ctx, req, userID)
+}
+
+// UnmarkUnhelpful mocks base method.
+func (m *MockReviewService) UnmarkUnhelpful(ctx context.Context, reviewID string, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmarkUnhelpful", ctx, reviewID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnmarkUnhelpful indicates an expected call of UnmarkUnhelpful.
+func (mr *MockReviewServiceMockRecorder) UnmarkUnhelpful(ctx, reviewID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmarkUnhelpful", reflect.TypeOf((*MockReviewService)(nil).UnmarkUnhelpful), ctx, reviewID, userID)
+}
+
+// UnpinReview mocks base method.
+func (m *MockReviewService) UnpinReview(ctx context.Context, reviewID string, merchantID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinReview", ctx, reviewID, merchantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpinReview indicates an expected call of UnpinReview.
+func (mr *MockReviewServiceMockRecorder) UnpinReview(ctx, reviewID, merchantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinReview", reflect.TypeOf((*MockReviewService)(nil).UnpinReview), ctx, reviewID, merchantID)
+}
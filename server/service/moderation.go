@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/validation"
+)
+
+const moderationPolicyHold = "hold"
+
+// initialStatus is the status of a newly saved review. With the hold policy
+// reviews wait in the moderation queue, otherwise they are approved right away.
+func initialStatus() string {
+	if config.Config.ReviewConfig != nil && config.Config.ReviewConfig.ModerationPolicy == moderationPolicyHold {
+		return model.StatusPending
+	}
+	return model.StatusApproved
+}
+
+// ListModerationQueue returns reviews and replies of one of the merchant's
+// products in the requested status, pending ones by default, newest first.
+func (r *ReviewServiceImpl) ListModerationQueue(ctx context.Context, req types.ModerationQueueRequest, merchantID int) (resp types.ListReviewResponse, err error) {
+	if err := validation.ValidateMerchantProduct(req.ProductID); err != nil {
+		return types.ListReviewResponse{}, err
+	}
+	status := req.Status
+	if status == "" {
+		status = model.StatusPending
+	}
+	if !model.IsModerationStatus(status) {
+		return types.ListReviewResponse{}, ErrInvalidStatus
+	}
+	if err := r.checkProductOwner(ctx, merchantID, req.ProductID); err != nil {
+		return types.ListReviewResponse{}, err
+	}
+
+	listRaw, nextCursor, err := r.reviewDao.GetListByStatus(ctx, req.ProductID, status, dao.Page{Cursor: req.Cursor, Limit: req.Limit})
+	if err != nil {
+		return types.ListReviewResponse{}, err
+	}
+
+	list, err := r.buildReviewInfoList(ctx, listRaw, 0)
+	if err != nil {
+		return types.ListReviewResponse{}, err
+	}
+
+	return types.ListReviewResponse{
+		ReviewList: list,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// ModerateReview records a moderation decision of the merchant owning the
+// product. Reviews that are no longer approved leave the rating summary and
+// lose their pin.
+func (r *ReviewServiceImpl) ModerateReview(ctx context.Context, reviewID string, req types.ModerateReviewRequest, moderatorID int) (err error) {
+	if req.Status == model.StatusPending || !model.IsModerationStatus(req.Status) {
+		return ErrInvalidStatus
+	}
+	if req.Status != model.StatusApproved && req.Reason == "" {
+		return ErrReasonRequired
+	}
+
+	commentRaw, err := r.reviewDao.Get(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if commentRaw.IsDeleted() {
+		return ErrReviewNotFound
+	}
+	if err := r.checkProductOwner(ctx, moderatorID, commentRaw.ProductID); err != nil {
+		return err
+	}
+
+	err = r.reviewDao.UpdateStatusByID(ctx, reviewID, req.Status, req.Reason, moderatorID, time.Now())
+	if err != nil {
+		return err
+	}
+	log.Ctx(ctx).Infof("ModerateReview: review %s set to %s by %d", reviewID, req.Status, moderatorID)
+	r.invalidateRatingSummary(ctx, commentRaw.ProductID)
+
+	if req.Status == model.StatusApproved || !commentRaw.IsPinned {
+		return nil
+	}
+	return r.clearPin(ctx, commentRaw)
+}
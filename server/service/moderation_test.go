@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/validation"
+)
+
+func TestCreateReview_HoldPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	old := config.Config.ReviewConfig
+	config.Config.ReviewConfig = &config.ReviewConfig{ModerationPolicy: "hold"}
+	defer func() { config.Config.ReviewConfig = old }()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	mockDao.EXPECT().Save(gomock.Any(), gomock.AssignableToTypeOf(&model.Comment{})).DoAndReturn(
+		func(ctx context.Context, c *model.Comment) error {
+			assert.Equal(t, model.StatusPending, c.Status)
+			return nil
+		})
+	mockDao.EXPECT().Incr(gomock.Any(), "product:3:rating_version").Return(nil)
+
+	err := svc.CreateReview(context.Background(), types.CreateReviewRequest{ProductID: 3, Stars: 4, Content: "nice"}, 1)
+	assert.NoError(t, err)
+}
+
+func TestListModerationQueue_DefaultsToPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 3)}
+
+	mockDao.EXPECT().GetListByStatus(gomock.Any(), 3, model.StatusPending, dao.Page{Limit: 10}).
+		Return([]*model.Comment{{ID: "q1", Status: model.StatusPending}}, "next", nil)
+	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, []string{"q1"}).Return(map[string]int{}, nil)
+
+	resp, err := svc.ListModerationQueue(context.Background(), types.ModerationQueueRequest{ProductID: 3, Limit: 10}, 9)
+	assert.NoError(t, err)
+	assert.Len(t, resp.ReviewList, 1)
+	assert.Equal(t, model.StatusPending, resp.ReviewList[0].Status)
+	assert.Equal(t, "next", resp.NextCursor)
+}
+
+func TestListModerationQueue_InvalidStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := &ReviewServiceImpl{reviewDao: mocks.NewMockCommentDao(ctrl)}
+
+	_, err := svc.ListModerationQueue(context.Background(), types.ModerationQueueRequest{ProductID: 3, Status: "spam"}, 9)
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
+
+func TestListModerationQueue_RequiresProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := &ReviewServiceImpl{reviewDao: mocks.NewMockCommentDao(ctrl), ownership: ownerOf(9, 3)}
+
+	_, err := svc.ListModerationQueue(context.Background(), types.ModerationQueueRequest{}, 9)
+	var fieldErrs validation.Errors
+	assert.ErrorAs(t, err, &fieldErrs)
+	assert.Equal(t, "product_id", fieldErrs[0].Field)
+}
+
+func TestListModerationQueue_OtherMerchant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// merchant 8 does not own product 3, the queue is not read
+	svc := &ReviewServiceImpl{reviewDao: mocks.NewMockCommentDao(ctrl), ownership: ownerOf(9, 3)}
+
+	_, err := svc.ListModerationQueue(context.Background(), types.ModerationQueueRequest{ProductID: 3}, 8)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestModerateReview_Approve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(5, 8)}
+
+	mockDao.EXPECT().Get(gomock.Any(), "m1").Return(&model.Comment{ID: "m1", ProductID: 8, Status: model.StatusPending}, nil)
+	mockDao.EXPECT().UpdateStatusByID(gomock.Any(), "m1", model.StatusApproved, "", 5, gomock.Any()).Return(nil)
+	mockDao.EXPECT().Incr(gomock.Any(), "product:8:rating_version").Return(nil)
+
+	err := svc.ModerateReview(context.Background(), "m1", types.ModerateReviewRequest{Status: model.StatusApproved}, 5)
+	assert.NoError(t, err)
+}
+
+func TestModerateReview_RejectPinnedUnpins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(5, 9)}
+
+	productID := 9
+	req := types.ModerateReviewRequest{Status: model.StatusRejected, Reason: "abusive"}
+
+	mockDao.EXPECT().Get(gomock.Any(), "m2").Return(&model.Comment{ID: "m2", ProductID: productID, IsPinned: true}, nil)
+	mockDao.EXPECT().UpdateStatusByID(gomock.Any(), "m2", model.StatusRejected, "abusive", 5, gomock.Any()).Return(nil)
+	mockDao.EXPECT().Incr(gomock.Any(), "product:9:rating_version").Return(nil)
+	mockDao.EXPECT().UpdateIsPinnedByID(gomock.Any(), "m2", false).Return(nil)
+	mockDao.EXPECT().Del(gomock.Any(), "product:9:pinned_reviews").Return(nil)
+
+	err := svc.ModerateReview(context.Background(), "m2", req, 5)
+	assert.NoError(t, err)
+}
+
+func TestModerateReview_InvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := &ReviewServiceImpl{reviewDao: mocks.NewMockCommentDao(ctrl)}
+
+	err := svc.ModerateReview(context.Background(), "m3", types.ModerateReviewRequest{Status: model.StatusPending}, 5)
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+
+	err = svc.ModerateReview(context.Background(), "m3", types.ModerateReviewRequest{Status: model.StatusHidden}, 5)
+	assert.ErrorIs(t, err, ErrReasonRequired)
+}
+
+func TestGetReview_NotApproved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	mockDao.EXPECT().Get(gomock.Any(), "h1").Return(&model.Comment{ID: "h1", Status: model.StatusHidden}, nil)
+	mockDao.EXPECT().HGet(gomock.Any(), reviewLikesCntKey, "h1").Return("", nil)
+
+	_, err := svc.GetReview(context.Background(), "h1", 0)
+	assert.ErrorIs(t, err, ErrReviewNotFound)
+}
+
+func TestPinReview_NotApproved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 1)}
+
+	mockDao.EXPECT().Get(gomock.Any(), "p1").Return(&model.Comment{ID: "p1", ProductID: 1, Status: model.StatusPending}, nil)
+
+	err := svc.PinReview(context.Background(), "p1", types.PinReviewRequest{IsPinned: true}, 9)
+	assert.ErrorIs(t, err, ErrReviewNotApproved)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+)
+
+const (
+	defaultMaxPinned = 3
+	// pinLockKeyFmt serializes the pin changes of a product across instances
+	pinLockKeyFmt     = "product:%d:pin_lock"
+	pinLockTTL        = 5 * time.Second
+	pinLockRetryEvery = 50 * time.Millisecond
+	// pinnedCacheKeyFmt caches the pinned comments of a product, mongo stays
+	// the source of truth and every pin change drops the entry
+	pinnedCacheKeyFmt = "product:%d:pinned_reviews"
+	pinnedCacheTTL    = 10 * time.Minute
+)
+
+// pinLockWait is how long a pin change waits for another one on the same product.
+var pinLockWait = 2 * time.Second
+
+func maxPinned() int {
+	if config.Config.ReviewConfig != nil && config.Config.ReviewConfig.MaxPinned > 0 {
+		return config.Config.ReviewConfig.MaxPinned
+	}
+	return defaultMaxPinned
+}
+
+// PinReview pins the review at req.Position among its product's pinned
+// reviews, moving it when it is already pinned. Reviews at or after that
+// position move down by one. Only the merchant owning the product may pin.
+func (r *ReviewServiceImpl) PinReview(ctx context.Context, reviewID string, req types.PinReviewRequest, merchantID int) (err error) {
+	commentRaw, err := r.reviewDao.Get(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if commentRaw.IsDeleted() {
+		return ErrReviewNotFound
+	}
+	if err := r.checkProductOwner(ctx, merchantID, commentRaw.ProductID); err != nil {
+		return err
+	}
+	if commentRaw.IsReply() {
+		return ErrCannotPinReply
+	}
+	if commentRaw.ModerationStatus() != model.StatusApproved {
+		return ErrReviewNotApproved
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return ErrInvalidPinExpiry
+	}
+
+	productId := commentRaw.ProductID
+	var position int
+	err = r.withPinLock(ctx, productId, func() error {
+		now := time.Now()
+		if err := r.reviewDao.ClearStalePins(ctx, productId, now); err != nil {
+			return err
+		}
+		pinned, err := r.reviewDao.GetPinnedByProductID(ctx, productId, now)
+		if err != nil {
+			return err
+		}
+		order := make([]*model.Comment, 0, len(pinned)+1)
+		for _, p := range pinned {
+			if p.ID != reviewID {
+				order = append(order, p)
+			}
+		}
+		if len(order) >= maxPinned() {
+			return ErrTooManyPinned
+		}
+		idx := req.Position - 1
+		if idx < 0 || idx > len(order) {
+			idx = len(order)
+		}
+		target := *commentRaw
+		target.PinnedUntil = req.ExpiresAt
+		target.PinPosition = 0 // always written, its expiry may have changed
+		order = append(order[:idx], append([]*model.Comment{&target}, order[idx:]...)...)
+		position = idx + 1
+		return r.savePinOrder(ctx, productId, order)
+	})
+	if err != nil {
+		return err
+	}
+	metrics.Pins.WithLabelValues("pin").Inc()
+	r.invalidatePinnedCache(ctx, productId)
+	log.Ctx(ctx).Infof("PinReview: review %s pinned at %d by %d", reviewID, position, merchantID)
+	return nil
+}
+
+// UnpinReview removes the review from its product's pinned reviews and closes
+// the gap it leaves.
+func (r *ReviewServiceImpl) UnpinReview(ctx context.Context, reviewID string, merchantID int) (err error) {
+	commentRaw, err := r.reviewDao.Get(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if commentRaw.IsDeleted() {
+		return ErrReviewNotFound
+	}
+	if err := r.checkProductOwner(ctx, merchantID, commentRaw.ProductID); err != nil {
+		return err
+	}
+	if !commentRaw.IsPinned {
+		return nil
+	}
+
+	productId := commentRaw.ProductID
+	err = r.withPinLock(ctx, productId, func() error {
+		if err := r.reviewDao.UpdateIsPinnedByID(ctx, reviewID, false); err != nil {
+			return err
+		}
+		pinned, err := r.reviewDao.GetPinnedByProductID(ctx, productId, time.Now())
+		if err != nil {
+			return err
+		}
+		return r.savePinOrder(ctx, productId, pinned)
+	})
+	if err != nil {
+		return err
+	}
+	metrics.Pins.WithLabelValues("unpin").Inc()
+	r.invalidatePinnedCache(ctx, productId)
+	return nil
+}
+
+// clearPin unpins a review that is deleted or no longer approved. Its
+// position is left as a gap, the remaining pins keep their order.
+func (r *ReviewServiceImpl) clearPin(ctx context.Context, commentRaw *model.Comment) error {
+	if err := r.reviewDao.UpdateIsPinnedByID(ctx, commentRaw.ID, false); err != nil {
+		return err
+	}
+	r.invalidatePinnedCache(ctx, commentRaw.ProductID)
+	return nil
+}
+
+// savePinOrder numbers the pinned reviews from 1 in the given order and writes
+// the ones whose position changed. It must run under the product's pin lock.
+func (r *ReviewServiceImpl) savePinOrder(ctx context.Context, productId int, order []*model.Comment) error {
+	changed := make([]*model.Comment, 0, len(order))
+	for idx, p := range order {
+		position := idx + 1
+		if p.PinPosition == position {
+			continue
+		}
+		moved := *p
+		moved.PinPosition = position
+		changed = append(changed, &moved)
+	}
+	return r.reviewDao.SetPinOrder(ctx, productId, changed)
+}
+
+// withPinLock runs fn while holding the product's pin lock, waiting up to
+// pinLockWait for it.
+func (r *ReviewServiceImpl) withPinLock(ctx context.Context, productId int, fn func() error) error {
+	key := fmt.Sprintf(pinLockKeyFmt, productId)
+	token, err := newLockToken()
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(pinLockWait)
+	for {
+		acquired, err := r.reviewDao.AcquireLock(ctx, key, token, pinLockTTL)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			break
+		}
+		if time.Now().After(deadline) {
+			return ErrPinBusy
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pinLockRetryEvery):
+		}
+	}
+	defer func() {
+		if err := r.reviewDao.ReleaseLock(ctx, key, token); err != nil {
+			// it expires after pinLockTTL anyway
+			log.Ctx(ctx).Warnf("withPinLock: release failed, product_id=%d, err %s", productId, err.Error())
+		}
+	}()
+	return fn()
+}
+
+func newLockToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// getPinnedReviews returns the product's active pinned reviews in order.
+func (r *ReviewServiceImpl) getPinnedReviews(ctx context.Context, productId int, userID int) ([]types.ReviewInfo, error) {
+	pinned, err := r.getPinnedComments(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	if len(pinned) == 0 {
+		return []types.ReviewInfo{}, nil
+	}
+	return r.buildReviewInfoList(ctx, pinned, userID)
+}
+
+// getPinnedComments reads the pinned comments of a product through the redis
+// cache. Pins expiring while cached are dropped on read.
+func (r *ReviewServiceImpl) getPinnedComments(ctx context.Context, productId int) ([]*model.Comment, error) {
+	key := fmt.Sprintf(pinnedCacheKeyFmt, productId)
+	now := time.Now()
+	cached, err := r.reviewDao.GetStr(ctx, key)
+	if err != nil {
+		log.Ctx(ctx).Warnf("getPinnedComments: read cache failed, product_id=%d, err %s", productId, err.Error())
+	}
+	if cached != "" {
+		var pinned []*model.Comment
+		if err := json.Unmarshal([]byte(cached), &pinned); err == nil {
+			active := pinned[:0]
+			for _, p := range pinned {
+				if p.IsPinnedAt(now) {
+					active = append(active, p)
+				}
+			}
+			return active, nil
+		}
+		log.Ctx(ctx).Warnf("getPinnedComments: broken cache entry, product_id=%d", productId)
+	}
+
+	pinned, err := r.reviewDao.GetPinnedByProductID(ctx, productId, now)
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := json.Marshal(pinned)
+	if err := r.reviewDao.SetEx(ctx, key, string(raw), pinnedCacheTTL); err != nil {
+		log.Ctx(ctx).Warnf("getPinnedComments: write cache failed, product_id=%d, err %s", productId, err.Error())
+	}
+	return pinned, nil
+}
+
+// invalidatePinnedCache drops the cached pinned comments of a product.
+// Failures are only logged, the entry expires after pinnedCacheTTL.
+func (r *ReviewServiceImpl) invalidatePinnedCache(ctx context.Context, productId int) {
+	key := fmt.Sprintf(pinnedCacheKeyFmt, productId)
+	if err := r.reviewDao.Del(ctx, key); err != nil {
+		log.Ctx(ctx).Warnf("invalidatePinnedCache: failed, product_id=%d, err %s", productId, err.Error())
+	}
+}
@@ -0,0 +1,429 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+)
+
+func TestPinReview_GetFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	mockDao.EXPECT().Get(gomock.Any(), "pgf").Return(nil, assert.AnError)
+
+	err := svc.PinReview(context.Background(), "pgf", types.PinReviewRequest{IsPinned: true}, 9)
+	assert.Error(t, err)
+}
+
+func TestPinReview_Append(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 8)}
+
+	mockDao.EXPECT().Get(gomock.Any(), "p3").Return(&model.Comment{ID: "p3", ProductID: 8}, nil)
+	gomock.InOrder(
+		mockDao.EXPECT().AcquireLock(gomock.Any(), "product:8:pin_lock", gomock.Any(), pinLockTTL).Return(true, nil),
+		mockDao.EXPECT().ClearStalePins(gomock.Any(), 8, gomock.Any()).Return(nil),
+		mockDao.EXPECT().GetPinnedByProductID(gomock.Any(), 8, gomock.Any()).Return([]*model.Comment{
+			{ID: "p1", ProductID: 8, IsPinned: true, PinPosition: 1},
+			{ID: "p2", ProductID: 8, IsPinned: true, PinPosition: 2},
+		}, nil),
+		// the others keep their positions
+		mockDao.EXPECT().SetPinOrder(gomock.Any(), 8, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, productId int, pins []*model.Comment) error {
+				assert.Equal(t, []string{"p3@3"}, pinSlots(pins))
+				return nil
+			}),
+		mockDao.EXPECT().ReleaseLock(gomock.Any(), "product:8:pin_lock", gomock.Any()).Return(nil),
+		mockDao.EXPECT().Del(gomock.Any(), "product:8:pinned_reviews").Return(nil),
+	)
+
+	err := svc.PinReview(context.Background(), "p3", types.PinReviewRequest{IsPinned: true}, 9)
+	assert.NoError(t, err)
+}
+
+func TestPinReview_InsertAtPositionWithExpiry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 8)}
+	expectPinLock(mockDao, 8)
+
+	until := time.Now().Add(time.Hour)
+	p2Until := time.Now().Add(2 * time.Hour)
+	mockDao.EXPECT().Get(gomock.Any(), "p3").Return(&model.Comment{ID: "p3", ProductID: 8}, nil)
+	mockDao.EXPECT().ClearStalePins(gomock.Any(), 8, gomock.Any()).Return(nil)
+	mockDao.EXPECT().GetPinnedByProductID(gomock.Any(), 8, gomock.Any()).Return([]*model.Comment{
+		{ID: "p1", ProductID: 8, IsPinned: true, PinPosition: 1},
+		{ID: "p2", ProductID: 8, IsPinned: true, PinPosition: 2, PinnedUntil: &p2Until},
+	}, nil)
+	mockDao.EXPECT().SetPinOrder(gomock.Any(), 8, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, productId int, pins []*model.Comment) error {
+			assert.Equal(t, []string{"p3@1", "p1@2", "p2@3"}, pinSlots(pins))
+			assert.Equal(t, &until, pins[0].PinnedUntil)
+			// the expiry of moved pins is kept
+			assert.Nil(t, pins[1].PinnedUntil)
+			assert.Equal(t, &p2Until, pins[2].PinnedUntil)
+			return nil
+		})
+	mockDao.EXPECT().Del(gomock.Any(), "product:8:pinned_reviews").Return(nil)
+
+	err := svc.PinReview(context.Background(), "p3", types.PinReviewRequest{IsPinned: true, Position: 1, ExpiresAt: &until}, 9)
+	assert.NoError(t, err)
+}
+
+func TestPinReview_MoveDown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 8)}
+	expectPinLock(mockDao, 8)
+
+	p1 := &model.Comment{ID: "p1", ProductID: 8, IsPinned: true, PinPosition: 1}
+	mockDao.EXPECT().Get(gomock.Any(), "p1").Return(p1, nil)
+	mockDao.EXPECT().ClearStalePins(gomock.Any(), 8, gomock.Any()).Return(nil)
+	mockDao.EXPECT().GetPinnedByProductID(gomock.Any(), 8, gomock.Any()).Return([]*model.Comment{
+		p1,
+		{ID: "p2", ProductID: 8, IsPinned: true, PinPosition: 2},
+	}, nil)
+	mockDao.EXPECT().SetPinOrder(gomock.Any(), 8, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, productId int, pins []*model.Comment) error {
+			assert.Equal(t, []string{"p2@1", "p1@2"}, pinSlots(pins))
+			return nil
+		})
+	mockDao.EXPECT().Del(gomock.Any(), "product:8:pinned_reviews").Return(nil)
+
+	err := svc.PinReview(context.Background(), "p1", types.PinReviewRequest{IsPinned: true, Position: 2}, 9)
+	assert.NoError(t, err)
+	// the document read before taking the lock is left untouched
+	assert.Equal(t, 1, p1.PinPosition)
+}
+
+func TestPinReview_TooMany(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	old := config.Config.ReviewConfig
+	config.Config.ReviewConfig = &config.ReviewConfig{MaxPinned: 1}
+	defer func() { config.Config.ReviewConfig = old }()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 8)}
+	expectPinLock(mockDao, 8)
+
+	mockDao.EXPECT().Get(gomock.Any(), "p2").Return(&model.Comment{ID: "p2", ProductID: 8}, nil)
+	mockDao.EXPECT().ClearStalePins(gomock.Any(), 8, gomock.Any()).Return(nil)
+	mockDao.EXPECT().GetPinnedByProductID(gomock.Any(), 8, gomock.Any()).Return([]*model.Comment{
+		{ID: "p1", ProductID: 8, IsPinned: true, PinPosition: 1},
+	}, nil)
+
+	err := svc.PinReview(context.Background(), "p2", types.PinReviewRequest{IsPinned: true}, 9)
+	assert.ErrorIs(t, err, ErrTooManyPinned)
+}
+
+func TestPinReview_InvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 8)}
+
+	past := time.Now().Add(-time.Minute)
+	mockDao.EXPECT().Get(gomock.Any(), "p1").Return(&model.Comment{ID: "p1", ProductID: 8}, nil)
+	err := svc.PinReview(context.Background(), "p1", types.PinReviewRequest{IsPinned: true, ExpiresAt: &past}, 9)
+	assert.ErrorIs(t, err, ErrInvalidPinExpiry)
+
+	mockDao.EXPECT().Get(gomock.Any(), "r1").Return(&model.Comment{ID: "r1", ProductID: 8, ParentID: "p1"}, nil)
+	err = svc.PinReview(context.Background(), "r1", types.PinReviewRequest{IsPinned: true}, 9)
+	assert.ErrorIs(t, err, ErrCannotPinReply)
+}
+
+func TestPinReview_LockBusy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oldWait := pinLockWait
+	pinLockWait = 0
+	defer func() { pinLockWait = oldWait }()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 8)}
+
+	mockDao.EXPECT().Get(gomock.Any(), "p1").Return(&model.Comment{ID: "p1", ProductID: 8}, nil)
+	mockDao.EXPECT().AcquireLock(gomock.Any(), "product:8:pin_lock", gomock.Any(), pinLockTTL).Return(false, nil).MinTimes(1)
+
+	err := svc.PinReview(context.Background(), "p1", types.PinReviewRequest{IsPinned: true}, 9)
+	assert.ErrorIs(t, err, ErrPinBusy)
+}
+
+func TestUnpinReview_ClosesGap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 8)}
+	expectPinLock(mockDao, 8)
+
+	mockDao.EXPECT().Get(gomock.Any(), "p1").Return(&model.Comment{ID: "p1", ProductID: 8, IsPinned: true, PinPosition: 1}, nil)
+	mockDao.EXPECT().UpdateIsPinnedByID(gomock.Any(), "p1", false).Return(nil)
+	mockDao.EXPECT().GetPinnedByProductID(gomock.Any(), 8, gomock.Any()).Return([]*model.Comment{
+		{ID: "p2", ProductID: 8, IsPinned: true, PinPosition: 2},
+	}, nil)
+	mockDao.EXPECT().SetPinOrder(gomock.Any(), 8, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, productId int, pins []*model.Comment) error {
+			assert.Equal(t, []string{"p2@1"}, pinSlots(pins))
+			return nil
+		})
+	mockDao.EXPECT().Del(gomock.Any(), "product:8:pinned_reviews").Return(nil)
+
+	err := svc.UnpinReview(context.Background(), "p1", 9)
+	assert.NoError(t, err)
+}
+
+func TestUnpinReview_NotPinned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 8)}
+
+	mockDao.EXPECT().Get(gomock.Any(), "p1").Return(&model.Comment{ID: "p1", ProductID: 8}, nil)
+
+	err := svc.UnpinReview(context.Background(), "p1", 9)
+	assert.NoError(t, err)
+}
+
+func TestUnpinReview_NotProductOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 8)}
+
+	mockDao.EXPECT().Get(gomock.Any(), "p1").Return(&model.Comment{ID: "p1", ProductID: 8, IsPinned: true}, nil)
+
+	err := svc.UnpinReview(context.Background(), "p1", 10)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestGetPinnedReviews_CacheHitDropsExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	expired := time.Now().Add(-time.Minute)
+	cached := fmt.Sprintf(`[{"id":"p1","product_id":8,"is_pinned":true,"pin_position":1,"pinned_until":%q},`+
+		`{"id":"p2","product_id":8,"is_pinned":true,"pin_position":2}]`, expired.Format(time.RFC3339Nano))
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:8:pinned_reviews").Return(cached, nil)
+	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, []string{"p2"}).Return(map[string]int{}, nil)
+
+	pinned, err := svc.getPinnedReviews(context.Background(), 8, 0)
+	assert.NoError(t, err)
+	assert.Len(t, pinned, 1)
+	assert.Equal(t, "p2", pinned[0].ID)
+}
+
+// TestPinReview_ConcurrentKeepsPositionsUnique pins and unpins reviews of one
+// product from many goroutines against a DAO enforcing the unique pin
+// position index, then checks the pins are numbered 1..n without gaps.
+func TestPinReview_ConcurrentKeepsPositionsUnique(t *testing.T) {
+	old := config.Config.ReviewConfig
+	config.Config.ReviewConfig = &config.ReviewConfig{MaxPinned: 3}
+	defer func() { config.Config.ReviewConfig = old }()
+
+	fake := newFakePinDao(8, 12)
+	svc := &ReviewServiceImpl{reviewDao: fake, ownership: ownerOf(9, 8)}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 36)
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 12; i++ {
+			wg.Add(1)
+			go func(round, i int) {
+				defer wg.Done()
+				id := fmt.Sprintf("c%d", i)
+				var err error
+				if round == 1 && i%3 == 0 {
+					err = svc.UnpinReview(context.Background(), id, 9)
+				} else {
+					err = svc.PinReview(context.Background(), id, types.PinReviewRequest{IsPinned: true, Position: 1 + i%3}, 9)
+				}
+				if err != nil && err != ErrTooManyPinned {
+					errs <- err
+				}
+			}(round, i)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	assert.Zero(t, fake.conflicts, "unique pin position index violated")
+	positions := fake.positions()
+	assert.LessOrEqual(t, len(positions), 3)
+	for idx, position := range positions {
+		assert.Equal(t, idx+1, position)
+	}
+}
+
+func expectPinLock(mockDao *mocks.MockCommentDao, productId int) {
+	key := fmt.Sprintf(pinLockKeyFmt, productId)
+	mockDao.EXPECT().AcquireLock(gomock.Any(), key, gomock.Any(), pinLockTTL).Return(true, nil)
+	mockDao.EXPECT().ReleaseLock(gomock.Any(), key, gomock.Any()).Return(nil)
+}
+
+func pinSlots(pins []*model.Comment) []string {
+	slots := make([]string, len(pins))
+	for idx, p := range pins {
+		slots[idx] = fmt.Sprintf("%s@%d", p.ID, p.PinPosition)
+	}
+	return slots
+}
+
+// fakePinDao keeps the comments of one product in memory. Each write takes
+// the mutex on its own, like separate mongo operations, so only the pin lock
+// keeps concurrent pin changes apart. Methods not used by pinning panic.
+type fakePinDao struct {
+	dao.CommentDao
+
+	mu        sync.Mutex
+	comments  map[string]*model.Comment
+	locks     map[string]string
+	conflicts int
+}
+
+func newFakePinDao(productId int, n int) *fakePinDao {
+	f := &fakePinDao{comments: map[string]*model.Comment{}, locks: map[string]string{}}
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("c%d", i)
+		f.comments[id] = &model.Comment{ID: id, ProductID: productId, Status: model.StatusApproved}
+	}
+	return f
+}
+
+func (f *fakePinDao) Get(ctx context.Context, id string) (*model.Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.comments[id]
+	if !ok {
+		return nil, dao.ErrNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (f *fakePinDao) GetPinnedByProductID(ctx context.Context, productId int, now time.Time) ([]*model.Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var list []*model.Comment
+	for _, c := range f.comments {
+		if c.ProductID == productId && c.IsPinnedAt(now) {
+			cp := *c
+			list = append(list, &cp)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].PinPosition < list[j].PinPosition })
+	return list, nil
+}
+
+func (f *fakePinDao) ClearStalePins(ctx context.Context, productId int, now time.Time) error {
+	return nil
+}
+
+func (f *fakePinDao) UpdateIsPinnedByID(ctx context.Context, id string, isPinned bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := f.comments[id]
+	c.IsPinned = isPinned
+	if !isPinned {
+		c.PinPosition = 0
+		c.PinnedUntil = nil
+	}
+	return nil
+}
+
+func (f *fakePinDao) SetPinOrder(ctx context.Context, productId int, pins []*model.Comment) error {
+	f.mu.Lock()
+	for _, p := range pins {
+		f.comments[p.ID].PinPosition = 0
+	}
+	f.mu.Unlock()
+	for _, p := range pins {
+		time.Sleep(time.Millisecond) // leave room for interleaving writers
+		f.mu.Lock()
+		for id, c := range f.comments {
+			if id != p.ID && c.IsPinned && c.PinPosition == p.PinPosition {
+				f.conflicts++
+				f.mu.Unlock()
+				return dao.ErrPinConflict
+			}
+		}
+		c := f.comments[p.ID]
+		c.IsPinned = true
+		c.PinPosition = p.PinPosition
+		c.PinnedUntil = p.PinnedUntil
+		f.mu.Unlock()
+	}
+	return nil
+}
+
+func (f *fakePinDao) AcquireLock(ctx context.Context, key string, token string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, held := f.locks[key]; held {
+		return false, nil
+	}
+	f.locks[key] = token
+	return true, nil
+}
+
+func (f *fakePinDao) ReleaseLock(ctx context.Context, key string, token string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.locks[key] == token {
+		delete(f.locks, key)
+	}
+	return nil
+}
+
+func (f *fakePinDao) Del(ctx context.Context, key string) error {
+	return nil
+}
+
+// positions returns the pin positions of the pinned comments in order.
+func (f *fakePinDao) positions() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var positions []int
+	for _, c := range f.comments {
+		if c.IsPinned {
+			positions = append(positions, c.PinPosition)
+		}
+	}
+	sort.Ints(positions)
+	return positions
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+)
+
+const (
+	// ratingSummaryKeyFmt caches the summary of a product at a version of its
+	// reviews, ratingVersionKeyFmt holds the current version
+	ratingSummaryKeyFmt = "product:%d:rating_summary:v%s"
+	ratingVersionKeyFmt = "product:%d:rating_version"
+	ratingSummaryTTL    = 10 * time.Minute
+)
+
+// GetRatingSummary returns the rating summary of a product, served from the
+// redis cache when possible. The summary is cached under the version of the
+// product's reviews read before it is computed. Writes move to the next
+// version once they commit, so a summary computed before a write is never
+// served after it.
+func (r *ReviewServiceImpl) GetRatingSummary(ctx context.Context, productId int) (summary types.RatingSummary, err error) {
+	version, err := r.reviewDao.GetStr(ctx, fmt.Sprintf(ratingVersionKeyFmt, productId))
+	// without the version the cache is skipped, mongo is the source of truth
+	cacheable := err == nil
+	if !cacheable {
+		log.Ctx(ctx).Warnf("GetRatingSummary: read cache version failed, product_id=%d, err %s", productId, err.Error())
+	}
+	if version == "" {
+		version = "0"
+	}
+	key := fmt.Sprintf(ratingSummaryKeyFmt, productId, version)
+
+	if cacheable {
+		cached, err := r.reviewDao.GetStr(ctx, key)
+		if err != nil {
+			log.Ctx(ctx).Warnf("GetRatingSummary: read cache failed, product_id=%d, err %s", productId, err.Error())
+		}
+		if cached != "" {
+			if err := json.Unmarshal([]byte(cached), &summary); err == nil {
+				return summary, nil
+			}
+			log.Ctx(ctx).Warnf("GetRatingSummary: broken cache entry, product_id=%d", productId)
+		}
+	}
+
+	stats, err := r.reviewDao.AggregateRatingByProductID(ctx, productId)
+	if err != nil {
+		return types.RatingSummary{}, err
+	}
+	summary = buildRatingSummary(productId, stats)
+
+	if cacheable {
+		raw, _ := json.Marshal(summary)
+		if err := r.reviewDao.SetEx(ctx, key, string(raw), ratingSummaryTTL); err != nil {
+			log.Ctx(ctx).Warnf("GetRatingSummary: write cache failed, product_id=%d, err %s", productId, err.Error())
+		}
+	}
+	return summary, nil
+}
+
+// BatchGetRatingSummary returns the rating summaries of several products,
+// keyed by product id.
+func (r *ReviewServiceImpl) BatchGetRatingSummary(ctx context.Context, productIds []int) (summaries map[int]types.RatingSummary, err error) {
+	summaries = make(map[int]types.RatingSummary, len(productIds))
+	for _, productId := range productIds {
+		if _, ok := summaries[productId]; ok {
+			continue
+		}
+		summary, err := r.GetRatingSummary(ctx, productId)
+		if err != nil {
+			return nil, err
+		}
+		summaries[productId] = summary
+	}
+	return summaries, nil
+}
+
+func buildRatingSummary(productId int, stats *model.RatingStats) types.RatingSummary {
+	summary := types.RatingSummary{
+		ProductID:  productId,
+		TotalCount: stats.TotalCount,
+		StarHistogram: map[int]int{
+			1: stats.Star1,
+			2: stats.Star2,
+			3: stats.Star3,
+			4: stats.Star4,
+			5: stats.Star5,
+		},
+		PhotoReviewCount: stats.PhotoCount,
+		ReplyCount:       stats.ReplyCount,
+	}
+	if stats.TotalCount > 0 {
+		avg := float64(stats.StarSum) / float64(stats.TotalCount)
+		summary.AverageStars = math.Round(avg*100) / 100
+	}
+	return summary
+}
+
+// invalidateRatingSummary moves the product to the next version of its
+// reviews, the summaries cached before are no longer read. It is called once
+// the write has committed. Failures are only logged: the entry expires on its
+// own after ratingSummaryTTL.
+func (r *ReviewServiceImpl) invalidateRatingSummary(ctx context.Context, productId int) {
+	key := fmt.Sprintf(ratingVersionKeyFmt, productId)
+	if err := r.reviewDao.Incr(ctx, key); err != nil {
+		log.Ctx(ctx).Warnf("invalidateRatingSummary: failed, product_id=%d, err %s", productId, err.Error())
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+)
+
+// ReconcileLikes compares the like counters and like sets in redis and the
+// like counts of the reviews with the likes recorded in mongo and reports
+// every review that drifted. With fix set they are rewritten to match the
+// recorded likes. Reviews are found through the recorded likes, the like
+// counts and the review_likes counter, so an entry left only in a user's like
+// set is not detected.
+func (r *ReviewServiceImpl) ReconcileLikes(ctx context.Context, fix bool) (report types.LikeReconcileReport, err error) {
+	likers := make(map[string]map[int]bool)
+	err = r.likeDao.ForEach(ctx, func(like *model.Like) error {
+		if likers[like.ReviewID] == nil {
+			likers[like.ReviewID] = make(map[int]bool)
+		}
+		likers[like.ReviewID][like.UserID] = true
+		report.Likes++
+		return nil
+	})
+	if err != nil {
+		return types.LikeReconcileReport{}, err
+	}
+	cachedCounts, err := r.reviewDao.HGetAll(ctx, reviewLikesCntKey)
+	if err != nil {
+		return types.LikeReconcileReport{}, err
+	}
+	storedCounts, err := r.reviewDao.GetLikeCounts(ctx)
+	if err != nil {
+		return types.LikeReconcileReport{}, err
+	}
+
+	seen := make(map[string]bool, len(likers)+len(cachedCounts))
+	reviewIDs := make([]string, 0, len(likers)+len(cachedCounts))
+	addReview := func(reviewID string) {
+		if !seen[reviewID] {
+			seen[reviewID] = true
+			reviewIDs = append(reviewIDs, reviewID)
+		}
+	}
+	for reviewID := range likers {
+		addReview(reviewID)
+	}
+	for reviewID := range cachedCounts {
+		addReview(reviewID)
+	}
+	for reviewID := range storedCounts {
+		addReview(reviewID)
+	}
+	sort.Strings(reviewIDs)
+	report.Reviews = len(reviewIDs)
+	report.Drifts = []types.LikeDrift{}
+
+	for _, reviewID := range reviewIDs {
+		drift, err := r.likeDrift(ctx, reviewID, likers[reviewID], cachedCounts[reviewID], storedCounts[reviewID])
+		if err != nil {
+			return types.LikeReconcileReport{}, err
+		}
+		if drift == nil {
+			continue
+		}
+		report.Drifts = append(report.Drifts, *drift)
+		if fix {
+			if err := r.fixLikeDrift(ctx, *drift); err != nil {
+				return types.LikeReconcileReport{}, err
+			}
+		}
+	}
+	report.Fixed = fix && len(report.Drifts) > 0
+	return report, nil
+}
+
+// likeDrift compares the cached like data and the like count of one review
+// with its likers recorded in mongo, it returns nil when they agree.
+func (r *ReviewServiceImpl) likeDrift(ctx context.Context, reviewID string, likers map[int]bool, cachedCount int, storedCount int) (*types.LikeDrift, error) {
+	members, err := r.reviewDao.SMembers(ctx, fmt.Sprintf(reviewLikersKeyFmt, reviewID))
+	if err != nil {
+		return nil, err
+	}
+	cached := make(map[int]bool, len(members))
+	for _, member := range members {
+		userID, err := strconv.Atoi(member)
+		if err != nil {
+			log.Ctx(ctx).Warnf("ReconcileLikes: bad liker %q of review %s", member, reviewID)
+			continue
+		}
+		cached[userID] = true
+	}
+
+	drift := types.LikeDrift{ReviewID: reviewID, Count: len(likers), CachedCount: cachedCount, StoredCount: storedCount}
+	for userID := range likers {
+		if !cached[userID] {
+			drift.MissingLikers = append(drift.MissingLikers, userID)
+		}
+	}
+	for userID := range cached {
+		if !likers[userID] {
+			drift.ExtraLikers = append(drift.ExtraLikers, userID)
+		}
+	}
+	if drift.Count == drift.CachedCount && drift.Count == drift.StoredCount &&
+		len(drift.MissingLikers) == 0 && len(drift.ExtraLikers) == 0 {
+		return nil, nil
+	}
+	sort.Ints(drift.MissingLikers)
+	sort.Ints(drift.ExtraLikers)
+	return &drift, nil
+}
+
+// fixLikeDrift rewrites the like sets and the counter of the review in redis
+// and its like count from the likes recorded in mongo.
+func (r *ReviewServiceImpl) fixLikeDrift(ctx context.Context, drift types.LikeDrift) error {
+	if drift.Count != drift.StoredCount {
+		if err := r.reviewDao.SetLikeCount(ctx, drift.ReviewID, drift.Count); err != nil {
+			return err
+		}
+	}
+	reviewLikersKey := fmt.Sprintf(reviewLikersKeyFmt, drift.ReviewID)
+	for _, userID := range drift.MissingLikers {
+		if err := r.reviewDao.SAdd(ctx, fmt.Sprintf("user:%d:likes", userID), drift.ReviewID); err != nil {
+			return err
+		}
+		if err := r.reviewDao.SAdd(ctx, reviewLikersKey, strconv.Itoa(userID)); err != nil {
+			return err
+		}
+	}
+	for _, userID := range drift.ExtraLikers {
+		if err := r.reviewDao.SRem(ctx, fmt.Sprintf("user:%d:likes", userID), drift.ReviewID); err != nil {
+			return err
+		}
+		if err := r.reviewDao.SRem(ctx, reviewLikersKey, strconv.Itoa(userID)); err != nil {
+			return err
+		}
+	}
+	if drift.Count == drift.CachedCount {
+		return nil
+	}
+	if drift.Count == 0 {
+		return r.reviewDao.HDel(ctx, reviewLikesCntKey, drift.ReviewID)
+	}
+	return r.reviewDao.HSet(ctx, reviewLikesCntKey, drift.ReviewID, strconv.Itoa(drift.Count))
+}
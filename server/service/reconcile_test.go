@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+)
+
+// forEachLike makes the mocked ForEach yield the given likes.
+func forEachLike(likes ...model.Like) func(ctx context.Context, fn func(like *model.Like) error) error {
+	return func(ctx context.Context, fn func(like *model.Like) error) error {
+		for idx := range likes {
+			if err := fn(&likes[idx]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestReconcileLikes_ReportOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+	mockLikeDao.EXPECT().ForEach(gomock.Any(), gomock.Any()).DoAndReturn(forEachLike(
+		model.Like{ReviewID: "a", UserID: 1},
+		model.Like{ReviewID: "a", UserID: 2},
+		model.Like{ReviewID: "b", UserID: 1},
+	))
+	// "b" was lost in a flush, "c" only exists in redis
+	mockDao.EXPECT().HGetAll(gomock.Any(), reviewLikesCntKey).Return(map[string]int{"a": 2, "c": 1}, nil)
+	mockDao.EXPECT().GetLikeCounts(gomock.Any()).Return(map[string]int{"a": 2, "b": 1}, nil)
+	mockDao.EXPECT().SMembers(gomock.Any(), "review:a:likers").Return([]string{"1", "2"}, nil)
+	mockDao.EXPECT().SMembers(gomock.Any(), "review:b:likers").Return(nil, nil)
+	mockDao.EXPECT().SMembers(gomock.Any(), "review:c:likers").Return([]string{"3"}, nil)
+
+	report, err := svc.ReconcileLikes(context.Background(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Reviews)
+	assert.Equal(t, 3, report.Likes)
+	assert.False(t, report.Fixed)
+	assert.Len(t, report.Drifts, 2)
+	assert.Equal(t, "b", report.Drifts[0].ReviewID)
+	assert.Equal(t, 1, report.Drifts[0].Count)
+	assert.Equal(t, 0, report.Drifts[0].CachedCount)
+	assert.Equal(t, 1, report.Drifts[0].StoredCount)
+	assert.Equal(t, []int{1}, report.Drifts[0].MissingLikers)
+	assert.Equal(t, "c", report.Drifts[1].ReviewID)
+	assert.Equal(t, []int{3}, report.Drifts[1].ExtraLikers)
+}
+
+func TestReconcileLikes_Fix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+	mockLikeDao.EXPECT().ForEach(gomock.Any(), gomock.Any()).DoAndReturn(forEachLike(
+		model.Like{ReviewID: "b", UserID: 1},
+	))
+	mockDao.EXPECT().HGetAll(gomock.Any(), reviewLikesCntKey).Return(map[string]int{"c": 1}, nil)
+	mockDao.EXPECT().GetLikeCounts(gomock.Any()).Return(map[string]int{"c": 1}, nil)
+	mockDao.EXPECT().SMembers(gomock.Any(), "review:b:likers").Return(nil, nil)
+	mockDao.EXPECT().SMembers(gomock.Any(), "review:c:likers").Return([]string{"3"}, nil)
+
+	// "b" is rebuilt from mongo
+	mockDao.EXPECT().SetLikeCount(gomock.Any(), "b", 1).Return(nil)
+	mockDao.EXPECT().SAdd(gomock.Any(), "user:1:likes", "b").Return(nil)
+	mockDao.EXPECT().SAdd(gomock.Any(), "review:b:likers", "1").Return(nil)
+	mockDao.EXPECT().HSet(gomock.Any(), reviewLikesCntKey, "b", "1").Return(nil)
+	// "c" is dropped from redis and its like count reset
+	mockDao.EXPECT().SetLikeCount(gomock.Any(), "c", 0).Return(nil)
+	mockDao.EXPECT().SRem(gomock.Any(), "user:3:likes", "c").Return(nil)
+	mockDao.EXPECT().SRem(gomock.Any(), "review:c:likers", "3").Return(nil)
+	mockDao.EXPECT().HDel(gomock.Any(), reviewLikesCntKey, "c").Return(nil)
+
+	report, err := svc.ReconcileLikes(context.Background(), true)
+	assert.NoError(t, err)
+	assert.Len(t, report.Drifts, 2)
+	assert.True(t, report.Fixed)
+}
+
+func TestReconcileLikes_NoDrift(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+	mockLikeDao.EXPECT().ForEach(gomock.Any(), gomock.Any()).DoAndReturn(forEachLike(
+		model.Like{ReviewID: "a", UserID: 1},
+	))
+	mockDao.EXPECT().HGetAll(gomock.Any(), reviewLikesCntKey).Return(map[string]int{"a": 1}, nil)
+	mockDao.EXPECT().GetLikeCounts(gomock.Any()).Return(map[string]int{"a": 1}, nil)
+	mockDao.EXPECT().SMembers(gomock.Any(), "review:a:likers").Return([]string{"1"}, nil)
+
+	report, err := svc.ReconcileLikes(context.Background(), true)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Drifts)
+	assert.False(t, report.Fixed)
+}
+
+func TestReconcileLikes_StoredCountOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+	mockLikeDao.EXPECT().ForEach(gomock.Any(), gomock.Any()).DoAndReturn(forEachLike(
+		model.Like{ReviewID: "a", UserID: 1},
+		model.Like{ReviewID: "a", UserID: 2},
+	))
+	// redis agrees with mongo, only the like count missed an increment
+	mockDao.EXPECT().HGetAll(gomock.Any(), reviewLikesCntKey).Return(map[string]int{"a": 2}, nil)
+	mockDao.EXPECT().GetLikeCounts(gomock.Any()).Return(map[string]int{"a": 1}, nil)
+	mockDao.EXPECT().SMembers(gomock.Any(), "review:a:likers").Return([]string{"1", "2"}, nil)
+	mockDao.EXPECT().SetLikeCount(gomock.Any(), "a", 2).Return(nil)
+
+	report, err := svc.ReconcileLikes(context.Background(), true)
+	assert.NoError(t, err)
+	assert.Len(t, report.Drifts, 1)
+	assert.Equal(t, 1, report.Drifts[0].StoredCount)
+	assert.True(t, report.Fixed)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+)
+
+const defaultReplyDepth = 2
+
+func replyDepth() int {
+	if config.Config.ReviewConfig != nil && config.Config.ReviewConfig.ReplyDepth > 0 {
+		return config.Config.ReviewConfig.ReplyDepth
+	}
+	return defaultReplyDepth
+}
+
+// buildReviewTree builds the list entries for top level reviews and nests their
+// replies up to replyDepth levels. Reviews and replies are enriched together so
+// like counts are read from redis once per list. reply_count is the number of
+// direct replies, including the ones below the depth limit that are not loaded.
+func (r *ReviewServiceImpl) buildReviewTree(ctx context.Context, roots []*model.Comment, userID int) (list []types.ReviewInfo, err error) {
+	all := append([]*model.Comment{}, roots...)
+	children := make(map[string][]*model.Comment)
+	expanded := make(map[string]bool)
+
+	level := roots
+	for depth := 0; depth < replyDepth() && len(level) > 0; depth++ {
+		parentIDs := commentIDs(level)
+		replies, err := r.reviewDao.GetListByParentIDs(ctx, parentIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range parentIDs {
+			expanded[id] = true
+		}
+		for _, reply := range replies {
+			children[reply.ParentID] = append(children[reply.ParentID], reply)
+		}
+		all = append(all, replies...)
+		level = replies
+	}
+
+	// the last loaded level only reports how many replies it has
+	truncatedCounts := map[string]int{}
+	if len(level) > 0 && !expanded[level[0].ID] {
+		truncatedCounts, err = r.reviewDao.CountByParentIDs(ctx, commentIDs(level))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	infos, err := r.buildReviewInfoList(ctx, all, userID)
+	if err != nil {
+		return nil, err
+	}
+	infoByID := make(map[string]types.ReviewInfo, len(infos))
+	for _, info := range infos {
+		infoByID[info.ID] = info
+	}
+
+	var assemble func(cm *model.Comment) types.ReviewInfo
+	assemble = func(cm *model.Comment) types.ReviewInfo {
+		info := infoByID[cm.ID]
+		if !expanded[cm.ID] {
+			info.ReplyCount = truncatedCounts[cm.ID]
+			return info
+		}
+		kids := children[cm.ID]
+		info.ReplyCount = len(kids)
+		for _, kid := range kids {
+			info.Replies = append(info.Replies, assemble(kid))
+		}
+		return info
+	}
+
+	list = make([]types.ReviewInfo, len(roots))
+	for idx, root := range roots {
+		list[idx] = assemble(root)
+	}
+	return list, nil
+}
+
+func commentIDs(list []*model.Comment) []string {
+	ids := make([]string, len(list))
+	for idx, cm := range list {
+		ids[idx] = cm.ID
+	}
+	return ids
+}
@@ -2,57 +2,105 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
 
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/authz"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/metrics"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/validation"
 )
 
 type ReviewService interface {
 	CreateReview(ctx context.Context, req types.CreateReviewRequest, userID int) (err error)
 	Like(ctx context.Context, req types.LikeRequest, userID int) (err error)
-	GetListByUserID(ctx context.Context, userID int) (list []types.ReviewInfo, err error)
-	GetListByProductID(ctx context.Context, productId int, userID int) (resp types.ListReviewResponse, err error)
-	PinReview(ctx context.Context, reviewID string) (err error)
-	DeleteReview(ctx context.Context, reviewID string) (err error)
-	GetListByQuery(ctx context.Context, req types.ListReviewRequest, userID int) (resp []types.ReviewInfo, err error)
+	Unlike(ctx context.Context, req types.LikeRequest, userID int) (err error)
+	MarkUnhelpful(ctx context.Context, reviewID string, userID int) (err error)
+	UnmarkUnhelpful(ctx context.Context, reviewID string, userID int) (err error)
+	GetListByUserID(ctx context.Context, req types.ListReviewRequest, userID int) (resp types.ListReviewResponse, err error)
+	GetListByProductID(ctx context.Context, req types.ListReviewRequest, userID int) (resp types.ListReviewResponse, err error)
+	PinReview(ctx context.Context, reviewID string, req types.PinReviewRequest, merchantID int) (err error)
+	UnpinReview(ctx context.Context, reviewID string, merchantID int) (err error)
+	DeleteReview(ctx context.Context, reviewID string, merchantID int) (err error)
+	DeleteOwnReview(ctx context.Context, reviewID string, userID int) (err error)
+	ReplyReview(ctx context.Context, req types.CreateReviewRequest, merchantID int) (err error)
+	GetListByQuery(ctx context.Context, req types.ListReviewRequest, userID int) (resp types.ListReviewResponse, err error)
+	SearchProductReviews(ctx context.Context, productID int, req types.SearchReviewRequest, userID int) (resp types.ListReviewResponse, err error)
+	SearchMerchantReviews(ctx context.Context, req types.SearchReviewRequest, merchantID int) (resp types.ListReviewResponse, err error)
+	GetRatingSummary(ctx context.Context, productId int) (summary types.RatingSummary, err error)
+	BatchGetRatingSummary(ctx context.Context, productIds []int) (summaries map[int]types.RatingSummary, err error)
+	GetReview(ctx context.Context, reviewID string, userID int) (detail types.ReviewInfo, err error)
+	ListModerationQueue(ctx context.Context, req types.ModerationQueueRequest, merchantID int) (resp types.ListReviewResponse, err error)
+	ModerateReview(ctx context.Context, reviewID string, req types.ModerateReviewRequest, moderatorID int) (err error)
+	ListTrash(ctx context.Context, req types.ListReviewRequest, merchantID int) (resp types.ListReviewResponse, err error)
+	RestoreReview(ctx context.Context, reviewID string, merchantID int) (err error)
+	PurgeDeleted(ctx context.Context, before time.Time) (purged int, err error)
+	EditReview(ctx context.Context, reviewID string, req types.EditReviewRequest, userID int) (detail types.ReviewInfo, err error)
+	GetRevisions(ctx context.Context, reviewID string, merchantID int) (resp types.ReviewRevisionsResponse, err error)
+	ReconcileLikes(ctx context.Context, fix bool) (report types.LikeReconcileReport, err error)
 }
 
 const (
 	reviewLikesCntKey = "review_likes"
-	pinnedReviewKey   = "pinned_reviews"
+	// reviewLikersKeyFmt is the set of users who liked a review, the reverse
+	// of user:%d:likes, so the like sets can be cleaned when it is purged
+	reviewLikersKeyFmt = "review:%s:likers"
 )
 
 type ReviewServiceImpl struct {
 	reviewDao dao.CommentDao
+	likeDao   dao.LikeDao
+	ownership authz.ProductOwnershipResolver
 }
 
 func GetReviewServiceInstance() *ReviewServiceImpl {
 	return &ReviewServiceImpl{
 		reviewDao: dao.GetCommentDao(),
+		likeDao:   dao.GetLikeDao(),
+		ownership: authz.GetOwnershipResolver(),
 	}
 }
 
-func (r *ReviewServiceImpl) GetListByUserID(ctx context.Context, userID int) (list []types.ReviewInfo, err error) {
-	listRaw, err := r.reviewDao.GetListByUserID(ctx, userID)
+// GetListByUserID returns the reviews written by the user in every moderation
+// status, so authors can follow reviews that are still pending.
+func (r *ReviewServiceImpl) GetListByUserID(ctx context.Context, req types.ListReviewRequest, userID int) (resp types.ListReviewResponse, err error) {
+	listRaw, nextCursor, err := r.reviewDao.GetListByUserID(ctx, userID, pageOf(req))
 	if err != nil {
-		return nil, err
+		return types.ListReviewResponse{}, err
 	}
 
-	return r.buildReviewInfoList(ctx, listRaw, userID)
+	list, err := r.buildReviewInfoList(ctx, listRaw, userID)
+	if err != nil {
+		return types.ListReviewResponse{}, err
+	}
+
+	return types.ListReviewResponse{
+		ReviewList: list,
+		NextCursor: nextCursor,
+	}, nil
 }
 
-func ExistInSlice(lists []string, tar string) bool {
-	for _, i := range lists {
-		if i == tar {
-			return true
-		}
+func pageOf(req types.ListReviewRequest) dao.Page {
+	return dao.Page{
+		Cursor: req.Cursor,
+		Limit:  req.Limit,
+		Sort:   req.Sort,
+	}
+}
+
+// likedBy reports which of the reviews the user liked. Anonymous callers
+// (userID 0) have no likes and redis is not asked.
+func (r *ReviewServiceImpl) likedBy(ctx context.Context, userID int, reviewIDs []string) (map[string]bool, error) {
+	if userID == 0 || len(reviewIDs) == 0 {
+		return map[string]bool{}, nil
 	}
-	return false
+	userLikesReviewSetKey := fmt.Sprintf("user:%d:likes", userID)
+	return r.reviewDao.SMIsMember(ctx, userLikesReviewSetKey, reviewIDs)
 }
 
 func (r *ReviewServiceImpl) getReviewDetail(ctx context.Context, reviewID string, userID int) (detail types.ReviewInfo, err error) {
@@ -60,6 +108,9 @@ func (r *ReviewServiceImpl) getReviewDetail(ctx context.Context, reviewID string
 	if err != nil {
 		return types.ReviewInfo{}, err
 	}
+	if reviewInfoRaw.IsDeleted() {
+		return types.ReviewInfo{}, ErrReviewNotFound
+	}
 
 	likesCntStr, err := r.reviewDao.HGet(ctx, reviewLikesCntKey, reviewID)
 	if err != nil {
@@ -68,13 +119,11 @@ func (r *ReviewServiceImpl) getReviewDetail(ctx context.Context, reviewID string
 
 	likesCnt, _ := strconv.Atoi(likesCntStr)
 
-	userLikesReviewSetKey := fmt.Sprintf("user:%d:likes", userID)
-	likedReviewList, err := r.reviewDao.SMembers(ctx, userLikesReviewSetKey)
+	liked, err := r.likedBy(ctx, userID, []string{reviewID})
 	if err != nil {
 		return types.ReviewInfo{}, err
 	}
-
-	curUserLiked := ExistInSlice(likedReviewList, reviewID)
+	curUserLiked := liked[reviewID]
 
 	return types.ReviewInfo{
 		ID:               reviewInfoRaw.ID,
@@ -88,9 +137,31 @@ func (r *ReviewServiceImpl) getReviewDetail(ctx context.Context, reviewID string
 		CreatedAt:        reviewInfoRaw.CreatedAt,
 		Likes:            likesCnt,
 		CurrentUserLiked: curUserLiked,
+		UnhelpfulCount:   reviewInfoRaw.UnhelpfulCount,
+		HelpfulScore:     reviewInfoRaw.HelpfulScore,
+		IsMerchantReply:  reviewInfoRaw.IsMerchantReply,
+		IsPinned:         reviewInfoRaw.IsPinnedAt(time.Now()),
+		PinPosition:      reviewInfoRaw.PinPosition,
+		PinnedUntil:      reviewInfoRaw.PinnedUntil,
+		Status:           reviewInfoRaw.ModerationStatus(),
+		ModerationReason: reviewInfoRaw.ModerationReason,
+		EditedAt:         reviewInfoRaw.EditedAt,
 	}, nil
 }
 
+// GetReview returns a single approved review with its like count. userID is
+// the caller and only decides current_user_liked, 0 means anonymous.
+func (r *ReviewServiceImpl) GetReview(ctx context.Context, reviewID string, userID int) (detail types.ReviewInfo, err error) {
+	detail, err = r.getReviewDetail(ctx, reviewID, userID)
+	if err != nil {
+		return types.ReviewInfo{}, err
+	}
+	if detail.Status != model.StatusApproved {
+		return types.ReviewInfo{}, ErrReviewNotFound
+	}
+	return detail, nil
+}
+
 func (r *ReviewServiceImpl) buildReviewInfoList(ctx context.Context, listRaw []*model.Comment, userID int) (list []types.ReviewInfo, err error) {
 	// get likes from redis
 	// like count
@@ -105,15 +176,15 @@ func (r *ReviewServiceImpl) buildReviewInfoList(ctx context.Context, listRaw []*
 	}
 
 	// current user liked
-	userLikesReviewSetKey := fmt.Sprintf("user:%d:likes", userID)
-	likedReviewList, err := r.reviewDao.SMembers(ctx, userLikesReviewSetKey)
+	liked, err := r.likedBy(ctx, userID, members)
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
 	ans := make([]types.ReviewInfo, len(listRaw))
 	for idx, review := range listRaw {
-		curUserLiked := ExistInSlice(likedReviewList, review.ID)
+		curUserLiked := liked[review.ID]
 		ans[idx] = types.ReviewInfo{
 			ID:               review.ID,
 			Content:          review.Content,
@@ -126,154 +197,341 @@ func (r *ReviewServiceImpl) buildReviewInfoList(ctx context.Context, listRaw []*
 			CreatedAt:        review.CreatedAt,
 			Likes:            likes[review.ID],
 			CurrentUserLiked: curUserLiked,
-			IsPinned:         review.IsPinned,
+			UnhelpfulCount:   review.UnhelpfulCount,
+			HelpfulScore:     review.HelpfulScore,
+			IsMerchantReply:  review.IsMerchantReply,
+			IsPinned:         review.IsPinnedAt(now),
+			PinPosition:      review.PinPosition,
+			PinnedUntil:      review.PinnedUntil,
+			Status:           review.ModerationStatus(),
+			ModerationReason: review.ModerationReason,
+			DeletedAt:        review.DeletedAt,
+			DeletedBy:        review.DeletedBy,
+			EditedAt:         review.EditedAt,
 		}
 	}
 
 	return ans, nil
 }
 
-func (r *ReviewServiceImpl) GetListByProductID(ctx context.Context, productId int, userID int) (resp types.ListReviewResponse, err error) {
+func (r *ReviewServiceImpl) GetListByProductID(ctx context.Context, req types.ListReviewRequest, userID int) (resp types.ListReviewResponse, err error) {
+	productId := req.ProductID
 	// 1. get review list
-	listRaw, err := r.reviewDao.GetListByProductID(ctx, productId)
+	listRaw, nextCursor, err := r.reviewDao.GetListByProductID(ctx, productId, pageOf(req))
 	if err != nil {
 		return types.ListReviewResponse{}, err
 	}
 
-	list, err := r.buildReviewInfoList(ctx, listRaw, userID)
+	list, err := r.buildReviewTree(ctx, listRaw, userID)
 	if err != nil {
 		return types.ListReviewResponse{}, err
 	}
 
-	// 2. get pinned review
-	productIdStr := strconv.Itoa(productId)
-	commentIdStr, err := r.reviewDao.HGet(ctx, pinnedReviewKey, productIdStr)
+	// 2. get pinned reviews
+	pinned, err := r.getPinnedReviews(ctx, productId, userID)
 	if err != nil {
 		return types.ListReviewResponse{}, err
 	}
 
 	// 3. build result
-	var pinnedReviewDetail types.ReviewInfo
-	if commentIdStr != "" {
-		pinnedReviewDetail, err = r.getReviewDetail(ctx, commentIdStr, userID)
-		if err != nil {
-			return types.ListReviewResponse{}, err
-		}
-		return types.ListReviewResponse{
-			ReviewList:   list,
-			PinnedReview: &pinnedReviewDetail,
-		}, nil
+	resp = types.ListReviewResponse{
+		ReviewList:    list,
+		PinnedReviews: pinned,
+		NextCursor:    nextCursor,
 	}
-
-	return types.ListReviewResponse{
-		ReviewList:   list,
-		PinnedReview: nil,
-	}, nil
+	if len(pinned) > 0 {
+		resp.PinnedReview = &pinned[0]
+	}
+	return resp, nil
 }
 
-// GetListByQuery returns list filtered by product and stars (stars==0 means any)
-func (r *ReviewServiceImpl) GetListByQuery(ctx context.Context, req types.ListReviewRequest, userID int) (resp []types.ReviewInfo, err error) {
-	productId := req.ProductID
-	stars := req.Stars
-	listRaw, err := r.reviewDao.GetListByQuery(ctx, productId, stars)
+// GetListByQuery returns the top level reviews matching the filters of the
+// merchant review list, with their replies nested. The product must be one of
+// the merchant's.
+func (r *ReviewServiceImpl) GetListByQuery(ctx context.Context, req types.ListReviewRequest, userID int) (resp types.ListReviewResponse, err error) {
+	if err := validation.ValidateReviewQuery(req); err != nil {
+		return types.ListReviewResponse{}, err
+	}
+	if err := r.checkProductOwner(ctx, userID, req.ProductID); err != nil {
+		return types.ListReviewResponse{}, err
+	}
+	listRaw, nextCursor, err := r.reviewDao.GetListByQuery(ctx, queryOf(req), pageOf(req))
 	if err != nil {
-		return nil, err
+		return types.ListReviewResponse{}, err
 	}
 
-	list, err := r.buildReviewInfoList(ctx, listRaw, userID)
+	list, err := r.buildReviewTree(ctx, listRaw, userID)
 	if err != nil {
-		return nil, err
+		return types.ListReviewResponse{}, err
 	}
 
-	return list, nil
+	return types.ListReviewResponse{
+		ReviewList: list,
+		NextCursor: nextCursor,
+	}, nil
 }
 
-func (r *ReviewServiceImpl) CreateReview(ctx context.Context, req types.CreateReviewRequest, userID int) (err error) {
-	return r.reviewDao.Save(ctx, &model.Comment{
-		Content:     req.Content,
-		UserID:      userID,
+func queryOf(req types.ListReviewRequest) dao.ReviewQuery {
+	return dao.ReviewQuery{
 		ProductID:   req.ProductID,
-		ParentID:    req.ParentID,
-		CreatedAt:   time.Now(),
-		IsAnonymous: req.IsAnonymous,
 		Stars:       req.Stars,
-		PicInfo:     req.PicInfo,
-	})
+		MinStars:    req.MinStars,
+		MaxStars:    req.MaxStars,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		HasPhotos:   req.HasPhotos,
+		IsAnonymous: req.IsAnonymous,
+		HasReply:    req.HasReply,
+		Status:      req.Status,
+		UserIDs:     req.UserIDs,
+		Keyword:     req.Keyword,
+	}
 }
 
-func (r *ReviewServiceImpl) Like(ctx context.Context, req types.LikeRequest, userID int) (err error) {
-	err = r.reviewDao.HIncr(ctx, reviewLikesCntKey, req.ReviewID, 1)
-	if err != nil {
-		log.Logger.Errorf("Like: failed, err %s", err.Error())
+func (r *ReviewServiceImpl) CreateReview(ctx context.Context, req types.CreateReviewRequest, userID int) (err error) {
+	return r.saveReview(ctx, req, userID, false)
+}
+
+// saveReview validates and saves a review or reply, isMerchantReply marks the
+// replies of the product's merchant.
+func (r *ReviewServiceImpl) saveReview(ctx context.Context, req types.CreateReviewRequest, userID int, isMerchantReply bool) (err error) {
+	if err := validation.ValidateCreateReview(req); err != nil {
 		return err
 	}
+	if model.IsReplyParentID(req.ParentID) {
+		parent, err := r.getParent(ctx, req.ParentID)
+		if err != nil {
+			return err
+		}
+		// replies posted without a product take the one of the thread
+		if req.ProductID == 0 {
+			req.ProductID = parent.ProductID
+		}
+		if parent.ProductID != req.ProductID {
+			return ErrParentProductMismatch
+		}
+	}
 
-	userLikesReviewSetKey := fmt.Sprintf("user:%d:likes", userID)
-	err = r.reviewDao.SAdd(ctx, userLikesReviewSetKey, req.ReviewID)
+	err = r.reviewDao.Save(ctx, &model.Comment{
+		Content:         req.Content,
+		UserID:          userID,
+		ProductID:       req.ProductID,
+		ParentID:        req.ParentID,
+		CreatedAt:       time.Now(),
+		IsAnonymous:     req.IsAnonymous,
+		Stars:           req.Stars,
+		PicInfo:         req.PicInfo,
+		Status:          initialStatus(),
+		IsMerchantReply: isMerchantReply,
+	})
 	if err != nil {
-		log.Logger.Errorf("Like: failed, err %s", err.Error())
 		return err
 	}
+	metrics.ReviewsCreated.WithLabelValues(reviewKind(req.ParentID, isMerchantReply)).Inc()
+	r.invalidateRatingSummary(ctx, req.ProductID)
 	return nil
 }
 
-func (r *ReviewServiceImpl) PinReview(ctx context.Context, reviewID string) (err error) {
-	commentRaw, err := r.reviewDao.Get(ctx, reviewID)
+// reviewKind is the kind label of a created review.
+func reviewKind(parentID string, isMerchantReply bool) string {
+	switch {
+	case isMerchantReply:
+		return "merchant_reply"
+	case model.IsReplyParentID(parentID):
+		return "reply"
+	default:
+		return "review"
+	}
+}
+
+// ReplyReview posts the merchant's reply to a review of one of its products.
+func (r *ReviewServiceImpl) ReplyReview(ctx context.Context, req types.CreateReviewRequest, merchantID int) (err error) {
+	parent, err := r.getParent(ctx, req.ParentID)
 	if err != nil {
 		return err
 	}
+	if err := r.checkProductOwner(ctx, merchantID, parent.ProductID); err != nil {
+		return err
+	}
+	if err := r.saveReview(ctx, req, merchantID, true); err != nil {
+		return err
+	}
+	r.countMerchantReply(ctx, parent.ID, 1)
+	return nil
+}
 
-	productIdStr := strconv.Itoa(commentRaw.ProductID)
+// countMerchantReply moves the merchant reply count of the review, it backs
+// the has_reply filter of the merchant review list. The reply itself is
+// already saved, so a failure is only logged.
+func (r *ReviewServiceImpl) countMerchantReply(ctx context.Context, reviewID string, delta int) {
+	if err := r.reviewDao.IncMerchantReplyCount(ctx, reviewID, delta); err != nil {
+		log.Ctx(ctx).Warnf("count merchant reply of review %s failed: %v", reviewID, err)
+	}
+}
 
-	oldCommentID, err := r.reviewDao.HGet(ctx, pinnedReviewKey, productIdStr)
+// getParent loads the review a reply answers.
+func (r *ReviewServiceImpl) getParent(ctx context.Context, parentID string) (*model.Comment, error) {
+	parent, err := r.reviewDao.Get(ctx, parentID)
+	if errors.Is(err, dao.ErrNotFound) {
+		return nil, ErrParentNotFound
+	}
 	if err != nil {
+		return nil, err
+	}
+	if parent.IsDeleted() {
+		return nil, ErrParentNotFound
+	}
+	return parent, nil
+}
+
+// getVotable loads a review customers can vote on, one that is approved and
+// not in the trash. Other reviews are not found.
+func (r *ReviewServiceImpl) getVotable(ctx context.Context, reviewID string) (*model.Comment, error) {
+	commentRaw, err := r.reviewDao.Get(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if commentRaw.IsDeleted() || commentRaw.ModerationStatus() != model.StatusApproved {
+		return nil, ErrReviewNotFound
+	}
+	return commentRaw, nil
+}
+
+// Like marks the review as liked by the user. The like is recorded in mongo,
+// which also keeps the like count the likes and helpful sorts rank by, redis
+// caches the counter and like sets. Liking an already liked review is a
+// no-op, so the counters only move when the user's like set changes. Only
+// approved reviews that are not in the trash can be liked.
+func (r *ReviewServiceImpl) Like(ctx context.Context, req types.LikeRequest, userID int) (err error) {
+	if _, err := r.getVotable(ctx, req.ReviewID); err != nil {
 		return err
 	}
 
-	if oldCommentID != "" {
-		err = r.reviewDao.UpdateIsPinnedByID(ctx, oldCommentID, false)
-		if err != nil {
+	added, err := r.likeDao.Add(ctx, req.ReviewID, userID, time.Now())
+	if err != nil {
+		log.Ctx(ctx).Errorf("Like: record failed, err %s", err.Error())
+		return err
+	}
+	if added {
+		metrics.Likes.WithLabelValues("like").Inc()
+		if err := r.reviewDao.IncLikeCount(ctx, req.ReviewID, 1); err != nil {
+			log.Ctx(ctx).Errorf("Like: count failed, err %s", err.Error())
 			return err
 		}
 	}
+	// write through to redis, both writes are idempotent so a retry after a
+	// redis failure is safe. reconcile-likes repairs whatever is left behind.
+	userLikesReviewSetKey := fmt.Sprintf("user:%d:likes", userID)
+	reviewLikersKey := fmt.Sprintf(reviewLikersKeyFmt, req.ReviewID)
+	_, err = r.reviewDao.SAddHIncr(ctx, userLikesReviewSetKey, reviewLikesCntKey, req.ReviewID, reviewLikersKey, strconv.Itoa(userID))
+	if err != nil {
+		log.Ctx(ctx).Errorf("Like: failed, err %s", err.Error())
+		return err
+	}
+	return nil
+}
 
-	err = r.reviewDao.UpdateIsPinnedByID(ctx, reviewID, true)
+// Unlike takes back the user's like on the review. Unliking a review that is
+// not liked is a no-op, unliking one that cannot be liked is not found.
+func (r *ReviewServiceImpl) Unlike(ctx context.Context, req types.LikeRequest, userID int) (err error) {
+	if _, err := r.getVotable(ctx, req.ReviewID); err != nil {
+		return err
+	}
+	removed, err := r.likeDao.Remove(ctx, req.ReviewID, userID)
 	if err != nil {
+		log.Ctx(ctx).Errorf("Unlike: remove record failed, err %s", err.Error())
 		return err
 	}
+	if removed {
+		metrics.Likes.WithLabelValues("unlike").Inc()
+		if err := r.reviewDao.IncLikeCount(ctx, req.ReviewID, -1); err != nil {
+			log.Ctx(ctx).Errorf("Unlike: count failed, err %s", err.Error())
+			return err
+		}
+	}
+	userLikesReviewSetKey := fmt.Sprintf("user:%d:likes", userID)
+	reviewLikersKey := fmt.Sprintf(reviewLikersKeyFmt, req.ReviewID)
+	_, err = r.reviewDao.SRemHDecr(ctx, userLikesReviewSetKey, reviewLikesCntKey, req.ReviewID, reviewLikersKey, strconv.Itoa(userID))
+	if err != nil {
+		log.Ctx(ctx).Errorf("Unlike: failed, err %s", err.Error())
+		return err
+	}
+	return nil
+}
 
-	return r.reviewDao.HSet(ctx, pinnedReviewKey, productIdStr, reviewID)
+// MarkUnhelpful records that the user found the review unhelpful, the vote
+// lowers its helpful score. Voting twice is a no-op. Like likes, votes are
+// limited to approved reviews that are not in the trash.
+func (r *ReviewServiceImpl) MarkUnhelpful(ctx context.Context, reviewID string, userID int) (err error) {
+	if _, err := r.getVotable(ctx, reviewID); err != nil {
+		return err
+	}
+	if _, err := r.reviewDao.AddUnhelpfulVote(ctx, reviewID, userID); err != nil {
+		log.Ctx(ctx).Errorf("MarkUnhelpful: failed, err %s", err.Error())
+		return err
+	}
+	return nil
 }
 
-func (r *ReviewServiceImpl) DeleteReview(ctx context.Context, reviewID string) (err error) {
+// UnmarkUnhelpful takes back the user's unhelpful vote on the review.
+func (r *ReviewServiceImpl) UnmarkUnhelpful(ctx context.Context, reviewID string, userID int) (err error) {
+	if _, err := r.getVotable(ctx, reviewID); err != nil {
+		return err
+	}
+	if _, err := r.reviewDao.RemoveUnhelpfulVote(ctx, reviewID, userID); err != nil {
+		log.Ctx(ctx).Errorf("UnmarkUnhelpful: failed, err %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+// DeleteReview moves a review of one of the merchant's products to the trash.
+func (r *ReviewServiceImpl) DeleteReview(ctx context.Context, reviewID string, merchantID int) (err error) {
 	// get comment to know product id
 	commentRaw, err := r.reviewDao.Get(ctx, reviewID)
 	if err != nil {
 		return err
 	}
-
-	// delete from mongo
-	if err := r.reviewDao.Delete(ctx, reviewID); err != nil {
+	if commentRaw.IsDeleted() {
+		return ErrReviewNotFound
+	}
+	if err := r.checkProductOwner(ctx, merchantID, commentRaw.ProductID); err != nil {
 		return err
 	}
+	return r.deleteReview(ctx, commentRaw, merchantID, "merchant")
+}
 
-	// remove likes hash field
-	if err := r.reviewDao.HDel(ctx, reviewLikesCntKey, reviewID); err != nil {
+// DeleteOwnReview moves a review or reply written by the user to the trash.
+func (r *ReviewServiceImpl) DeleteOwnReview(ctx context.Context, reviewID string, userID int) (err error) {
+	commentRaw, err := r.reviewDao.Get(ctx, reviewID)
+	if err != nil {
 		return err
 	}
+	if commentRaw.IsDeleted() {
+		return ErrReviewNotFound
+	}
+	if commentRaw.UserID != userID {
+		return ErrForbidden
+	}
+	return r.deleteReview(ctx, commentRaw, userID, "author")
+}
 
-	// remove from any user's liked sets is optional (could be many) - skip
-
-	// if this review was pinned for the product, delete pinned mapping
-	productIdStr := strconv.Itoa(commentRaw.ProductID)
-	pinnedId, err := r.reviewDao.HGet(ctx, pinnedReviewKey, productIdStr)
-	if err != nil {
+// deleteReview moves the review to the trash. It keeps its likes so it can be
+// restored, but it is unpinned right away. PurgeDeleted removes it for good.
+// by labels the delete in the metrics: author or merchant.
+func (r *ReviewServiceImpl) deleteReview(ctx context.Context, commentRaw *model.Comment, operatorID int, by string) (err error) {
+	reviewID := commentRaw.ID
+	if err := r.reviewDao.SoftDelete(ctx, reviewID, operatorID, time.Now()); err != nil {
 		return err
 	}
-	if pinnedId == reviewID {
-		if err := r.reviewDao.HDel(ctx, pinnedReviewKey, productIdStr); err != nil {
-			return err
-		}
+	metrics.Deletes.WithLabelValues(by).Inc()
+	r.invalidateRatingSummary(ctx, commentRaw.ProductID)
+	if commentRaw.IsMerchantReply {
+		r.countMerchantReply(ctx, commentRaw.ParentID, -1)
+	}
+
+	if commentRaw.IsPinned {
+		return r.clearPin(ctx, commentRaw)
 	}
 
 	return nil
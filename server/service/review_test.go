@@ -10,10 +10,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/authz"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mocks"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/validation"
 )
 
 func init() {
@@ -35,7 +38,7 @@ func TestCreateReview_Success(t *testing.T) {
 		Content:     "great",
 		ParentID:    "0",
 		Stars:       5,
-		PicInfo:     []string{"a.jpg"},
+		PicInfo:     []string{"https://cdn.example.com/a.jpg"},
 		IsAnonymous: false,
 	}
 	userID := 123
@@ -54,6 +57,9 @@ func TestCreateReview_Success(t *testing.T) {
 			return nil
 		})
 
+	// cached rating summary of the product is dropped
+	mockDao.EXPECT().Incr(gomock.Any(), "product:42:rating_version").Return(nil)
+
 	err := svc.CreateReview(context.Background(), req, userID)
 	assert.NoError(t, err)
 }
@@ -63,56 +69,262 @@ func TestLike_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
 
 	reviewID := "99"
 	userID := 77
 
-	// Expect HIncr called first
-	mockDao.EXPECT().HIncr(gomock.Any(), "review_likes", reviewID, 1).Return(nil)
-	// Then expect SAdd called
-	mockDao.EXPECT().SAdd(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", reviewID).Return(nil)
+	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, Status: model.StatusApproved}, nil)
+	// set membership and counter are updated together
+	mockLikeDao.EXPECT().Add(gomock.Any(), reviewID, userID, gomock.Any()).Return(true, nil)
+	mockDao.EXPECT().IncLikeCount(gomock.Any(), reviewID, 1).Return(nil)
+	mockDao.EXPECT().SAddHIncr(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", "review_likes", reviewID, "review:"+reviewID+":likers", strconv.Itoa(userID)).Return(true, nil)
 
 	err := svc.Like(context.Background(), types.LikeRequest{ReviewID: reviewID}, userID)
 	assert.NoError(t, err)
 }
 
-func TestLike_HIncrFail(t *testing.T) {
+func TestLike_AlreadyLiked(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
 
 	reviewID := "100"
 	userID := 77
 
-	// HIncr returns error
-	mockDao.EXPECT().HIncr(gomock.Any(), "review_likes", reviewID, 1).Return(assert.AnError)
+	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, Status: model.StatusApproved}, nil)
+	// a repeated like does not change anything and is not an error
+	mockLikeDao.EXPECT().Add(gomock.Any(), reviewID, userID, gomock.Any()).Return(false, nil)
+	mockDao.EXPECT().SAddHIncr(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", "review_likes", reviewID, "review:"+reviewID+":likers", strconv.Itoa(userID)).Return(false, nil)
 
 	err := svc.Like(context.Background(), types.LikeRequest{ReviewID: reviewID}, userID)
-	assert.Error(t, err)
+	assert.NoError(t, err)
 }
 
-func TestLike_SAddFail(t *testing.T) {
+func TestLike_Fail(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
 
 	reviewID := "101"
 	userID := 88
 
-	// HIncr succeeds
-	mockDao.EXPECT().HIncr(gomock.Any(), "review_likes", reviewID, 1).Return(nil)
-	// SAdd fails
-	mockDao.EXPECT().SAdd(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", reviewID).Return(assert.AnError)
+	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, Status: model.StatusApproved}, nil)
+	mockLikeDao.EXPECT().Add(gomock.Any(), reviewID, userID, gomock.Any()).Return(true, nil)
+	mockDao.EXPECT().IncLikeCount(gomock.Any(), reviewID, 1).Return(nil)
+	// the like is recorded but the cache write fails, the caller can retry
+	mockDao.EXPECT().SAddHIncr(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", "review_likes", reviewID, "review:"+reviewID+":likers", strconv.Itoa(userID)).Return(false, assert.AnError)
+
+	err := svc.Like(context.Background(), types.LikeRequest{ReviewID: reviewID}, userID)
+	assert.Error(t, err)
+}
+
+func TestLike_RecordFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+	reviewID := "105"
+	userID := 88
+
+	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, Status: model.StatusApproved}, nil)
+	// redis is not touched when mongo fails
+	mockLikeDao.EXPECT().Add(gomock.Any(), reviewID, userID, gomock.Any()).Return(false, assert.AnError)
+
+	err := svc.Like(context.Background(), types.LikeRequest{ReviewID: reviewID}, userID)
+	assert.Error(t, err)
+}
+
+func TestLike_CountFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+	reviewID := "106"
+	userID := 88
+
+	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, Status: model.StatusApproved}, nil)
+	// redis is not touched when the like count cannot be updated
+	mockLikeDao.EXPECT().Add(gomock.Any(), reviewID, userID, gomock.Any()).Return(true, nil)
+	mockDao.EXPECT().IncLikeCount(gomock.Any(), reviewID, 1).Return(assert.AnError)
 
 	err := svc.Like(context.Background(), types.LikeRequest{ReviewID: reviewID}, userID)
 	assert.Error(t, err)
 }
 
+func TestLike_ReviewNotLikeable(t *testing.T) {
+	deletedAt := time.Now()
+	cases := map[string]struct {
+		comment *model.Comment
+		err     error
+	}{
+		"missing":  {err: dao.ErrNotFound},
+		"deleted":  {comment: &model.Comment{ID: "107", Status: model.StatusApproved, DeletedAt: &deletedAt}},
+		"pending":  {comment: &model.Comment{ID: "107", Status: model.StatusPending}},
+		"rejected": {comment: &model.Comment{ID: "107", Status: model.StatusRejected}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDao := mocks.NewMockCommentDao(ctrl)
+			mockLikeDao := mocks.NewMockLikeDao(ctrl)
+			svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+			// no like is recorded or cached
+			mockDao.EXPECT().Get(gomock.Any(), "107").Return(tc.comment, tc.err)
+
+			err := svc.Like(context.Background(), types.LikeRequest{ReviewID: "107"}, 88)
+			assert.ErrorIs(t, err, ErrReviewNotFound)
+		})
+	}
+}
+
+func TestUnlike_ReviewNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+	// no like is removed and redis is not written
+	mockDao.EXPECT().Get(gomock.Any(), "not-an-id").Return(nil, dao.ErrNotFound)
+
+	err := svc.Unlike(context.Background(), types.LikeRequest{ReviewID: "not-an-id"}, 77)
+	assert.ErrorIs(t, err, ErrReviewNotFound)
+}
+
+func TestMarkUnhelpful(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	mockDao.EXPECT().Get(gomock.Any(), "107").Return(&model.Comment{ID: "107", Status: model.StatusApproved}, nil).Times(2)
+	mockDao.EXPECT().AddUnhelpfulVote(gomock.Any(), "107", 77).Return(true, nil)
+	mockDao.EXPECT().AddUnhelpfulVote(gomock.Any(), "107", 77).Return(false, nil)
+
+	// voting twice is not an error
+	assert.NoError(t, svc.MarkUnhelpful(context.Background(), "107", 77))
+	assert.NoError(t, svc.MarkUnhelpful(context.Background(), "107", 77))
+}
+
+func TestUnmarkUnhelpful_Fail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	mockDao.EXPECT().Get(gomock.Any(), "108").Return(&model.Comment{ID: "108", Status: model.StatusApproved}, nil)
+	mockDao.EXPECT().RemoveUnhelpfulVote(gomock.Any(), "108", 77).Return(false, assert.AnError)
+
+	err := svc.UnmarkUnhelpful(context.Background(), "108", 77)
+	assert.Error(t, err)
+}
+
+func TestUnhelpful_ReviewNotVotable(t *testing.T) {
+	deletedAt := time.Now()
+	cases := map[string]struct {
+		comment *model.Comment
+		err     error
+	}{
+		"missing": {err: dao.ErrNotFound},
+		"deleted": {comment: &model.Comment{ID: "109", Status: model.StatusApproved, DeletedAt: &deletedAt}},
+		"hidden":  {comment: &model.Comment{ID: "109", Status: model.StatusHidden}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDao := mocks.NewMockCommentDao(ctrl)
+			svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+			// the helpful score is not touched
+			mockDao.EXPECT().Get(gomock.Any(), "109").Return(tc.comment, tc.err).Times(2)
+
+			assert.ErrorIs(t, svc.MarkUnhelpful(context.Background(), "109", 77), ErrReviewNotFound)
+			assert.ErrorIs(t, svc.UnmarkUnhelpful(context.Background(), "109", 77), ErrReviewNotFound)
+		})
+	}
+}
+
+func TestUnlike_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+	reviewID := "102"
+	userID := 77
+
+	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, Status: model.StatusApproved}, nil)
+	mockLikeDao.EXPECT().Remove(gomock.Any(), reviewID, userID).Return(true, nil)
+	mockDao.EXPECT().IncLikeCount(gomock.Any(), reviewID, -1).Return(nil)
+	mockDao.EXPECT().SRemHDecr(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", "review_likes", reviewID, "review:"+reviewID+":likers", strconv.Itoa(userID)).Return(true, nil)
+
+	err := svc.Unlike(context.Background(), types.LikeRequest{ReviewID: reviewID}, userID)
+	assert.NoError(t, err)
+}
+
+func TestUnlike_NotLiked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+	reviewID := "103"
+	userID := 77
+
+	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, Status: model.StatusApproved}, nil)
+	mockLikeDao.EXPECT().Remove(gomock.Any(), reviewID, userID).Return(false, nil)
+	mockDao.EXPECT().SRemHDecr(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", "review_likes", reviewID, "review:"+reviewID+":likers", strconv.Itoa(userID)).Return(false, nil)
+
+	err := svc.Unlike(context.Background(), types.LikeRequest{ReviewID: reviewID}, userID)
+	assert.NoError(t, err)
+}
+
+func TestUnlike_Fail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+	reviewID := "104"
+	userID := 88
+
+	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, Status: model.StatusApproved}, nil)
+	mockLikeDao.EXPECT().Remove(gomock.Any(), reviewID, userID).Return(true, nil)
+	mockDao.EXPECT().IncLikeCount(gomock.Any(), reviewID, -1).Return(nil)
+	mockDao.EXPECT().SRemHDecr(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", "review_likes", reviewID, "review:"+reviewID+":likers", strconv.Itoa(userID)).Return(false, assert.AnError)
+
+	err := svc.Unlike(context.Background(), types.LikeRequest{ReviewID: reviewID}, userID)
+	assert.Error(t, err)
+}
+
 func TestGetListByUserID_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -135,7 +347,7 @@ func TestGetListByUserID_Success(t *testing.T) {
 	}
 
 	// Expect GetListByUserID
-	mockDao.EXPECT().GetListByUserID(gomock.Any(), userID).Return([]*model.Comment{cm}, nil)
+	mockDao.EXPECT().GetListByUserID(gomock.Any(), userID, dao.Page{}).Return([]*model.Comment{cm}, "", nil)
 
 	// Expect HMGet called with members ["c1"] and return likes
 	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, gomock.AssignableToTypeOf([]string{})).DoAndReturn(
@@ -144,13 +356,13 @@ func TestGetListByUserID_Success(t *testing.T) {
 			return map[string]int{"c1": 5}, nil
 		})
 
-	// Expect SMembers for current user's liked set
-	mockDao.EXPECT().SMembers(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes").Return([]string{"c1"}, nil)
+	// Expect SMIsMember for current user's liked set
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", gomock.Any()).Return(map[string]bool{"c1": true}, nil)
 
-	list, err := svc.GetListByUserID(context.Background(), userID)
+	resp, err := svc.GetListByUserID(context.Background(), types.ListReviewRequest{}, userID)
 	assert.NoError(t, err)
-	assert.Len(t, list, 1)
-	ri := list[0]
+	assert.Len(t, resp.ReviewList, 1)
+	ri := resp.ReviewList[0]
 	assert.Equal(t, cm.ID, ri.ID)
 	assert.Equal(t, cm.Content, ri.Content)
 	assert.Equal(t, 5, ri.Likes)
@@ -178,7 +390,8 @@ func TestGetListByProductID_Success(t *testing.T) {
 		CreatedAt:   time.Now(),
 	}
 
-	mockDao.EXPECT().GetListByProductID(gomock.Any(), productID).Return([]*model.Comment{cm}, nil)
+	mockDao.EXPECT().GetListByProductID(gomock.Any(), productID, dao.Page{}).Return([]*model.Comment{cm}, "", nil)
+	mockDao.EXPECT().GetListByParentIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
 
 	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, gomock.AssignableToTypeOf([]string{})).DoAndReturn(
 		func(ctx context.Context, key string, members []string) (map[string]int, error) {
@@ -186,12 +399,14 @@ func TestGetListByProductID_Success(t *testing.T) {
 			return map[string]int{"p1": 2}, nil
 		})
 
-	mockDao.EXPECT().SMembers(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes").Return([]string{}, nil)
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", gomock.Any()).Return(map[string]bool{}, nil)
 
 	// No pinned review for this product
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return("", nil)
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:"+strconv.Itoa(productID)+":pinned_reviews").Return("", nil)
+	mockDao.EXPECT().GetPinnedByProductID(gomock.Any(), productID, gomock.Any()).Return(nil, nil)
+	mockDao.EXPECT().SetEx(gomock.Any(), "product:"+strconv.Itoa(productID)+":pinned_reviews", gomock.Any(), pinnedCacheTTL).Return(nil)
 
-	resp, err := svc.GetListByProductID(context.Background(), productID, userID)
+	resp, err := svc.GetListByProductID(context.Background(), types.ListReviewRequest{ProductID: productID}, userID)
 	assert.NoError(t, err)
 	assert.Len(t, resp.ReviewList, 1)
 	ri := resp.ReviewList[0]
@@ -199,6 +414,7 @@ func TestGetListByProductID_Success(t *testing.T) {
 	assert.Equal(t, 2, ri.Likes)
 	assert.False(t, ri.CurrentUserLiked)
 	assert.Nil(t, resp.PinnedReview)
+	assert.Empty(t, resp.PinnedReviews)
 }
 
 func TestDeleteReview_Success_Pinned(t *testing.T) {
@@ -206,23 +422,22 @@ func TestDeleteReview_Success_Pinned(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 77)}
 
 	reviewID := "del123"
 	productID := 77
 
-	// Get returns comment with ProductID
-	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	// Delete from mongo
-	mockDao.EXPECT().Delete(gomock.Any(), reviewID).Return(nil)
-	// remove likes hash field
-	mockDao.EXPECT().HDel(gomock.Any(), reviewLikesCntKey, reviewID).Return(nil)
-	// pinned mapping returns this review id
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return(reviewID, nil)
-	// remove pinned mapping
-	mockDao.EXPECT().HDel(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return(nil)
-
-	err := svc.DeleteReview(context.Background(), reviewID)
+	// Get returns a pinned comment with ProductID
+	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID, IsPinned: true, PinPosition: 1}, nil)
+	// move to the trash
+	mockDao.EXPECT().SoftDelete(gomock.Any(), reviewID, 9, gomock.Any()).Return(nil)
+	// drop cached rating summary
+	mockDao.EXPECT().Incr(gomock.Any(), "product:"+strconv.Itoa(productID)+":rating_version").Return(nil)
+	// unpin the document
+	mockDao.EXPECT().UpdateIsPinnedByID(gomock.Any(), reviewID, false).Return(nil)
+	mockDao.EXPECT().Del(gomock.Any(), "product:"+strconv.Itoa(productID)+":pinned_reviews").Return(nil)
+
+	err := svc.DeleteReview(context.Background(), reviewID, 9)
 	assert.NoError(t, err)
 }
 
@@ -253,8 +468,8 @@ func TestGetReviewDetail_Success(t *testing.T) {
 	// mock HGet for likes
 	mockDao.EXPECT().HGet(gomock.Any(), reviewLikesCntKey, reviewID).Return("7", nil)
 
-	// mock SMembers for current user liked set
-	mockDao.EXPECT().SMembers(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes").Return([]string{reviewID}, nil)
+	// mock SMIsMember for current user liked set
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", gomock.Any()).Return(map[string]bool{reviewID: true}, nil)
 
 	detail, err := svc.getReviewDetail(context.Background(), reviewID, userID)
 	assert.NoError(t, err)
@@ -287,36 +502,40 @@ func TestGetListByProductID_WithPinned_Success(t *testing.T) {
 	}
 
 	// list
-	mockDao.EXPECT().GetListByProductID(gomock.Any(), productID).Return([]*model.Comment{cm}, nil)
+	mockDao.EXPECT().GetListByProductID(gomock.Any(), productID, dao.Page{}).Return([]*model.Comment{cm}, "", nil)
+	mockDao.EXPECT().GetListByParentIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
 	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, gomock.AssignableToTypeOf([]string{})).DoAndReturn(
 		func(ctx context.Context, key string, members []string) (map[string]int, error) {
 			assert.Equal(t, []string{"rA"}, members)
 			return map[string]int{"rA": 4}, nil
 		})
-	mockDao.EXPECT().SMembers(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes").Return([]string{}, nil)
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", gomock.Any()).Return(map[string]bool{}, nil)
 
-	// pinned mapping exists
+	// pinned reviews exist
 	pinnedID := "pinned1"
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return(pinnedID, nil)
-
-	// getReviewDetail calls
-	mockDao.EXPECT().Get(gomock.Any(), pinnedID).Return(&model.Comment{
-		ID:        pinnedID,
-		Content:   "pinned content",
-		ProductID: productID,
-		UserID:    99,
-		CreatedAt: time.Now(),
+	secondID := "pinned2"
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:"+strconv.Itoa(productID)+":pinned_reviews").Return("", nil)
+	mockDao.EXPECT().GetPinnedByProductID(gomock.Any(), productID, gomock.Any()).Return([]*model.Comment{
+		{ID: pinnedID, Content: "pinned content", ProductID: productID, UserID: 99, IsPinned: true, PinPosition: 1, CreatedAt: time.Now()},
+		{ID: secondID, Content: "second", ProductID: productID, UserID: 98, IsPinned: true, PinPosition: 2, CreatedAt: time.Now()},
 	}, nil)
-	mockDao.EXPECT().HGet(gomock.Any(), reviewLikesCntKey, pinnedID).Return("3", nil)
-	mockDao.EXPECT().SMembers(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes").Return([]string{pinnedID}, nil)
+	mockDao.EXPECT().SetEx(gomock.Any(), "product:"+strconv.Itoa(productID)+":pinned_reviews", gomock.Any(), pinnedCacheTTL).Return(nil)
 
-	resp, err := svc.GetListByProductID(context.Background(), productID, userID)
+	// likes of the pinned reviews
+	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, []string{pinnedID, secondID}).Return(map[string]int{pinnedID: 3}, nil)
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", gomock.Any()).Return(map[string]bool{pinnedID: true}, nil)
+
+	resp, err := svc.GetListByProductID(context.Background(), types.ListReviewRequest{ProductID: productID}, userID)
 	assert.NoError(t, err)
 	assert.Len(t, resp.ReviewList, 1)
 	assert.NotNil(t, resp.PinnedReview)
 	assert.Equal(t, pinnedID, resp.PinnedReview.ID)
 	assert.Equal(t, 3, resp.PinnedReview.Likes)
 	assert.True(t, resp.PinnedReview.CurrentUserLiked)
+	assert.Len(t, resp.PinnedReviews, 2)
+	assert.Equal(t, pinnedID, resp.PinnedReviews[0].ID)
+	assert.Equal(t, secondID, resp.PinnedReviews[1].ID)
+	assert.Equal(t, 2, resp.PinnedReviews[1].PinPosition)
 }
 
 func TestGetListByProductID_HMGetError(t *testing.T) {
@@ -328,10 +547,10 @@ func TestGetListByProductID_HMGetError(t *testing.T) {
 
 	productID := 202
 	// make HMGet return error
-	mockDao.EXPECT().GetListByProductID(gomock.Any(), productID).Return([]*model.Comment{}, nil)
+	mockDao.EXPECT().GetListByProductID(gomock.Any(), productID, dao.Page{}).Return([]*model.Comment{}, "", nil)
 	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, gomock.AssignableToTypeOf([]string{})).Return(nil, assert.AnError)
 
-	_, err := svc.GetListByProductID(context.Background(), productID, 0)
+	_, err := svc.GetListByProductID(context.Background(), types.ListReviewRequest{ProductID: productID}, 0)
 	assert.Error(t, err)
 }
 
@@ -352,7 +571,7 @@ func TestGetReviewDetail_HGetError(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestGetReviewDetail_SMembersError(t *testing.T) {
+func TestGetReviewDetail_SMIsMemberError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -364,7 +583,7 @@ func TestGetReviewDetail_SMembersError(t *testing.T) {
 
 	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID}, nil)
 	mockDao.EXPECT().HGet(gomock.Any(), reviewLikesCntKey, reviewID).Return("2", nil)
-	mockDao.EXPECT().SMembers(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes").Return(nil, assert.AnError)
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", gomock.Any()).Return(nil, assert.AnError)
 
 	_, err := svc.getReviewDetail(context.Background(), reviewID, userID)
 	assert.Error(t, err)
@@ -375,9 +594,9 @@ func TestGetListByQuery_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
 
 	userID := 123
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(userID, 88)}
 	req := types.ListReviewRequest{
 		ProductID: 88,
 		Stars:     5,
@@ -407,18 +626,20 @@ func TestGetListByQuery_Success(t *testing.T) {
 	}
 
 	// Expect DAO method called with correct params
-	mockDao.EXPECT().GetListByQuery(gomock.Any(), req.ProductID, req.Stars).Return([]*model.Comment{cm1, cm2}, nil)
+	mockDao.EXPECT().GetListByQuery(gomock.Any(), dao.ReviewQuery{ProductID: req.ProductID, Stars: req.Stars}, dao.Page{}).Return([]*model.Comment{cm1, cm2}, "", nil)
+	mockDao.EXPECT().GetListByParentIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
 	// Expect HMGet called with both IDs
 	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, gomock.AssignableToTypeOf([]string{})).DoAndReturn(
 		func(ctx context.Context, key string, members []string) (map[string]int, error) {
 			assert.ElementsMatch(t, []string{"c1", "c2"}, members)
 			return map[string]int{"c1": 10, "c2": 5}, nil
 		})
-	// Expect SMembers for current user's liked set
-	mockDao.EXPECT().SMembers(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes").Return([]string{"c2"}, nil)
+	// Expect SMIsMember for current user's liked set
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", gomock.Any()).Return(map[string]bool{"c2": true}, nil)
 
-	list, err := svc.GetListByQuery(context.Background(), req, userID)
+	resp, err := svc.GetListByQuery(context.Background(), req, userID)
 	assert.NoError(t, err)
+	list := resp.ReviewList
 	assert.Len(t, list, 2)
 	// Check order: should be sorted by CreatedAt desc (cm1 newer)
 	assert.Equal(t, "c1", list[0].ID)
@@ -431,7 +652,7 @@ func TestGetListByQuery_Success(t *testing.T) {
 	assert.True(t, list[1].CurrentUserLiked)
 }
 
-// DeleteReview tests: cover Get failure, Delete failure, HDel(likes) failure,
+// DeleteReview tests: cover Get failure, SoftDelete failure, already deleted,
 // pinned HGet error, pinned HDel failure, and non-pinned success.
 func TestDeleteReview_GetFail(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -443,7 +664,7 @@ func TestDeleteReview_GetFail(t *testing.T) {
 	reviewID := "dgetfail"
 	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(nil, assert.AnError)
 
-	err := svc.DeleteReview(context.Background(), reviewID)
+	err := svc.DeleteReview(context.Background(), reviewID, 9)
 	assert.Error(t, err)
 }
 
@@ -452,151 +673,273 @@ func TestDeleteReview_DeleteFail(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 10)}
 
 	reviewID := "ddelfail"
 	productID := 10
 
 	// Get returns comment
 	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	// Delete fails
-	mockDao.EXPECT().Delete(gomock.Any(), reviewID).Return(assert.AnError)
+	// SoftDelete fails
+	mockDao.EXPECT().SoftDelete(gomock.Any(), reviewID, 9, gomock.Any()).Return(assert.AnError)
 
-	err := svc.DeleteReview(context.Background(), reviewID)
+	err := svc.DeleteReview(context.Background(), reviewID, 9)
 	assert.Error(t, err)
 }
 
-func TestDeleteReview_HDelLikesFail(t *testing.T) {
+func TestDeleteReview_AlreadyDeleted(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
 	svc := &ReviewServiceImpl{reviewDao: mockDao}
 
-	reviewID := "dhdel"
-	productID := 11
+	reviewID := "dtrash"
+	deletedAt := time.Now()
 
-	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	mockDao.EXPECT().Delete(gomock.Any(), reviewID).Return(nil)
-	// HDel for likes fails
-	mockDao.EXPECT().HDel(gomock.Any(), reviewLikesCntKey, reviewID).Return(assert.AnError)
+	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, DeletedAt: &deletedAt}, nil)
 
-	err := svc.DeleteReview(context.Background(), reviewID)
-	assert.Error(t, err)
+	err := svc.DeleteReview(context.Background(), reviewID, 9)
+	assert.ErrorIs(t, err, ErrReviewNotFound)
 }
 
-func TestDeleteReview_PinnedHGetFail(t *testing.T) {
+func TestDeleteReview_UnpinFail(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 12)}
 
 	reviewID := "dpinnederr"
 	productID := 12
 
-	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	mockDao.EXPECT().Delete(gomock.Any(), reviewID).Return(nil)
-	mockDao.EXPECT().HDel(gomock.Any(), reviewLikesCntKey, reviewID).Return(nil)
-	// HGet for pinned mapping fails
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return("", assert.AnError)
+	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID, IsPinned: true}, nil)
+	mockDao.EXPECT().SoftDelete(gomock.Any(), reviewID, 9, gomock.Any()).Return(nil)
+	mockDao.EXPECT().Incr(gomock.Any(), "product:"+strconv.Itoa(productID)+":rating_version").Return(nil)
+	// clearing the pin fails
+	mockDao.EXPECT().UpdateIsPinnedByID(gomock.Any(), reviewID, false).Return(assert.AnError)
 
-	err := svc.DeleteReview(context.Background(), reviewID)
+	err := svc.DeleteReview(context.Background(), reviewID, 9)
 	assert.Error(t, err)
 }
 
-func TestDeleteReview_PinnedHDelFail(t *testing.T) {
+func TestDeleteReview_NonPinned_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 21)}
 
-	reviewID := "dpinnedhdel"
-	productID := 13
+	reviewID := "dok"
+	productID := 21
 
 	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	mockDao.EXPECT().Delete(gomock.Any(), reviewID).Return(nil)
-	mockDao.EXPECT().HDel(gomock.Any(), reviewLikesCntKey, reviewID).Return(nil)
-	// pinned mapping matches and then HDel fails
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return(reviewID, nil)
-	mockDao.EXPECT().HDel(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return(assert.AnError)
+	mockDao.EXPECT().SoftDelete(gomock.Any(), reviewID, 9, gomock.Any()).Return(nil)
+	mockDao.EXPECT().Incr(gomock.Any(), "product:"+strconv.Itoa(productID)+":rating_version").Return(nil)
 
-	err := svc.DeleteReview(context.Background(), reviewID)
-	assert.Error(t, err)
+	err := svc.DeleteReview(context.Background(), reviewID, 9)
+	assert.NoError(t, err)
 }
 
-func TestDeleteReview_NonPinned_Success(t *testing.T) {
+func TestDeleteReview_Pinned_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 31)}
 
-	reviewID := "dok"
-	productID := 21
+	reviewID := "dpinsuccess"
+	productID := 31
 
-	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	mockDao.EXPECT().Delete(gomock.Any(), reviewID).Return(nil)
-	mockDao.EXPECT().HDel(gomock.Any(), reviewLikesCntKey, reviewID).Return(nil)
-	// pinned mapping returns different id
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return("otherid", nil)
+	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID, IsPinned: true, PinPosition: 2}, nil)
+	mockDao.EXPECT().SoftDelete(gomock.Any(), reviewID, 9, gomock.Any()).Return(nil)
+	mockDao.EXPECT().Incr(gomock.Any(), "product:"+strconv.Itoa(productID)+":rating_version").Return(nil)
+	// the pin is cleared, the remaining pins keep their order
+	mockDao.EXPECT().UpdateIsPinnedByID(gomock.Any(), reviewID, false).Return(nil)
+	mockDao.EXPECT().Del(gomock.Any(), "product:"+strconv.Itoa(productID)+":pinned_reviews").Return(nil)
 
-	err := svc.DeleteReview(context.Background(), reviewID)
+	err := svc.DeleteReview(context.Background(), reviewID, 9)
 	assert.NoError(t, err)
 }
 
-func TestDeleteReview_Pinned_Success(t *testing.T) {
+func TestGetListByQuery_Paginated(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
 
-	reviewID := "dpinsuccess"
-	productID := 31
+	userID := 42
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(userID, 500)}
+	req := types.ListReviewRequest{ProductID: 500, Limit: 1, Cursor: "cur1"}
 
-	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	mockDao.EXPECT().Delete(gomock.Any(), reviewID).Return(nil)
-	mockDao.EXPECT().HDel(gomock.Any(), reviewLikesCntKey, reviewID).Return(nil)
-	// pinned mapping matches and then remove it successfully
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return(reviewID, nil)
-	mockDao.EXPECT().HDel(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return(nil)
+	// limit and cursor are handed to the DAO, and its next cursor is returned
+	mockDao.EXPECT().GetListByQuery(gomock.Any(), dao.ReviewQuery{ProductID: req.ProductID, Stars: req.Stars}, dao.Page{Cursor: "cur1", Limit: 1}).
+		Return([]*model.Comment{{ID: "n1"}}, "cur2", nil)
+	mockDao.EXPECT().GetListByParentIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, []string{"n1"}).Return(map[string]int{"n1": 0}, nil)
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", gomock.Any()).Return(map[string]bool{}, nil)
 
-	err := svc.DeleteReview(context.Background(), reviewID)
+	resp, err := svc.GetListByQuery(context.Background(), req, userID)
 	assert.NoError(t, err)
+	assert.Len(t, resp.ReviewList, 1)
+	assert.Equal(t, "cur2", resp.NextCursor)
 }
 
-func TestGetListByQuery_HMGetError(t *testing.T) {
+func TestGetListByQuery_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 501)}
+
+	req := types.ListReviewRequest{ProductID: 501, Cursor: "garbage"}
+
+	mockDao.EXPECT().GetListByQuery(gomock.Any(), dao.ReviewQuery{ProductID: req.ProductID, Stars: req.Stars}, dao.Page{Cursor: "garbage"}).
+		Return(nil, "", dao.ErrInvalidCursor)
+
+	_, err := svc.GetListByQuery(context.Background(), req, 9)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestGetListByQuery_Sort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 502)}
+
+	req := types.ListReviewRequest{ProductID: 502, Sort: dao.SortHelpful}
+	cm := &model.Comment{ID: "h1", ProductID: 502, LikeCount: 9, UnhelpfulCount: 1, HelpfulScore: 0.5958}
+
+	mockDao.EXPECT().GetListByQuery(gomock.Any(), dao.ReviewQuery{ProductID: req.ProductID, Stars: req.Stars}, dao.Page{Sort: dao.SortHelpful}).
+		Return([]*model.Comment{cm}, "", nil)
+	mockDao.EXPECT().GetListByParentIDs(gomock.Any(), []string{"h1"}).Return(nil, nil)
+	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, []string{"h1"}).Return(map[string]int{"h1": 9}, nil)
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:9:likes", []string{"h1"}).Return(map[string]bool{}, nil)
+
+	resp, err := svc.GetListByQuery(context.Background(), req, 9)
+	assert.NoError(t, err)
+	assert.Len(t, resp.ReviewList, 1)
+	assert.Equal(t, 1, resp.ReviewList[0].UnhelpfulCount)
+	assert.Equal(t, 0.5958, resp.ReviewList[0].HelpfulScore)
+}
+
+func TestGetListByQuery_InvalidSort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 503)}
+
+	req := types.ListReviewRequest{ProductID: 503, Sort: "random"}
+
+	mockDao.EXPECT().GetListByQuery(gomock.Any(), dao.ReviewQuery{ProductID: req.ProductID, Stars: req.Stars}, dao.Page{Sort: "random"}).
+		Return(nil, "", dao.ErrInvalidSort)
+
+	_, err := svc.GetListByQuery(context.Background(), req, 9)
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func TestGetListByQuery_Filters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 504)}
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	yes, no := true, false
+	req := types.ListReviewRequest{
+		ProductID:   504,
+		MinStars:    1,
+		MaxStars:    2,
+		CreatedFrom: &from,
+		HasPhotos:   &yes,
+		HasReply:    &no,
+		Status:      model.StatusPending,
+		UserIDs:     []int{3, 4},
+		Keyword:     "chipped",
+	}
+
+	mockDao.EXPECT().GetListByQuery(gomock.Any(), dao.ReviewQuery{
+		ProductID:   504,
+		MinStars:    1,
+		MaxStars:    2,
+		CreatedFrom: &from,
+		HasPhotos:   &yes,
+		HasReply:    &no,
+		Status:      model.StatusPending,
+		UserIDs:     []int{3, 4},
+		Keyword:     "chipped",
+	}, dao.Page{}).Return(nil, "", nil)
+	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, []string{}).Return(map[string]int{}, nil)
+
+	resp, err := svc.GetListByQuery(context.Background(), req, 9)
+	assert.NoError(t, err)
+	assert.Empty(t, resp.ReviewList)
+}
+
+func TestGetListByQuery_InvalidFilters(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
 	svc := &ReviewServiceImpl{reviewDao: mockDao}
 
+	// rejected before mongo is queried
+	_, err := svc.GetListByQuery(context.Background(), types.ListReviewRequest{MinStars: 5, MaxStars: 1}, 0)
+	var fieldErrs validation.Errors
+	assert.ErrorAs(t, err, &fieldErrs)
+}
+
+func TestGetListByQuery_OtherMerchant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// merchant 8 does not own product 505, mongo is not queried
+	svc := &ReviewServiceImpl{reviewDao: mocks.NewMockCommentDao(ctrl), ownership: ownerOf(7, 505)}
+
+	_, err := svc.GetListByQuery(context.Background(), types.ListReviewRequest{ProductID: 505}, 8)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	_, err = svc.GetListByQuery(context.Background(), types.ListReviewRequest{}, 7)
+	var fieldErrs validation.Errors
+	assert.ErrorAs(t, err, &fieldErrs)
+}
+
+func TestGetListByQuery_HMGetError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 300)}
+
 	req := types.ListReviewRequest{ProductID: 300, Stars: 4}
 
-	mockDao.EXPECT().GetListByQuery(gomock.Any(), req.ProductID, req.Stars).Return([]*model.Comment{{ID: "a1"}}, nil)
+	mockDao.EXPECT().GetListByQuery(gomock.Any(), dao.ReviewQuery{ProductID: req.ProductID, Stars: req.Stars}, dao.Page{}).Return([]*model.Comment{{ID: "a1"}}, "", nil)
+	mockDao.EXPECT().GetListByParentIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
 	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, gomock.AssignableToTypeOf([]string{})).Return(nil, assert.AnError)
 
-	_, err := svc.GetListByQuery(context.Background(), req, 0)
+	_, err := svc.GetListByQuery(context.Background(), req, 9)
 	assert.Error(t, err)
 }
 
-func TestGetListByQuery_SMembersError(t *testing.T) {
+func TestGetListByQuery_SMIsMemberError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(7, 301)}
 
 	req := types.ListReviewRequest{ProductID: 301, Stars: 5}
 
-	mockDao.EXPECT().GetListByQuery(gomock.Any(), req.ProductID, req.Stars).Return([]*model.Comment{{ID: "b1"}}, nil)
+	mockDao.EXPECT().GetListByQuery(gomock.Any(), dao.ReviewQuery{ProductID: req.ProductID, Stars: req.Stars}, dao.Page{}).Return([]*model.Comment{{ID: "b1"}}, "", nil)
+	mockDao.EXPECT().GetListByParentIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
 	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, gomock.AssignableToTypeOf([]string{})).Return(map[string]int{"b1": 1}, nil)
-	mockDao.EXPECT().SMembers(gomock.Any(), gomock.AssignableToTypeOf("")).Return(nil, assert.AnError)
+	// only the reviews on the page are checked
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:7:likes", []string{"b1"}).Return(nil, assert.AnError)
 
-	_, err := svc.GetListByQuery(context.Background(), req, 0)
+	_, err := svc.GetListByQuery(context.Background(), req, 7)
 	assert.Error(t, err)
 }
 
@@ -605,13 +948,13 @@ func TestGetListByQuery_DAOError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 302)}
 
 	req := types.ListReviewRequest{ProductID: 302, Stars: 3}
 
-	mockDao.EXPECT().GetListByQuery(gomock.Any(), req.ProductID, req.Stars).Return(nil, assert.AnError)
+	mockDao.EXPECT().GetListByQuery(gomock.Any(), dao.ReviewQuery{ProductID: req.ProductID, Stars: req.Stars}, dao.Page{}).Return(nil, "", assert.AnError)
 
-	_, err := svc.GetListByQuery(context.Background(), req, 0)
+	_, err := svc.GetListByQuery(context.Background(), req, 9)
 	assert.Error(t, err)
 }
 
@@ -620,157 +963,239 @@ func TestGetListByQuery_StarsZero_AllStars(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
-	svc := &ReviewServiceImpl{reviewDao: mockDao}
 
 	userID := 77
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(userID, 400)}
 	req := types.ListReviewRequest{ProductID: 400, Stars: 0}
 
 	cm1 := &model.Comment{ID: "s1", CreatedAt: time.Now()}
 	cm2 := &model.Comment{ID: "s2", CreatedAt: time.Now().Add(-time.Minute)}
 
-	mockDao.EXPECT().GetListByQuery(gomock.Any(), req.ProductID, req.Stars).Return([]*model.Comment{cm1, cm2}, nil)
+	mockDao.EXPECT().GetListByQuery(gomock.Any(), dao.ReviewQuery{ProductID: req.ProductID, Stars: req.Stars}, dao.Page{}).Return([]*model.Comment{cm1, cm2}, "", nil)
+	mockDao.EXPECT().GetListByParentIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
 	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, gomock.AssignableToTypeOf([]string{})).Return(map[string]int{"s1": 2, "s2": 0}, nil)
-	mockDao.EXPECT().SMembers(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes").Return([]string{"s1"}, nil)
+	mockDao.EXPECT().SMIsMember(gomock.Any(), "user:"+strconv.Itoa(userID)+":likes", gomock.Any()).Return(map[string]bool{"s1": true}, nil)
 
-	list, err := svc.GetListByQuery(context.Background(), req, userID)
+	resp, err := svc.GetListByQuery(context.Background(), req, userID)
 	assert.NoError(t, err)
+	list := resp.ReviewList
 	assert.Len(t, list, 2)
 	assert.Equal(t, "s1", list[0].ID)
 	assert.Equal(t, 2, list[0].Likes)
 	assert.True(t, list[0].CurrentUserLiked)
 }
 
-// PinReview comprehensive tests
-func TestPinReview_GetFail(t *testing.T) {
+func TestGetRatingSummary_CacheHit(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
 	svc := &ReviewServiceImpl{reviewDao: mockDao}
 
-	reviewID := "pgf"
-	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(nil, assert.AnError)
+	cached := `{"product_id":7,"total_count":2,"average_stars":4.5,"star_histogram":{"4":1,"5":1}}`
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:7:rating_version").Return("3", nil)
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:7:rating_summary:v3").Return(cached, nil)
 
-	err := svc.PinReview(context.Background(), reviewID)
-	assert.Error(t, err)
+	summary, err := svc.GetRatingSummary(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.TotalCount)
+	assert.Equal(t, 4.5, summary.AverageStars)
+	assert.Equal(t, 1, summary.StarHistogram[5])
 }
 
-func TestPinReview_HGetFail(t *testing.T) {
+func TestGetRatingSummary_CacheMiss(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
 	svc := &ReviewServiceImpl{reviewDao: mockDao}
 
-	reviewID := "phg"
-	productID := 7
+	// a product never written to is at version 0
+	key := "product:8:rating_summary:v0"
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:8:rating_version").Return("", nil)
+	mockDao.EXPECT().GetStr(gomock.Any(), key).Return("", nil)
+	mockDao.EXPECT().AggregateRatingByProductID(gomock.Any(), 8).Return(&model.RatingStats{
+		TotalCount: 3,
+		StarSum:    11,
+		Star3:      1,
+		Star4:      1,
+		Star5:      1,
+		PhotoCount: 2,
+		ReplyCount: 4,
+	}, nil)
+	mockDao.EXPECT().SetEx(gomock.Any(), key, gomock.Any(), ratingSummaryTTL).Return(nil)
 
-	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return("", assert.AnError)
+	summary, err := svc.GetRatingSummary(context.Background(), 8)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, summary.ProductID)
+	assert.Equal(t, 3, summary.TotalCount)
+	assert.Equal(t, 3.67, summary.AverageStars)
+	assert.Equal(t, map[int]int{1: 0, 2: 0, 3: 1, 4: 1, 5: 1}, summary.StarHistogram)
+	assert.Equal(t, 2, summary.PhotoReviewCount)
+	assert.Equal(t, 4, summary.ReplyCount)
+}
+
+func TestGetRatingSummary_NoReviews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	mockDao.EXPECT().GetStr(gomock.Any(), gomock.Any()).Return("", nil).Times(2)
+	mockDao.EXPECT().AggregateRatingByProductID(gomock.Any(), 9).Return(&model.RatingStats{}, nil)
+	mockDao.EXPECT().SetEx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	summary, err := svc.GetRatingSummary(context.Background(), 9)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.TotalCount)
+	assert.Equal(t, float64(0), summary.AverageStars)
+}
+
+func TestGetRatingSummary_AggregateError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	// a broken cache falls back to mongo
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:10:rating_version").Return("", assert.AnError)
+	mockDao.EXPECT().AggregateRatingByProductID(gomock.Any(), 10).Return(nil, assert.AnError)
 
-	err := svc.PinReview(context.Background(), reviewID)
+	_, err := svc.GetRatingSummary(context.Background(), 10)
 	assert.Error(t, err)
 }
 
-func TestPinReview_NoOld_Success(t *testing.T) {
+func TestGetRatingSummary_VersionUnreadable(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
 	svc := &ReviewServiceImpl{reviewDao: mockDao}
 
-	reviewID := "pnoold"
-	productID := 8
+	// without the version the summary is neither read from nor written to
+	// the cache, it could be of an older version
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:11:rating_version").Return("", assert.AnError)
+	mockDao.EXPECT().AggregateRatingByProductID(gomock.Any(), 11).Return(&model.RatingStats{TotalCount: 1, StarSum: 4, Star4: 1}, nil)
 
-	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	// no old pinned
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return("", nil)
-	// set new pinned flag
-	mockDao.EXPECT().UpdateIsPinnedByID(gomock.Any(), reviewID, true).Return(nil)
-	// set mapping
-	mockDao.EXPECT().HSet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID), reviewID).Return(nil)
-
-	err := svc.PinReview(context.Background(), reviewID)
+	summary, err := svc.GetRatingSummary(context.Background(), 11)
 	assert.NoError(t, err)
+	assert.Equal(t, 4.0, summary.AverageStars)
 }
 
-func TestPinReview_WithOld_Success(t *testing.T) {
+func TestBatchGetRatingSummary_DedupesProducts(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
 	svc := &ReviewServiceImpl{reviewDao: mockDao}
 
-	reviewID := "pwithold_new"
-	oldID := "pwithold_old"
-	productID := 9
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:1:rating_version").Return("1", nil)
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:1:rating_summary:v1").Return(`{"product_id":1,"total_count":1}`, nil)
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:2:rating_version").Return("", nil)
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:2:rating_summary:v0").Return(`{"product_id":2,"total_count":4}`, nil)
 
-	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	// old pinned exists
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return(oldID, nil)
-	// unset old pinned
-	mockDao.EXPECT().UpdateIsPinnedByID(gomock.Any(), oldID, false).Return(nil)
-	// set new pinned
-	mockDao.EXPECT().UpdateIsPinnedByID(gomock.Any(), reviewID, true).Return(nil)
-	// set mapping
-	mockDao.EXPECT().HSet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID), reviewID).Return(nil)
-
-	err := svc.PinReview(context.Background(), reviewID)
+	summaries, err := svc.BatchGetRatingSummary(context.Background(), []int{1, 2, 1})
 	assert.NoError(t, err)
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, 4, summaries[2].TotalCount)
 }
 
-func TestPinReview_UpdateOldFail(t *testing.T) {
+func TestGetListByProductID_NestsReplies(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
 	svc := &ReviewServiceImpl{reviewDao: mockDao}
 
-	reviewID := "poldfail_new"
-	oldID := "poldfail_old"
-	productID := 10
+	productID := 60
+	root := &model.Comment{ID: "r1", ProductID: productID}
+	reply := &model.Comment{ID: "r1-1", ParentID: "r1", ProductID: productID}
+	nested := &model.Comment{ID: "r1-1-1", ParentID: "r1-1", ProductID: productID}
+
+	mockDao.EXPECT().GetListByProductID(gomock.Any(), productID, dao.Page{}).Return([]*model.Comment{root}, "", nil)
+	// default depth is two levels, the second level only reports its reply count
+	mockDao.EXPECT().GetListByParentIDs(gomock.Any(), []string{"r1"}).Return([]*model.Comment{reply}, nil)
+	mockDao.EXPECT().GetListByParentIDs(gomock.Any(), []string{"r1-1"}).Return([]*model.Comment{nested}, nil)
+	mockDao.EXPECT().CountByParentIDs(gomock.Any(), []string{"r1-1-1"}).Return(map[string]int{"r1-1-1": 4}, nil)
+	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, []string{"r1", "r1-1", "r1-1-1"}).
+		Return(map[string]int{"r1-1": 3}, nil)
+	mockDao.EXPECT().GetStr(gomock.Any(), "product:"+strconv.Itoa(productID)+":pinned_reviews").Return("", nil)
+	mockDao.EXPECT().GetPinnedByProductID(gomock.Any(), productID, gomock.Any()).Return(nil, nil)
+	mockDao.EXPECT().SetEx(gomock.Any(), "product:"+strconv.Itoa(productID)+":pinned_reviews", gomock.Any(), pinnedCacheTTL).Return(nil)
+
+	resp, err := svc.GetListByProductID(context.Background(), types.ListReviewRequest{ProductID: productID}, 0)
+	assert.NoError(t, err)
+	assert.Len(t, resp.ReviewList, 1)
+	top := resp.ReviewList[0]
+	assert.Equal(t, 1, top.ReplyCount)
+	assert.Len(t, top.Replies, 1)
+	assert.Equal(t, "r1-1", top.Replies[0].ID)
+	assert.Equal(t, 3, top.Replies[0].Likes)
+	assert.Len(t, top.Replies[0].Replies, 1)
+	deepest := top.Replies[0].Replies[0]
+	assert.Equal(t, "r1-1-1", deepest.ID)
+	assert.Equal(t, 4, deepest.ReplyCount)
+	assert.Empty(t, deepest.Replies)
+}
 
-	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return(oldID, nil)
-	mockDao.EXPECT().UpdateIsPinnedByID(gomock.Any(), oldID, false).Return(assert.AnError)
+func TestCreateReview_ReplyInheritsProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	err := svc.PinReview(context.Background(), reviewID)
-	assert.Error(t, err)
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	req := types.CreateReviewRequest{Content: "thanks", ParentID: "p1"}
+
+	mockDao.EXPECT().Get(gomock.Any(), "p1").Return(&model.Comment{ID: "p1", ProductID: 9}, nil)
+	mockDao.EXPECT().Save(gomock.Any(), gomock.AssignableToTypeOf(&model.Comment{})).DoAndReturn(
+		func(ctx context.Context, c *model.Comment) error {
+			assert.Equal(t, 9, c.ProductID)
+			assert.Equal(t, "p1", c.ParentID)
+			return nil
+		})
+	mockDao.EXPECT().Incr(gomock.Any(), "product:9:rating_version").Return(nil)
+
+	err := svc.CreateReview(context.Background(), req, 1)
+	assert.NoError(t, err)
 }
 
-func TestPinReview_UpdateNewFail(t *testing.T) {
+func TestCreateReview_ParentNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
 	svc := &ReviewServiceImpl{reviewDao: mockDao}
 
-	reviewID := "pnewfail"
-	productID := 11
-
-	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return("", nil)
-	mockDao.EXPECT().UpdateIsPinnedByID(gomock.Any(), reviewID, true).Return(assert.AnError)
+	mockDao.EXPECT().Get(gomock.Any(), "missing").Return(nil, dao.ErrNotFound)
 
-	err := svc.PinReview(context.Background(), reviewID)
-	assert.Error(t, err)
+	err := svc.CreateReview(context.Background(), types.CreateReviewRequest{ParentID: "missing", Content: "hi"}, 1)
+	assert.ErrorIs(t, err, ErrParentNotFound)
 }
 
-func TestPinReview_HSetFail(t *testing.T) {
+func TestCreateReview_ParentOtherProduct(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockDao := mocks.NewMockCommentDao(ctrl)
 	svc := &ReviewServiceImpl{reviewDao: mockDao}
 
-	reviewID := "phsetfail"
-	productID := 12
+	mockDao.EXPECT().Get(gomock.Any(), "p2").Return(&model.Comment{ID: "p2", ProductID: 1}, nil)
 
-	mockDao.EXPECT().Get(gomock.Any(), reviewID).Return(&model.Comment{ID: reviewID, ProductID: productID}, nil)
-	mockDao.EXPECT().HGet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID)).Return("", nil)
-	mockDao.EXPECT().UpdateIsPinnedByID(gomock.Any(), reviewID, true).Return(nil)
-	mockDao.EXPECT().HSet(gomock.Any(), pinnedReviewKey, strconv.Itoa(productID), reviewID).Return(assert.AnError)
+	err := svc.CreateReview(context.Background(), types.CreateReviewRequest{ParentID: "p2", ProductID: 2, Content: "hi"}, 1)
+	assert.ErrorIs(t, err, ErrParentProductMismatch)
+}
 
-	err := svc.PinReview(context.Background(), reviewID)
-	assert.Error(t, err)
+// ownerOf returns a resolver where merchantID owns the given products.
+func ownerOf(merchantID int, productIDs ...int) authz.ProductOwnershipResolver {
+	return authz.NewMemoryOwnershipResolver().SetOwner(merchantID, productIDs...)
+}
+
+func TestReviewKind(t *testing.T) {
+	assert.Equal(t, "review", reviewKind("0", false))
+	assert.Equal(t, "review", reviewKind("", false))
+	assert.Equal(t, "reply", reviewKind("r1", false))
+	assert.Equal(t, "merchant_reply", reviewKind("r1", true))
 }
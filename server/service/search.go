@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/validation"
+)
+
+// snippetContext is the number of runes kept on each side of the first match
+// of a snippet.
+const snippetContext = 60
+
+// searchPhrase matches a quoted phrase of a search query, negated when it
+// starts with a minus.
+var searchPhrase = regexp.MustCompile(`(-?)"([^"]*)"`)
+
+// SearchProductReviews searches the approved reviews of a product. userID is
+// the caller and only decides current_user_liked, 0 means anonymous.
+func (r *ReviewServiceImpl) SearchProductReviews(ctx context.Context, productID int, req types.SearchReviewRequest, userID int) (resp types.ListReviewResponse, err error) {
+	if err := validation.ValidateSearchReview(req, false); err != nil {
+		return types.ListReviewResponse{}, err
+	}
+	search := dao.TextSearch{Query: req.Query, ProductIDs: []int{productID}}
+	return r.searchReviews(ctx, search, req, userID)
+}
+
+// SearchMerchantReviews searches the reviews of the merchant's products in
+// every moderation status. Every product searched must be the merchant's.
+// The merchant is not a shopper, current_user_liked is false as for
+// anonymous callers.
+func (r *ReviewServiceImpl) SearchMerchantReviews(ctx context.Context, req types.SearchReviewRequest, merchantID int) (resp types.ListReviewResponse, err error) {
+	if err := validation.ValidateSearchReview(req, true); err != nil {
+		return types.ListReviewResponse{}, err
+	}
+	productIDs := make([]int, 0, len(req.ProductIDs))
+	seen := make(map[int]bool, len(req.ProductIDs))
+	for _, productID := range req.ProductIDs {
+		if seen[productID] {
+			continue
+		}
+		seen[productID] = true
+		if err := r.checkProductOwner(ctx, merchantID, productID); err != nil {
+			return types.ListReviewResponse{}, err
+		}
+		productIDs = append(productIDs, productID)
+	}
+	search := dao.TextSearch{Query: req.Query, ProductIDs: productIDs, AllStatuses: true}
+	return r.searchReviews(ctx, search, req, 0)
+}
+
+// searchReviews returns a page of search results enriched like the review
+// lists, each with a snippet of its content highlighting the matches.
+func (r *ReviewServiceImpl) searchReviews(ctx context.Context, search dao.TextSearch, req types.SearchReviewRequest, userID int) (resp types.ListReviewResponse, err error) {
+	page := dao.Page{Cursor: req.Cursor, Limit: req.Limit, Sort: req.Sort}
+	listRaw, nextCursor, err := r.reviewDao.SearchByText(ctx, search, page)
+	if err != nil {
+		return types.ListReviewResponse{}, err
+	}
+
+	list, err := r.buildReviewTree(ctx, listRaw, userID)
+	if err != nil {
+		return types.ListReviewResponse{}, err
+	}
+	terms := searchTerms(req.Query)
+	for idx := range list {
+		list[idx].Snippet = snippet(list[idx].Content, terms)
+	}
+
+	return types.ListReviewResponse{
+		ReviewList: list,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// searchTerms returns the lowercased phrases and terms a search query looks
+// for, negated ones are left out.
+func searchTerms(query string) []string {
+	var terms []string
+	for _, match := range searchPhrase.FindAllStringSubmatch(query, -1) {
+		phrase := strings.TrimSpace(match[2])
+		if match[1] == "" && phrase != "" {
+			terms = append(terms, strings.ToLower(phrase))
+		}
+	}
+	for _, word := range strings.Fields(searchPhrase.ReplaceAllString(query, " ")) {
+		if strings.HasPrefix(word, "-") {
+			continue
+		}
+		word = strings.TrimFunc(word, func(r rune) bool { return !isWordRune(r) })
+		if word != "" {
+			terms = append(terms, strings.ToLower(word))
+		}
+	}
+	return terms
+}
+
+// snippet returns an HTML escaped excerpt of content around the first match
+// of the terms, with the matches wrapped in <em>. Mongo matches stemmed words,
+// so a result may have no literal match, its snippet is then the start of the
+// content.
+func snippet(content string, terms []string) string {
+	text := []rune(content)
+	matches := findTerms(text, terms)
+
+	start, end := 0, min(len(text), 2*snippetContext)
+	if len(matches) > 0 {
+		start = max(0, matches[0][0]-snippetContext)
+		end = min(len(text), matches[0][1]+snippetContext)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, match := range matches {
+		if match[0] < start || match[1] > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(text[pos:match[0]])))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(string(text[match[0]:match[1]])))
+		b.WriteString("</em>")
+		pos = match[1]
+	}
+	b.WriteString(html.EscapeString(string(text[pos:end])))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// findTerms returns the [start, end) rune ranges of text where a term starts
+// a word, case insensitive, sorted and merged. A plural term also matches its
+// singular.
+func findTerms(text []rune, terms []string) [][2]int {
+	lower := make([]rune, len(text))
+	for idx, r := range text {
+		lower[idx] = unicode.ToLower(r)
+	}
+	var variants [][]rune
+	for _, term := range terms {
+		variants = append(variants, []rune(term))
+		if singular, ok := strings.CutSuffix(term, "s"); ok && len([]rune(singular)) >= 3 {
+			variants = append(variants, []rune(singular))
+		}
+	}
+
+	var matches [][2]int
+	for _, variant := range variants {
+		for idx := 0; idx+len(variant) <= len(lower); idx++ {
+			if idx > 0 && isWordRune(lower[idx-1]) {
+				continue
+			}
+			if string(lower[idx:idx+len(variant)]) == string(variant) {
+				matches = append(matches, [2]int{idx, idx + len(variant)})
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i][0] < matches[j][0] })
+
+	merged := matches[:0]
+	for _, match := range matches {
+		if last := len(merged) - 1; last >= 0 && match[0] <= merged[last][1] {
+			merged[last][1] = max(merged[last][1], match[1])
+			continue
+		}
+		merged = append(merged, match)
+	}
+	return merged
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
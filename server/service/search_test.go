@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/validation"
+)
+
+func TestSearchProductReviews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	req := types.SearchReviewRequest{Query: `glaze -crack`, Limit: 5, Cursor: "cur"}
+	cm := &model.Comment{ID: "s1", ProductID: 30, Content: "Lovely glaze on the rim", TextScore: 1.1}
+
+	mockDao.EXPECT().SearchByText(gomock.Any(),
+		dao.TextSearch{Query: `glaze -crack`, ProductIDs: []int{30}},
+		dao.Page{Cursor: "cur", Limit: 5}).
+		Return([]*model.Comment{cm}, "next", nil)
+	mockDao.EXPECT().GetListByParentIDs(gomock.Any(), []string{"s1"}).Return(nil, nil)
+	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, []string{"s1"}).Return(map[string]int{}, nil)
+
+	resp, err := svc.SearchProductReviews(context.Background(), 30, req, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "next", resp.NextCursor)
+	assert.Len(t, resp.ReviewList, 1)
+	assert.Equal(t, "Lovely <em>glaze</em> on the rim", resp.ReviewList[0].Snippet)
+}
+
+func TestSearchProductReviews_EmptyQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := &ReviewServiceImpl{reviewDao: mocks.NewMockCommentDao(ctrl)}
+
+	_, err := svc.SearchProductReviews(context.Background(), 30, types.SearchReviewRequest{Query: " "}, 0)
+	var fieldErrs validation.Errors
+	assert.ErrorAs(t, err, &fieldErrs)
+}
+
+func TestSearchMerchantReviews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(4, 31, 32)}
+
+	req := types.SearchReviewRequest{Query: `"too small"`, ProductIDs: []int{31, 32, 31}, Sort: dao.SortNewest}
+	cm := &model.Comment{ID: "s2", ProductID: 31, Content: "A bit too small", Status: model.StatusPending}
+
+	// every status is searched, each product once
+	mockDao.EXPECT().SearchByText(gomock.Any(),
+		dao.TextSearch{Query: `"too small"`, ProductIDs: []int{31, 32}, AllStatuses: true},
+		dao.Page{Sort: dao.SortNewest}).
+		Return([]*model.Comment{cm}, "", nil)
+	mockDao.EXPECT().GetListByParentIDs(gomock.Any(), []string{"s2"}).Return(nil, nil)
+	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, []string{"s2"}).Return(map[string]int{}, nil)
+	// the merchant's own likes are not looked up
+
+	resp, err := svc.SearchMerchantReviews(context.Background(), req, 4)
+	assert.NoError(t, err)
+	assert.Len(t, resp.ReviewList, 1)
+	assert.False(t, resp.ReviewList[0].CurrentUserLiked)
+}
+
+func TestSearchMerchantReviews_NotProductOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(4, 31)}
+
+	req := types.SearchReviewRequest{Query: "mug", ProductIDs: []int{31, 33}}
+
+	_, err := svc.SearchMerchantReviews(context.Background(), req, 4)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"hand made", "glaze", "mugs"},
+		searchTerms(`"Hand made" -"mass produced" Glaze, -crack mugs`))
+	assert.Empty(t, searchTerms(`-crack -"chipped rim"`))
+}
+
+func TestSnippet(t *testing.T) {
+	// matches are highlighted case insensitively, at word starts only
+	assert.Equal(t, "<em>Mugs</em> and a <em>mug</em> rack, smug",
+		snippet("Mugs and a mug rack, smug", []string{"mugs"}))
+	// the content is escaped
+	assert.Equal(t, "a &lt;b&gt; <em>glaze</em>", snippet("a <b> glaze", []string{"glaze"}))
+	// a phrase is highlighted as a whole
+	assert.Equal(t, "it is <em>too small</em>", snippet("it is too small", []string{"too small"}))
+
+	long := make([]rune, 0, 300)
+	for len(long) < 200 {
+		long = append(long, []rune("lorem ")...)
+	}
+	content := string(long) + "glaze" + string(long)
+	got := snippet(content, []string{"glaze"})
+	assert.Contains(t, got, "<em>glaze</em>")
+	assert.True(t, []rune(got)[0] == '…')
+	assert.Equal(t, 2*snippetContext+len("glaze")+len("<em></em>")+2, len([]rune(got)))
+
+	// words starting with a term are highlighted up to the term
+	assert.Equal(t, "<em>Fire</em>d twice", snippet("Fired twice", []string{"fire"}))
+	// no literal match, e.g. a stemmed one, keeps the start of the content
+	assert.Equal(t, "It was firing", snippet("It was firing", []string{"fired"}))
+}
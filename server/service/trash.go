@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/validation"
+)
+
+const purgeBatchSize = 100
+
+// ListTrash returns reviews and replies of one of the merchant's products that
+// were deleted and can still be restored, newest first.
+func (r *ReviewServiceImpl) ListTrash(ctx context.Context, req types.ListReviewRequest, merchantID int) (resp types.ListReviewResponse, err error) {
+	if err := validation.ValidateMerchantProduct(req.ProductID); err != nil {
+		return types.ListReviewResponse{}, err
+	}
+	if err := r.checkProductOwner(ctx, merchantID, req.ProductID); err != nil {
+		return types.ListReviewResponse{}, err
+	}
+
+	listRaw, nextCursor, err := r.reviewDao.GetListDeleted(ctx, req.ProductID, pageOf(req))
+	if err != nil {
+		return types.ListReviewResponse{}, err
+	}
+
+	list, err := r.buildReviewInfoList(ctx, listRaw, 0)
+	if err != nil {
+		return types.ListReviewResponse{}, err
+	}
+
+	return types.ListReviewResponse{
+		ReviewList: list,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// RestoreReview takes a deleted review of one of the merchant's products out
+// of the trash. It comes back with its likes but without its pin.
+func (r *ReviewServiceImpl) RestoreReview(ctx context.Context, reviewID string, merchantID int) (err error) {
+	commentRaw, err := r.reviewDao.Get(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if err := r.checkProductOwner(ctx, merchantID, commentRaw.ProductID); err != nil {
+		return err
+	}
+	if err := r.reviewDao.Restore(ctx, reviewID); err != nil {
+		return err
+	}
+	r.invalidateRatingSummary(ctx, commentRaw.ProductID)
+	if commentRaw.IsMerchantReply {
+		r.countMerchantReply(ctx, commentRaw.ParentID, 1)
+	}
+	return nil
+}
+
+// PurgeDeleted hard-deletes the reviews moved to the trash before the given
+// time, together with their replies, likes, like counters and like set
+// entries.
+func (r *ReviewServiceImpl) PurgeDeleted(ctx context.Context, before time.Time) (purged int, err error) {
+	for {
+		list, err := r.reviewDao.GetListDeletedBefore(ctx, before, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+		for _, commentRaw := range list {
+			if err := r.purgeReview(ctx, commentRaw); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(list) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// purgeReview purges the replies of the review, whatever their status, then
+// cleans up its likes before removing the document, so a failure leaves the
+// review in the trash for the next run.
+func (r *ReviewServiceImpl) purgeReview(ctx context.Context, commentRaw *model.Comment) error {
+	reviewID := commentRaw.ID
+	replies, err := r.reviewDao.GetAllReplies(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	for _, reply := range replies {
+		if err := r.purgeReview(ctx, reply); err != nil {
+			return err
+		}
+	}
+
+	reviewLikersKey := fmt.Sprintf(reviewLikersKeyFmt, reviewID)
+	likers, err := r.reviewDao.SMembers(ctx, reviewLikersKey)
+	if err != nil {
+		return err
+	}
+	for _, liker := range likers {
+		userID, err := strconv.Atoi(liker)
+		if err != nil {
+			log.Ctx(ctx).Warnf("purgeReview: bad liker %q of review %s", liker, reviewID)
+			continue
+		}
+		if err := r.reviewDao.SRem(ctx, fmt.Sprintf("user:%d:likes", userID), reviewID); err != nil {
+			return err
+		}
+	}
+	if err := r.reviewDao.Del(ctx, reviewLikersKey); err != nil {
+		return err
+	}
+	if err := r.reviewDao.HDel(ctx, reviewLikesCntKey, reviewID); err != nil {
+		return err
+	}
+	if _, err := r.likeDao.DeleteByReviewID(ctx, reviewID); err != nil {
+		return err
+	}
+
+	if err := r.reviewDao.Delete(ctx, reviewID); err != nil {
+		return err
+	}
+	metrics.Deletes.WithLabelValues("purge").Inc()
+	return nil
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/validation"
+)
+
+func TestDeleteReview_UnpinsDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(7, 4)}
+
+	mockDao.EXPECT().Get(gomock.Any(), "t1").Return(&model.Comment{ID: "t1", ProductID: 4, IsPinned: true}, nil)
+	mockDao.EXPECT().SoftDelete(gomock.Any(), "t1", 7, gomock.Any()).Return(nil)
+	mockDao.EXPECT().Incr(gomock.Any(), "product:4:rating_version").Return(nil)
+	mockDao.EXPECT().UpdateIsPinnedByID(gomock.Any(), "t1", false).Return(nil)
+	mockDao.EXPECT().Del(gomock.Any(), "product:4:pinned_reviews").Return(nil)
+
+	err := svc.DeleteReview(context.Background(), "t1", 7)
+	assert.NoError(t, err)
+}
+
+func TestListTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(7, 4)}
+
+	deletedAt := time.Now()
+	mockDao.EXPECT().GetListDeleted(gomock.Any(), 4, dao.Page{Limit: 5}).
+		Return([]*model.Comment{{ID: "t2", DeletedAt: &deletedAt, DeletedBy: 7}}, "", nil)
+	mockDao.EXPECT().HMGet(gomock.Any(), reviewLikesCntKey, []string{"t2"}).Return(map[string]int{"t2": 1}, nil)
+
+	resp, err := svc.ListTrash(context.Background(), types.ListReviewRequest{ProductID: 4, Limit: 5}, 7)
+	assert.NoError(t, err)
+	assert.Len(t, resp.ReviewList, 1)
+	assert.Equal(t, 7, resp.ReviewList[0].DeletedBy)
+	assert.NotNil(t, resp.ReviewList[0].DeletedAt)
+	assert.Equal(t, 1, resp.ReviewList[0].Likes)
+}
+
+func TestListTrash_OtherMerchant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// merchant 8 does not own product 4, the trash is not read
+	svc := &ReviewServiceImpl{reviewDao: mocks.NewMockCommentDao(ctrl), ownership: ownerOf(7, 4)}
+
+	_, err := svc.ListTrash(context.Background(), types.ListReviewRequest{ProductID: 4}, 8)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	_, err = svc.ListTrash(context.Background(), types.ListReviewRequest{}, 7)
+	var fieldErrs validation.Errors
+	assert.ErrorAs(t, err, &fieldErrs)
+}
+
+func TestRestoreReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 6)}
+
+	mockDao.EXPECT().Get(gomock.Any(), "t3").Return(&model.Comment{ID: "t3", ProductID: 6}, nil)
+	mockDao.EXPECT().Restore(gomock.Any(), "t3").Return(nil)
+	mockDao.EXPECT().Incr(gomock.Any(), "product:6:rating_version").Return(nil)
+
+	err := svc.RestoreReview(context.Background(), "t3", 9)
+	assert.NoError(t, err)
+}
+
+func TestDeleteReview_MerchantReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	reply := &model.Comment{ID: "t7", ParentID: "t8", ProductID: 6, UserID: 9, IsMerchantReply: true}
+	mockDao.EXPECT().Get(gomock.Any(), "t7").Return(reply, nil)
+	mockDao.EXPECT().SoftDelete(gomock.Any(), "t7", 9, gomock.Any()).Return(nil)
+	mockDao.EXPECT().Incr(gomock.Any(), "product:6:rating_version").Return(nil)
+	// the review no longer counts as replied by the merchant
+	mockDao.EXPECT().IncMerchantReplyCount(gomock.Any(), "t8", -1).Return(nil)
+
+	err := svc.DeleteOwnReview(context.Background(), "t7", 9)
+	assert.NoError(t, err)
+}
+
+func TestRestoreReview_MerchantReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 6)}
+
+	reply := &model.Comment{ID: "t7", ParentID: "t8", ProductID: 6, UserID: 9, IsMerchantReply: true}
+	mockDao.EXPECT().Get(gomock.Any(), "t7").Return(reply, nil)
+	mockDao.EXPECT().Restore(gomock.Any(), "t7").Return(nil)
+	mockDao.EXPECT().Incr(gomock.Any(), "product:6:rating_version").Return(nil)
+	mockDao.EXPECT().IncMerchantReplyCount(gomock.Any(), "t8", 1).Return(assert.AnError)
+
+	// a failed count does not fail the restore
+	err := svc.RestoreReview(context.Background(), "t7", 9)
+	assert.NoError(t, err)
+}
+
+func TestRestoreReview_NotInTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, ownership: ownerOf(9, 6)}
+
+	mockDao.EXPECT().Get(gomock.Any(), "t4").Return(&model.Comment{ID: "t4", ProductID: 6}, nil)
+	mockDao.EXPECT().Restore(gomock.Any(), "t4").Return(dao.ErrNotFound)
+
+	err := svc.RestoreReview(context.Background(), "t4", 9)
+	assert.ErrorIs(t, err, ErrReviewNotFound)
+}
+
+func TestPurgeDeleted_CleansRedis(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+	before := time.Now()
+	mockDao.EXPECT().GetListDeletedBefore(gomock.Any(), before, purgeBatchSize).
+		Return([]*model.Comment{{ID: "t5", ProductID: 2}}, nil)
+
+	gomock.InOrder(
+		mockDao.EXPECT().GetAllReplies(gomock.Any(), "t5").Return(nil, nil),
+		mockDao.EXPECT().SMembers(gomock.Any(), "review:t5:likers").Return([]string{"11", "12"}, nil),
+		mockDao.EXPECT().SRem(gomock.Any(), "user:11:likes", "t5").Return(nil),
+		mockDao.EXPECT().SRem(gomock.Any(), "user:12:likes", "t5").Return(nil),
+		mockDao.EXPECT().Del(gomock.Any(), "review:t5:likers").Return(nil),
+		mockDao.EXPECT().HDel(gomock.Any(), reviewLikesCntKey, "t5").Return(nil),
+		mockLikeDao.EXPECT().DeleteByReviewID(gomock.Any(), "t5").Return(2, nil),
+		mockDao.EXPECT().Delete(gomock.Any(), "t5").Return(nil),
+	)
+
+	purged, err := svc.PurgeDeleted(context.Background(), before)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+}
+
+func TestPurgeDeleted_StopsOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao}
+
+	mockDao.EXPECT().GetListDeletedBefore(gomock.Any(), gomock.Any(), purgeBatchSize).
+		Return([]*model.Comment{{ID: "t6", ProductID: 2}}, nil)
+	mockDao.EXPECT().GetAllReplies(gomock.Any(), "t6").Return(nil, nil)
+	mockDao.EXPECT().SMembers(gomock.Any(), "review:t6:likers").Return(nil, assert.AnError)
+
+	purged, err := svc.PurgeDeleted(context.Background(), time.Now())
+	assert.Error(t, err)
+	assert.Equal(t, 0, purged)
+}
+
+func TestPurgeDeleted_PurgesReplies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDao := mocks.NewMockCommentDao(ctrl)
+	mockLikeDao := mocks.NewMockLikeDao(ctrl)
+	svc := &ReviewServiceImpl{reviewDao: mockDao, likeDao: mockLikeDao}
+
+	mockDao.EXPECT().GetListDeletedBefore(gomock.Any(), gomock.Any(), purgeBatchSize).
+		Return([]*model.Comment{{ID: "t8", ProductID: 2}}, nil)
+	purgeOne := func(id string) *gomock.Call {
+		mockDao.EXPECT().SMembers(gomock.Any(), "review:"+id+":likers").Return(nil, nil)
+		mockDao.EXPECT().Del(gomock.Any(), "review:"+id+":likers").Return(nil)
+		mockDao.EXPECT().HDel(gomock.Any(), reviewLikesCntKey, id).Return(nil)
+		mockLikeDao.EXPECT().DeleteByReviewID(gomock.Any(), id).Return(0, nil)
+		return mockDao.EXPECT().Delete(gomock.Any(), id).Return(nil)
+	}
+	// the merchant reply and the nested reply go before the review they answer
+	mockDao.EXPECT().GetAllReplies(gomock.Any(), "t8").
+		Return([]*model.Comment{{ID: "t8-m", ParentID: "t8", IsMerchantReply: true}, {ID: "t8-1", ParentID: "t8"}}, nil)
+	mockDao.EXPECT().GetAllReplies(gomock.Any(), "t8-m").Return(nil, nil)
+	mockDao.EXPECT().GetAllReplies(gomock.Any(), "t8-1").Return([]*model.Comment{{ID: "t8-1-1", ParentID: "t8-1"}}, nil)
+	mockDao.EXPECT().GetAllReplies(gomock.Any(), "t8-1-1").Return(nil, nil)
+	gomock.InOrder(purgeOne("t8-m"), purgeOne("t8-1-1"), purgeOne("t8-1"), purgeOne("t8"))
+
+	purged, err := svc.PurgeDeleted(context.Background(), time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+}
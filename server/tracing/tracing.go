@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/log"
+)
+
+const (
+	// ServiceName names the service in traces.
+	ServiceName = "ceramicraft-comment-mservice"
+
+	instrumentationName = "github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server"
+)
+
+// Exporters of the tracing config.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// ErrUnknownExporter is returned for an exporter that is not one of the
+// Exporter constants.
+var ErrUnknownExporter = errors.New("unknown trace exporter")
+
+var provider *sdktrace.TracerProvider
+
+// Init installs the W3C trace context propagator and, unless the configured
+// exporter is none, a tracer provider exporting the spans. With a bad config
+// the error is logged and no spans are exported.
+func Init() {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	cfg := config.Config.TracingConfig
+	if cfg == nil || exporterName(cfg.Exporter) == ExporterNone {
+		return
+	}
+	exporter, err := newExporter(context.Background(), cfg)
+	if err != nil {
+		log.Logger.Errorf("tracing disabled, create %s exporter failed: %v", cfg.Exporter, err)
+		return
+	}
+	ratio := 1.0
+	if cfg.SampleRatio > 0 {
+		ratio = cfg.SampleRatio
+	}
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", ServiceName))),
+		// a sampled caller keeps its traces whole
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+	log.Logger.Infof("Tracing started, exporter %s, sample ratio %v", exporterName(cfg.Exporter), ratio)
+}
+
+// Shutdown exports the spans left and stops the tracer provider.
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+	if err := provider.Shutdown(ctx); err != nil {
+		log.Logger.Errorf("tracing shutdown failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Tracer returns the tracer of the service's own spans.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// IsExporter reports whether name is empty or one of the exporters.
+func IsExporter(name string) bool {
+	switch exporterName(name) {
+	case ExporterNone, ExporterOTLP, ExporterStdout:
+		return true
+	}
+	return false
+}
+
+// exporterName normalizes the configured exporter, empty means none.
+func exporterName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ExporterNone
+	}
+	return name
+}
+
+func newExporter(ctx context.Context, cfg *config.TracingConfig) (sdktrace.SpanExporter, error) {
+	switch exporterName(cfg.Exporter) {
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case ExporterStdout:
+		return stdouttrace.New()
+	default:
+		return nil, ErrUnknownExporter
+	}
+}
@@ -0,0 +1,26 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+)
+
+func TestIsExporter(t *testing.T) {
+	for _, name := range []string{"", "none", "otlp", "stdout", " OTLP "} {
+		assert.True(t, IsExporter(name), name)
+	}
+	assert.False(t, IsExporter("jaeger"))
+}
+
+func TestNewExporter(t *testing.T) {
+	exporter, err := newExporter(context.Background(), &config.TracingConfig{Exporter: "stdout"})
+	assert.NoError(t, err)
+	assert.NotNil(t, exporter)
+
+	_, err = newExporter(context.Background(), &config.TracingConfig{Exporter: "jaeger"})
+	assert.ErrorIs(t, err, ErrUnknownExporter)
+}
@@ -15,31 +15,132 @@ type LikeRequest struct {
 	ReviewID string `json:"review_id"`
 }
 
+// LikeDrift is a review whose like data in redis or like count differs from
+// the likes recorded in mongo.
+type LikeDrift struct {
+	ReviewID      string `json:"review_id"`
+	Count         int    `json:"count"`                    // likes recorded in mongo
+	CachedCount   int    `json:"cached_count"`             // counter in redis
+	StoredCount   int    `json:"stored_count"`             // like_count of the review in mongo
+	MissingLikers []int  `json:"missing_likers,omitempty"` // users whose like is missing in redis
+	ExtraLikers   []int  `json:"extra_likers,omitempty"`   // users whose like is only in redis
+}
+
+type LikeReconcileReport struct {
+	Reviews int         `json:"reviews"` // reviews with likes in mongo or redis
+	Likes   int         `json:"likes"`   // likes recorded in mongo
+	Drifts  []LikeDrift `json:"drifts"`
+	Fixed   bool        `json:"fixed"` // redis and the like counts were rewritten from the likes
+}
+
 type ReviewInfo struct {
-	ID               string    `json:"id"`
-	Content          string    `json:"content"`
-	UserID           int       `json:"user_id"`
-	ProductID        int       `json:"product_id"`
-	ParentID         string    `json:"parent_id"`
-	Stars            int       `json:"stars"`
-	IsAnonymous      bool      `json:"is_anonymous"`
-	PicInfo          []string  `json:"pic_info"`
-	CreatedAt        time.Time `json:"created_at"`
-	Likes            int       `json:"likes"`
-	CurrentUserLiked bool      `json:"current_user_liked"`
-	IsPinned         bool      `json:"is_pinned"`
+	ID               string       `json:"id"`
+	Content          string       `json:"content"`
+	UserID           int          `json:"user_id"`
+	ProductID        int          `json:"product_id"`
+	ParentID         string       `json:"parent_id"`
+	Stars            int          `json:"stars"`
+	IsAnonymous      bool         `json:"is_anonymous"`
+	PicInfo          []string     `json:"pic_info"`
+	CreatedAt        time.Time    `json:"created_at"`
+	Likes            int          `json:"likes"`
+	CurrentUserLiked bool         `json:"current_user_liked"`
+	UnhelpfulCount   int          `json:"unhelpful_count"`
+	HelpfulScore     float64      `json:"helpful_score"` // ranks the most helpful sort
+	IsMerchantReply  bool         `json:"is_merchant_reply,omitempty"`
+	Snippet          string       `json:"snippet,omitempty"` // search results only: HTML escaped excerpt, matches wrapped in <em>
+	IsPinned         bool         `json:"is_pinned"`
+	PinPosition      int          `json:"pin_position,omitempty"` // 1 based order among the product's pinned reviews
+	PinnedUntil      *time.Time   `json:"pinned_until,omitempty"` // the pin expires after this time
+	Status           string       `json:"status"`                 // pending, approved, rejected or hidden
+	ModerationReason string       `json:"moderation_reason,omitempty"`
+	DeletedAt        *time.Time   `json:"deleted_at,omitempty"` // only set for reviews in the trash
+	DeletedBy        int          `json:"deleted_by,omitempty"`
+	EditedAt         *time.Time   `json:"edited_at,omitempty"` // set once the author edited the review
+	ReplyCount       int          `json:"reply_count"`         // number of direct replies
+	Replies          []ReviewInfo `json:"replies,omitempty"`   // direct replies, oldest first, up to the configured depth
 }
 
 type PinReviewRequest struct {
-	IsPinned bool   `json:"is_pinned"`
+	IsPinned  bool       `json:"is_pinned"`  // false unpins the review
+	Position  int        `json:"position"`   // 1 based, 0 or past the end appends the review
+	ExpiresAt *time.Time `json:"expires_at"` // optional, the pin is dropped after this time
 }
 
 type ListReviewResponse struct {
-	ReviewList   []ReviewInfo `json:"review_list"`
-	PinnedReview *ReviewInfo  `json:"pinned_review"`
+	ReviewList    []ReviewInfo `json:"review_list"`
+	PinnedReview  *ReviewInfo  `json:"pinned_review"`  // first of pinned_reviews, kept for older clients
+	PinnedReviews []ReviewInfo `json:"pinned_reviews"` // in pin position order
+	NextCursor    string       `json:"next_cursor"`    // empty when there are no more pages
 }
 
 type ListReviewRequest struct {
-	ProductID int `json:"product_id"`
-	Stars     int `json:"stars"` // 0 means any stars
+	ProductID int    `json:"product_id" form:"product_id"` // required by the merchant review list
+	Stars     int    `json:"stars" form:"stars"`           // 0 means any stars
+	Limit     int    `json:"limit" form:"limit"`           // page size, 0 means default
+	Cursor    string `json:"cursor" form:"cursor"`         // next_cursor of the previous page
+	Sort      string `json:"sort" form:"sort"`             // newest (default), oldest, stars_desc, stars_asc, likes or helpful
+
+	// filters of the merchant review list, the other lists ignore them
+	MinStars    int        `json:"min_stars"`
+	MaxStars    int        `json:"max_stars"`
+	CreatedFrom *time.Time `json:"created_from"` // inclusive
+	CreatedTo   *time.Time `json:"created_to"`   // exclusive
+	HasPhotos   *bool      `json:"has_photos"`
+	IsAnonymous *bool      `json:"is_anonymous"`
+	HasReply    *bool      `json:"has_reply"` // has a reply of the merchant
+	Status      string     `json:"status"`    // moderation status, empty means approved
+	UserIDs     []int      `json:"user_ids"`
+	Keyword     string     `json:"keyword"` // case insensitive, matched in the content
+}
+
+// SearchReviewRequest searches the content of reviews. Query supports
+// "quoted phrases" and -negated terms.
+type SearchReviewRequest struct {
+	Query      string `json:"q" form:"q"`
+	ProductIDs []int  `json:"product_ids" form:"-"` // merchant search only, the merchant's products to search
+	Limit      int    `json:"limit" form:"limit"`   // page size, 0 means default
+	Cursor     string `json:"cursor" form:"cursor"` // next_cursor of the previous page
+	Sort       string `json:"sort" form:"sort"`     // relevance (default) or a sort of the review lists
+}
+
+type RatingSummary struct {
+	ProductID        int         `json:"product_id"`
+	TotalCount       int         `json:"total_count"`
+	AverageStars     float64     `json:"average_stars"`
+	StarHistogram    map[int]int `json:"star_histogram"` // stars (1-5) -> number of reviews
+	PhotoReviewCount int         `json:"photo_review_count"`
+	ReplyCount       int         `json:"reply_count"`
+}
+
+type ModerationQueueRequest struct {
+	ProductID int    `json:"product_id" form:"product_id"` // required, one of the merchant's products
+	Status    string `json:"status" form:"status"`         // pending when empty
+	Limit     int    `json:"limit" form:"limit"`
+	Cursor    string `json:"cursor" form:"cursor"`
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status"` // approved, rejected or hidden
+	Reason string `json:"reason"` // required unless the review is approved
+}
+
+// EditReviewRequest changes a review. Fields left out keep their value, an
+// empty pic_info removes all pictures.
+type EditReviewRequest struct {
+	Content *string   `json:"content"`
+	Stars   *int      `json:"stars"`
+	PicInfo *[]string `json:"pic_info"`
+}
+
+type ReviewRevision struct {
+	Content   string    `json:"content"`
+	Stars     int       `json:"stars"`
+	PicInfo   []string  `json:"pic_info"`
+	RevisedAt time.Time `json:"revised_at"` // when this version was replaced
+}
+
+type ReviewRevisionsResponse struct {
+	ReviewID  string           `json:"review_id"`
+	Revisions []ReviewRevision `json:"revisions"` // oldest first
 }
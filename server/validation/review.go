@@ -0,0 +1,257 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+)
+
+// Codes of field errors. They are part of the API and must not change.
+const (
+	CodeRequired       = "required"
+	CodeOutOfRange     = "out_of_range"
+	CodeTooLong        = "too_long"
+	CodeTooMany        = "too_many"
+	CodeInvalidURL     = "invalid_url"
+	CodeHostNotAllowed = "host_not_allowed"
+	CodeNotAllowed     = "not_allowed"
+	CodeInvalidType    = "invalid_type"
+	CodeMalformed      = "malformed"
+)
+
+const (
+	minStars                = 1
+	maxStars                = 5
+	defaultMaxContentLength = 2000
+	defaultMaxPictures      = 9
+	maxFilterUserIDs        = 100
+	maxKeywordLength        = 100
+	maxSearchQueryLength    = 200
+	maxSearchProducts       = 50
+)
+
+// JSON names of the validated fields, as sent by clients.
+const (
+	fieldProductID = "ProductID"
+	fieldContent   = "Content"
+	fieldStars     = "Stars"
+	fieldPicInfo   = "pic_info"
+)
+
+// FieldError describes why one field of a request was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors is the list of field errors of a rejected request.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for idx, fe := range e {
+		msgs[idx] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *Errors) add(field, code, message string) {
+	*e = append(*e, FieldError{Field: field, Code: code, Message: message})
+}
+
+func (e Errors) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ValidateCreateReview checks a new review or reply. Reviews need a product
+// and 1 to 5 stars, replies may leave the product to their thread and must
+// not carry stars.
+func ValidateCreateReview(req types.CreateReviewRequest) error {
+	var errs Errors
+	isReply := model.IsReplyParentID(req.ParentID)
+	if !isReply {
+		checkProductID(&errs, fieldProductID, req.ProductID)
+	}
+	checkContent(&errs, req.Content)
+	checkStars(&errs, req.Stars, isReply)
+	checkPictures(&errs, req.PicInfo)
+	return errs.orNil()
+}
+
+// ValidateEditReview checks the fields set in an edit of a review or reply.
+func ValidateEditReview(req types.EditReviewRequest, isReply bool) error {
+	var errs Errors
+	if req.Content != nil {
+		checkContent(&errs, *req.Content)
+	}
+	if req.Stars != nil {
+		checkStars(&errs, *req.Stars, isReply)
+	}
+	if req.PicInfo != nil {
+		checkPictures(&errs, *req.PicInfo)
+	}
+	return errs.orNil()
+}
+
+// ValidateReviewQuery checks the filters of the merchant review list. The
+// list is limited to one of the merchant's products, so it is required.
+func ValidateReviewQuery(req types.ListReviewRequest) error {
+	var errs Errors
+	checkProductID(&errs, "product_id", req.ProductID)
+	checkStarsFilter(&errs, "stars", req.Stars)
+	checkStarsFilter(&errs, "min_stars", req.MinStars)
+	checkStarsFilter(&errs, "max_stars", req.MaxStars)
+	if req.MinStars > 0 && req.MaxStars > 0 && req.MinStars > req.MaxStars {
+		errs.add("max_stars", CodeOutOfRange, "must not be less than min_stars")
+	}
+	if req.CreatedFrom != nil && req.CreatedTo != nil && !req.CreatedTo.After(*req.CreatedFrom) {
+		errs.add("created_to", CodeOutOfRange, "must be after created_from")
+	}
+	if req.Status != "" && !model.IsModerationStatus(req.Status) {
+		errs.add("status", CodeNotAllowed, "must be pending, approved, rejected or hidden")
+	}
+	if len(req.UserIDs) > maxFilterUserIDs {
+		errs.add("user_ids", CodeTooMany, "has too many users")
+	}
+	if utf8.RuneCountInString(req.Keyword) > maxKeywordLength {
+		errs.add("keyword", CodeTooLong, "is too long")
+	}
+	return errs.orNil()
+}
+
+// ValidateMerchantProduct checks the product a merchant list is limited to.
+// Merchants only see the reviews of their own products, so it is required.
+func ValidateMerchantProduct(productID int) error {
+	var errs Errors
+	checkProductID(&errs, "product_id", productID)
+	return errs.orNil()
+}
+
+// ValidateSearchReview checks a search request. Merchant searches name the
+// products to search, customer searches take it from the path.
+func ValidateSearchReview(req types.SearchReviewRequest, isMerchant bool) error {
+	var errs Errors
+	if strings.TrimSpace(req.Query) == "" {
+		errs.add("q", CodeRequired, "must not be empty")
+	} else if utf8.RuneCountInString(req.Query) > maxSearchQueryLength {
+		errs.add("q", CodeTooLong, "is too long")
+	}
+	if isMerchant {
+		if len(req.ProductIDs) == 0 {
+			errs.add("product_ids", CodeRequired, "must not be empty")
+		} else if len(req.ProductIDs) > maxSearchProducts {
+			errs.add("product_ids", CodeTooMany, "has too many products")
+		}
+	}
+	return errs.orNil()
+}
+
+// FromBindError turns an error of decoding a request body into field errors.
+func FromBindError(err error) Errors {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return Errors{{Field: typeErr.Field, Code: CodeInvalidType, Message: "must be a " + typeErr.Type.String()}}
+	}
+	return Errors{{Code: CodeMalformed, Message: err.Error()}}
+}
+
+// checkProductID requires a product, 0 stands for every product in the list
+// filters.
+func checkProductID(errs *Errors, field string, productID int) {
+	if productID == 0 {
+		errs.add(field, CodeRequired, "is required")
+	} else if productID < 0 {
+		errs.add(field, CodeOutOfRange, "must be positive")
+	}
+}
+
+func checkContent(errs *Errors, content string) {
+	if strings.TrimSpace(content) == "" {
+		errs.add(fieldContent, CodeRequired, "must not be empty")
+		return
+	}
+	if utf8.RuneCountInString(content) > maxContentLength() {
+		errs.add(fieldContent, CodeTooLong, "is too long")
+	}
+}
+
+func checkStars(errs *Errors, stars int, isReply bool) {
+	if isReply {
+		if stars != 0 {
+			errs.add(fieldStars, CodeNotAllowed, "replies must not carry stars")
+		}
+		return
+	}
+	if stars < minStars || stars > maxStars {
+		errs.add(fieldStars, CodeOutOfRange, "must be between 1 and 5")
+	}
+}
+
+// checkStarsFilter checks a stars filter, 0 means any stars.
+func checkStarsFilter(errs *Errors, field string, stars int) {
+	if stars != 0 && (stars < minStars || stars > maxStars) {
+		errs.add(field, CodeOutOfRange, "must be between 1 and 5")
+	}
+}
+
+func checkPictures(errs *Errors, picInfo []string) {
+	if len(picInfo) > maxPictures() {
+		errs.add(fieldPicInfo, CodeTooMany, "has too many pictures")
+		return
+	}
+	for _, raw := range picInfo {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.add(fieldPicInfo, CodeInvalidURL, "must be http(s) URLs")
+			return
+		}
+		if !hostAllowed(u.Hostname()) {
+			errs.add(fieldPicInfo, CodeHostNotAllowed, "host "+u.Hostname()+" is not allowed")
+			return
+		}
+	}
+}
+
+// hostAllowed reports whether pictures may be served from host. Hosts match
+// an allowlist entry exactly or as a subdomain. An empty allowlist allows any host.
+func hostAllowed(host string) bool {
+	var allowed []string
+	if config.Config.ReviewConfig != nil {
+		allowed = config.Config.ReviewConfig.PictureHosts
+	}
+	if len(allowed) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, entry := range allowed {
+		entry = strings.ToLower(entry)
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+func maxContentLength() int {
+	if config.Config.ReviewConfig != nil && config.Config.ReviewConfig.MaxContentLength > 0 {
+		return config.Config.ReviewConfig.MaxContentLength
+	}
+	return defaultMaxContentLength
+}
+
+func maxPictures() int {
+	if config.Config.ReviewConfig != nil && config.Config.ReviewConfig.MaxPictures > 0 {
+		return config.Config.ReviewConfig.MaxPictures
+	}
+	return defaultMaxPictures
+}
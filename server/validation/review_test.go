@@ -0,0 +1,169 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-comment-mservice/server/types"
+)
+
+func codesOf(err error) map[string]string {
+	codes := map[string]string{}
+	var errs Errors
+	if errors.As(err, &errs) {
+		for _, fe := range errs {
+			codes[fe.Field] = fe.Code
+		}
+	}
+	return codes
+}
+
+func TestValidateCreateReview(t *testing.T) {
+	cases := []struct {
+		name  string
+		req   types.CreateReviewRequest
+		codes map[string]string
+	}{
+		{
+			name:  "valid review",
+			req:   types.CreateReviewRequest{ProductID: 1, Content: "nice mug", Stars: 5, PicInfo: []string{"https://cdn.example.com/a.jpg"}},
+			codes: map[string]string{},
+		},
+		{
+			name:  "valid reply",
+			req:   types.CreateReviewRequest{Content: "thank you", ParentID: "abc"},
+			codes: map[string]string{},
+		},
+		{
+			name:  "stars out of range and empty content",
+			req:   types.CreateReviewRequest{ProductID: 1, Content: "  ", Stars: 99},
+			codes: map[string]string{fieldContent: CodeRequired, fieldStars: CodeOutOfRange},
+		},
+		{
+			name:  "zero stars on a review",
+			req:   types.CreateReviewRequest{ProductID: 1, Content: "meh"},
+			codes: map[string]string{fieldStars: CodeOutOfRange},
+		},
+		{
+			name:  "review without product",
+			req:   types.CreateReviewRequest{Content: "nice mug", Stars: 5},
+			codes: map[string]string{fieldProductID: CodeRequired},
+		},
+		{
+			name:  "review with negative product",
+			req:   types.CreateReviewRequest{ProductID: -3, Content: "nice mug", Stars: 5},
+			codes: map[string]string{fieldProductID: CodeOutOfRange},
+		},
+		{
+			name:  "reply with stars",
+			req:   types.CreateReviewRequest{Content: "thanks", ParentID: "abc", Stars: 5},
+			codes: map[string]string{fieldStars: CodeNotAllowed},
+		},
+		{
+			name:  "content too long",
+			req:   types.CreateReviewRequest{ProductID: 1, Content: strings.Repeat("陶", defaultMaxContentLength+1), Stars: 4},
+			codes: map[string]string{fieldContent: CodeTooLong},
+		},
+		{
+			name:  "too many pictures",
+			req:   types.CreateReviewRequest{ProductID: 1, Content: "ok", Stars: 4, PicInfo: make([]string, defaultMaxPictures+1)},
+			codes: map[string]string{fieldPicInfo: CodeTooMany},
+		},
+		{
+			name:  "picture is not a url",
+			req:   types.CreateReviewRequest{ProductID: 1, Content: "ok", Stars: 4, PicInfo: []string{"a.jpg"}},
+			codes: map[string]string{fieldPicInfo: CodeInvalidURL},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateCreateReview(tc.req)
+			assert.Equal(t, tc.codes, codesOf(err))
+			if len(tc.codes) == 0 {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateCreateReview_PictureHosts(t *testing.T) {
+	old := config.Config.ReviewConfig
+	config.Config.ReviewConfig = &config.ReviewConfig{PictureHosts: []string{"example.com"}}
+	defer func() { config.Config.ReviewConfig = old }()
+
+	req := types.CreateReviewRequest{ProductID: 1, Content: "ok", Stars: 4, PicInfo: []string{"https://cdn.example.com/a.jpg"}}
+	assert.NoError(t, ValidateCreateReview(req))
+
+	req.PicInfo = []string{"https://evil.test/a.jpg"}
+	assert.Equal(t, map[string]string{fieldPicInfo: CodeHostNotAllowed}, codesOf(ValidateCreateReview(req)))
+}
+
+func TestValidateEditReview(t *testing.T) {
+	stars := 6
+	empty := ""
+	assert.NoError(t, ValidateEditReview(types.EditReviewRequest{}, false))
+	assert.Equal(t, map[string]string{fieldStars: CodeOutOfRange, fieldContent: CodeRequired},
+		codesOf(ValidateEditReview(types.EditReviewRequest{Stars: &stars, Content: &empty}, false)))
+
+	one := 1
+	assert.Equal(t, map[string]string{fieldStars: CodeNotAllowed},
+		codesOf(ValidateEditReview(types.EditReviewRequest{Stars: &one}, true)))
+}
+
+func TestValidateReviewQuery(t *testing.T) {
+	assert.NoError(t, ValidateReviewQuery(types.ListReviewRequest{ProductID: 1}))
+	assert.NoError(t, ValidateReviewQuery(types.ListReviewRequest{ProductID: 1, MinStars: 2, MaxStars: 2, Status: "hidden"}))
+
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	assert.Equal(t, map[string]string{
+		"product_id": CodeOutOfRange,
+		"stars":      CodeOutOfRange,
+		"max_stars":  CodeOutOfRange,
+		"created_to": CodeOutOfRange,
+		"status":     CodeNotAllowed,
+		"user_ids":   CodeTooMany,
+		"keyword":    CodeTooLong,
+	}, codesOf(ValidateReviewQuery(types.ListReviewRequest{
+		ProductID:   -1,
+		Stars:       6,
+		MinStars:    4,
+		MaxStars:    2,
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		Status:      "deleted",
+		UserIDs:     make([]int, maxFilterUserIDs+1),
+		Keyword:     strings.Repeat("k", maxKeywordLength+1),
+	})))
+}
+
+func TestValidateSearchReview(t *testing.T) {
+	assert.NoError(t, ValidateSearchReview(types.SearchReviewRequest{Query: `"glaze" -crack`}, false))
+	assert.NoError(t, ValidateSearchReview(types.SearchReviewRequest{Query: "glaze", ProductIDs: []int{1}}, true))
+
+	assert.Equal(t, map[string]string{"q": CodeRequired, "product_ids": CodeRequired},
+		codesOf(ValidateSearchReview(types.SearchReviewRequest{Query: "  "}, true)))
+	assert.Equal(t, map[string]string{"q": CodeTooLong, "product_ids": CodeTooMany},
+		codesOf(ValidateSearchReview(types.SearchReviewRequest{
+			Query:      strings.Repeat("q", maxSearchQueryLength+1),
+			ProductIDs: make([]int, maxSearchProducts+1),
+		}, true)))
+}
+
+func TestFromBindError(t *testing.T) {
+	var req types.CreateReviewRequest
+	err := json.Unmarshal([]byte(`{"Stars": "five"}`), &req)
+	errs := FromBindError(err)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "Stars", errs[0].Field)
+	assert.Equal(t, CodeInvalidType, errs[0].Code)
+
+	errs = FromBindError(json.Unmarshal([]byte(`{`), &req))
+	assert.Equal(t, CodeMalformed, errs[0].Code)
+}